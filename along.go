@@ -0,0 +1,65 @@
+package polyline
+
+import "math"
+
+// PointAtDistance returns the point meters along the polyline buf by
+// great-circle distance, interpolated within the segment containing it,
+// computed while decoding without storing the coordinates, for example to
+// place a vehicle or an ETA marker on a route. Distances are clamped to the
+// length of the polyline. It returns ErrEmpty if buf contains no
+// coordinates.
+func (c Codec) PointAtDistance(buf []byte, meters float64) ([]float64, error) {
+	return c.PointAtDistanceWith(buf, meters, Haversine)
+}
+
+// PointAtDistanceWith returns the point meters along the polyline buf as
+// PointAtDistance does, with distances measured with model. A nil model
+// means Haversine.
+func (c Codec) PointAtDistanceWith(buf []byte, meters float64, model DistanceModel) ([]float64, error) {
+	if model == nil {
+		model = Haversine
+	}
+	var point []float64
+	var along float64
+	err := c.walk(buf, func(i int, prev, coord []float64) {
+		switch {
+		case point != nil && along >= meters:
+			return
+		case prev == nil:
+			point = cloneCoord(coord)
+			return
+		}
+		d := model.Distance(prev, coord)
+		if along+d >= meters && d > 0 {
+			point = interpolate(prev, coord, math.Max(0, meters-along)/d)
+		} else {
+			point = cloneCoord(coord)
+		}
+		along += d
+	})
+	if err != nil {
+		return nil, err
+	}
+	if point == nil {
+		return nil, ErrEmpty
+	}
+	return point, nil
+}
+
+// PointAtFraction returns the point at fraction of the length of the
+// polyline buf, clamped to [0, 1], as PointAtDistance does. It decodes buf
+// twice, once to measure it.
+func (c Codec) PointAtFraction(buf []byte, fraction float64) ([]float64, error) {
+	return c.PointAtFractionWith(buf, fraction, Haversine)
+}
+
+// PointAtFractionWith returns the point at fraction of the length of the
+// polyline buf as PointAtFraction does, with distances measured with model.
+// A nil model means Haversine.
+func (c Codec) PointAtFractionWith(buf []byte, fraction float64, model DistanceModel) ([]float64, error) {
+	length, err := c.LengthWith(buf, model)
+	if err != nil {
+		return nil, err
+	}
+	return c.PointAtDistanceWith(buf, math.Max(0, math.Min(1, fraction))*length, model)
+}
@@ -0,0 +1,60 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecPointAtDistance(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	coords := [][]float64{{0, 0}, {0.003, 0}, {0.003, 0}, {0.004, 0}, {0.01, 0}}
+	buf := codec.EncodeCoords(nil, coords)
+	length, err := codec.Length(buf)
+	assert.NoError(t, err)
+
+	for i, expected := range polyline.Waypoints(coords, 5) {
+		point, err := codec.PointAtDistance(buf, length*float64(i)/4)
+		assert.NoError(t, err)
+		assert.True(t, float64ArrayWithin(expected, point, 1e-9), "want %v, got %v", expected, point)
+
+		point, err = codec.PointAtFraction(buf, float64(i)/4)
+		assert.NoError(t, err)
+		assert.True(t, float64ArrayWithin(expected, point, 1e-9), "want %v, got %v", expected, point)
+	}
+
+	for _, tc := range []struct {
+		meters   float64
+		expected []float64
+	}{
+		{meters: -1, expected: []float64{0, 0}},
+		{meters: 2 * length, expected: []float64{0.01, 0}},
+	} {
+		point, err := codec.PointAtDistance(buf, tc.meters)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, point)
+	}
+
+	point, err := codec.PointAtFraction(buf, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.01, 0}, point)
+
+	for _, meters := range []float64{0.3 * length, 0.8 * length} {
+		want, err := codec.PointAtDistance(buf, meters)
+		assert.NoError(t, err)
+		point, err := codec.PointAtDistanceWith(buf, 2*meters, doubledModel{})
+		assert.NoError(t, err)
+		assert.True(t, float64ArrayWithin(want, point, 1e-9), "want %v, got %v", want, point)
+	}
+
+	point, err = codec.PointAtDistance(codec.EncodeCoords(nil, [][]float64{{1, 2}}), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2}, point)
+
+	_, err = codec.PointAtDistance(nil, 10)
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+	_, err = codec.PointAtFraction([]byte("_p~iF~ps|U_"), 0.5)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
@@ -0,0 +1,105 @@
+package polyline
+
+import "fmt"
+
+// Annotations holds per-segment attributes of a route, as returned by the
+// annotations of OSRM and Valhalla routing responses. Each non-nil array must
+// have one entry per segment, that is one fewer than the route's points.
+type Annotations struct {
+	Distance    []float64 // Distance in meters
+	Duration    []float64 // Duration in seconds
+	Speed       []float64 // Speed in meters per second
+	Weight      []float64 // Routing weight
+	Datasources []int     // Index of the data source of the speed
+}
+
+// An AnnotatedSegment is a segment of a route with its annotations. Fields
+// whose annotation array is nil are zero.
+type AnnotatedSegment struct {
+	Index      int
+	From, To   []float64
+	Distance   float64
+	Duration   float64
+	Speed      float64
+	Weight     float64
+	Datasource int
+}
+
+// A SegmentIterator iterates over the annotated segments of a route.
+type SegmentIterator struct {
+	coords [][]float64
+	a      Annotations
+	i      int
+}
+
+// AlignAnnotations returns an iterator over the segments of coords and their
+// annotations from a. It returns ErrDimensionalMismatch if an annotation
+// array does not have one entry per segment.
+func AlignAnnotations(coords [][]float64, a Annotations) (*SegmentIterator, error) {
+	segments := len(coords) - 1
+	if segments < 0 {
+		segments = 0
+	}
+	for _, array := range []struct {
+		name string
+		n    int
+		set  bool
+	}{
+		{"distance", len(a.Distance), a.Distance != nil},
+		{"duration", len(a.Duration), a.Duration != nil},
+		{"speed", len(a.Speed), a.Speed != nil},
+		{"weight", len(a.Weight), a.Weight != nil},
+		{"datasources", len(a.Datasources), a.Datasources != nil},
+	} {
+		if array.set && array.n != segments {
+			return nil, fmt.Errorf("%w: %d %s annotations for %d segments", ErrDimensionalMismatch, array.n, array.name, segments)
+		}
+	}
+	return &SegmentIterator{coords: coords, a: a}, nil
+}
+
+// AlignAnnotations decodes buf and returns an iterator over its segments and
+// their annotations from a.
+func (c Codec) AlignAnnotations(buf []byte, a Annotations) (*SegmentIterator, error) {
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	return AlignAnnotations(coords, a)
+}
+
+// Next advances it to the next segment, which is then available from
+// Segment. It returns false after the last segment.
+func (it *SegmentIterator) Next() bool {
+	if it.i+1 >= len(it.coords) {
+		return false
+	}
+	it.i++
+	return true
+}
+
+// Segment returns the current segment.
+func (it *SegmentIterator) Segment() AnnotatedSegment {
+	i := it.i - 1
+	s := AnnotatedSegment{
+		Index: i,
+		From:  it.coords[i],
+		To:    it.coords[i+1],
+	}
+	if it.a.Distance != nil {
+		s.Distance = it.a.Distance[i]
+	}
+	if it.a.Duration != nil {
+		s.Duration = it.a.Duration[i]
+	}
+	if it.a.Speed != nil {
+		s.Speed = it.a.Speed[i]
+	}
+	if it.a.Weight != nil {
+		s.Weight = it.a.Weight[i]
+	}
+	if it.a.Datasources != nil {
+		s.Datasource = it.a.Datasources[i]
+	}
+	return s
+}
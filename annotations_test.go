@@ -0,0 +1,40 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlignAnnotations(t *testing.T) {
+	t.Parallel()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	a := polyline.Annotations{
+		Duration:    []float64{8700, 18000},
+		Speed:       []float64{29.2, 29.7},
+		Datasources: []int{0, 1},
+	}
+	it, err := polyline.DefaultCodec().AlignAnnotations(buf, a)
+	assert.NoError(t, err)
+	var got []polyline.AnnotatedSegment
+	for it.Next() {
+		got = append(got, it.Segment())
+	}
+	assert.Equal(t, []polyline.AnnotatedSegment{
+		{Index: 0, From: []float64{38.5, -120.2}, To: []float64{40.7, -120.95}, Duration: 8700, Speed: 29.2},
+		{Index: 1, From: []float64{40.7, -120.95}, To: []float64{43.252, -126.453}, Duration: 18000, Speed: 29.7, Datasource: 1},
+	}, got)
+	assert.False(t, it.Next())
+
+	it, err = polyline.AlignAnnotations(nil, polyline.Annotations{})
+	assert.NoError(t, err)
+	assert.False(t, it.Next())
+
+	_, err = polyline.DefaultCodec().AlignAnnotations(buf, polyline.Annotations{Distance: []float64{1, 2, 3}})
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+	assert.EqualError(t, err, "dimensional mismatch: 3 distance annotations for 2 segments")
+
+	_, err = polyline.DefaultCodec().AlignAnnotations([]byte("_"), a)
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package polyline
+
+import "math"
+
+// Bearings returns the initial great-circle bearing of each segment of
+// coords, in degrees clockwise from north in the range [0, 360), for
+// example to orient arrows along a route. Bearings are computed on the
+// sphere, so segments across the antimeridian point the short way round.
+// The bearing of a segment whose ends coincide is zero.
+func Bearings(coords [][]float64) []float64 {
+	if len(coords) < 2 {
+		return nil
+	}
+	result := make([]float64, len(coords)-1)
+	for i := range result {
+		result[i] = bearing(coords[i], coords[i+1])
+	}
+	return result
+}
+
+// BearingAt returns the heading of the path coords at fraction of its
+// length, in degrees clockwise from north in the range [0, 360): the
+// bearing at that point along the great circle of the segment containing
+// it. fraction is clamped to [0, 1]; at 1 the result is the final bearing of
+// the last segment. It returns false if coords has no length.
+func BearingAt(coords [][]float64, fraction float64) (float64, bool) {
+	if len(coords) < 2 {
+		return 0, false
+	}
+	cum := cumulativeDistances(coords)
+	total := cum[len(cum)-1]
+	if total == 0 {
+		return 0, false
+	}
+	fraction = math.Max(0, math.Min(1, fraction))
+	p, i := pointAtDistance(coords, cum, fraction*total)
+	// Skip zero-length segments, forwards and then, at the end of the path,
+	// backwards.
+	for i < len(cum)-2 && cum[i+1] == cum[i] {
+		i++
+	}
+	for cum[i+1] == cum[i] {
+		i--
+	}
+	end := coords[i+1]
+	if haversine(p, end) == 0 {
+		return math.Mod(bearing(end, coords[i])+180, 360), true
+	}
+	return bearing(p, end), true
+}
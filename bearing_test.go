@@ -0,0 +1,52 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBearings(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {1, 0}, {1, 1}, {1, 1}, {0, 1}, {0, 179.5}, {0, -179.5}}
+	bearings := polyline.Bearings(coords)
+	assert.Len(t, bearings, 6)
+	assert.InDeltaSlice(t, []float64{0, 89.99, 0, 180, 90, 90}, bearings, 0.01)
+	assert.Nil(t, polyline.Bearings(coords[:1]))
+}
+
+func TestBearingAt(t *testing.T) {
+	t.Parallel()
+	// A repeated point, then north, then east.
+	coords := [][]float64{{0, 0}, {0, 0}, {1, 0}, {1, 1}}
+	for _, tc := range []struct {
+		fraction float64
+		expected float64
+	}{
+		{fraction: -1, expected: 0},
+		{fraction: 0, expected: 0},
+		{fraction: 0.25, expected: 0},
+		{fraction: 0.75, expected: 89.996},
+		{fraction: 1, expected: 90.009},
+		{fraction: 2, expected: 90.009},
+	} {
+		b, ok := polyline.BearingAt(coords, tc.fraction)
+		assert.True(t, ok)
+		assert.InDelta(t, tc.expected, b, 0.001, "fraction %g", tc.fraction)
+	}
+
+	b, ok := polyline.BearingAt([][]float64{{0, 179.5}, {0, -179.5}}, 0.5)
+	assert.True(t, ok)
+	assert.InDelta(t, 90, b, 1e-9)
+
+	// A path ending in a repeated vertex, as GPS traces often do.
+	b, ok = polyline.BearingAt([][]float64{{0, 0}, {1, 0}, {1, 0}}, 1)
+	assert.True(t, ok)
+	assert.InDelta(t, 0, b, 1e-9)
+
+	_, ok = polyline.BearingAt(coords[:1], 0.5)
+	assert.False(t, ok)
+	_, ok = polyline.BearingAt(coords[:2], 0.5)
+	assert.False(t, ok)
+}
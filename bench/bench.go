@@ -0,0 +1,169 @@
+// Package bench measures the performance of package polyline on
+// user-supplied datasets, for capacity planning without forking the
+// repository's benchmarks.
+package bench
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// A Dataset is a named set of polylines.
+type Dataset struct {
+	Name      string
+	Polylines [][][]float64
+}
+
+// Options configures RunProfile. Zero fields take default values.
+type Options struct {
+	Codec       polyline.Codec // Codec, default Dim 2 and Scale 1e5
+	Tolerance   float64        // Simplification tolerance, default 1e-4
+	MinDuration time.Duration  // Minimum duration of each measurement, default 1s
+}
+
+// A Measurement is the cost of an operation.
+type Measurement struct {
+	NsPerPoint         float64 // Time per point in nanoseconds
+	AllocsPerPoint     float64 // Heap allocations per point
+	AllocBytesPerPoint float64 // Heap allocated bytes per point
+}
+
+// A Profile is the result of RunProfile.
+type Profile struct {
+	Dataset       string
+	Points        int         // Total number of points in the dataset
+	BytesPerPoint float64     // Encoded bytes per point
+	Encode        Measurement // Cost of encoding
+	Decode        Measurement // Cost of decoding
+	Simplify      Measurement // Cost of simplifying; zero if the codec has fewer than two dimensions
+}
+
+// String returns a human readable table of p.
+func (p Profile) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "dataset %s: %d points, %.2f bytes/point\n", p.Dataset, p.Points, p.BytesPerPoint)
+	fmt.Fprintf(&sb, "%-10s %12s %12s %12s\n", "operation", "ns/point", "allocs/point", "B/point")
+	for _, row := range []struct {
+		name string
+		m    Measurement
+	}{
+		{"encode", p.Encode},
+		{"decode", p.Decode},
+		{"simplify", p.Simplify},
+	} {
+		fmt.Fprintf(&sb, "%-10s %12.2f %12.4f %12.2f\n", row.name, row.m.NsPerPoint, row.m.AllocsPerPoint, row.m.AllocBytesPerPoint)
+	}
+	return sb.String()
+}
+
+// measure runs f repeatedly for at least minDuration and returns its cost per
+// point, where each run processes points points.
+func measure(points int, minDuration time.Duration, f func()) Measurement {
+	start := time.Now()
+	runs := 0
+	for runs == 0 || time.Since(start) < minDuration {
+		f()
+		runs++
+	}
+	elapsed := time.Since(start)
+
+	n := float64(runs) * float64(points)
+	if n == 0 {
+		return Measurement{}
+	}
+	allocs, bytes := allocsPerRun(f)
+	return Measurement{
+		NsPerPoint:         float64(elapsed.Nanoseconds()) / n,
+		AllocsPerPoint:     allocs / float64(points),
+		AllocBytesPerPoint: bytes / float64(points),
+	}
+}
+
+// allocsPerRun returns the number of heap allocations and allocated bytes of
+// a run of f. Like testing.AllocsPerRun, which panics in parallel tests, it
+// measures a single run after a warm-up with GOMAXPROCS set to one, so that
+// allocations by goroutines running in parallel are not counted.
+func allocsPerRun(f func()) (allocs, bytes float64) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	f()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return float64(after.Mallocs - before.Mallocs), float64(after.TotalAlloc - before.TotalAlloc)
+}
+
+// RunProfile measures encoding, decoding, and simplifying ds. It returns
+// polyline.ErrDimensionalMismatch if a coordinate of ds does not have as many
+// values as the codec has dimensions. Simplifying needs two dimensions, so
+// it is skipped for codecs with fewer.
+//
+// RunProfile sets GOMAXPROCS to one while it counts allocations, so it must
+// not run alongside other work in the same process.
+func RunProfile(ds Dataset, opts Options) (Profile, error) {
+	if opts.Codec.Dim == 0 {
+		opts.Codec = polyline.DefaultCodec()
+	}
+	for i, coords := range ds.Polylines {
+		for j, coord := range coords {
+			if len(coord) != opts.Codec.Dim {
+				return Profile{}, fmt.Errorf("%w: polyline %d coordinate %d has %d values", polyline.ErrDimensionalMismatch, i, j, len(coord))
+			}
+		}
+	}
+	if opts.Tolerance == 0 {
+		opts.Tolerance = 1e-4
+	}
+	if opts.MinDuration == 0 {
+		opts.MinDuration = time.Second
+	}
+
+	profile := Profile{Dataset: ds.Name}
+	encoded := make([][]byte, len(ds.Polylines))
+	points := make([][]polyline.Point, len(ds.Polylines))
+	var bytes int
+	for i, coords := range ds.Polylines {
+		profile.Points += len(coords)
+		encoded[i] = opts.Codec.EncodeCoords(nil, coords)
+		bytes += len(encoded[i])
+		if opts.Codec.Dim < 2 {
+			continue
+		}
+		points[i] = make([]polyline.Point, len(coords))
+		for j, coord := range coords {
+			points[i][j] = polyline.ChartPoint{X: coord[0], Y: coord[1]}
+		}
+	}
+	if profile.Points > 0 {
+		profile.BytesPerPoint = float64(bytes) / float64(profile.Points)
+	}
+
+	var err error
+	profile.Encode = measure(profile.Points, opts.MinDuration, func() {
+		for _, coords := range ds.Polylines {
+			_ = opts.Codec.EncodeCoords(nil, coords)
+		}
+	})
+	profile.Decode = measure(profile.Points, opts.MinDuration, func() {
+		for _, buf := range encoded {
+			if _, _, decodeErr := opts.Codec.DecodeCoords(buf); decodeErr != nil {
+				err = decodeErr
+			}
+		}
+	})
+	if err != nil {
+		return Profile{}, err
+	}
+	if opts.Codec.Dim >= 2 {
+		profile.Simplify = measure(profile.Points, opts.MinDuration, func() {
+			for i := range points {
+				_ = polyline.Simplify(&points[i], opts.Tolerance, false)
+			}
+		})
+	}
+	return profile, nil
+}
@@ -0,0 +1,47 @@
+package bench_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/bench"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunProfile(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(0))
+	ds := bench.Dataset{
+		Name: "random",
+		Polylines: [][][]float64{
+			polyline.Generate(r, polyline.GenOptions{Points: 500}),
+			polyline.Generate(r, polyline.GenOptions{Points: 500}),
+		},
+	}
+	profile, err := bench.RunProfile(ds, bench.Options{MinDuration: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	assert.Equal(t, "random", profile.Dataset)
+	assert.Equal(t, 1000, profile.Points)
+	assert.Greater(t, profile.BytesPerPoint, 2.0)
+	for _, m := range []bench.Measurement{profile.Encode, profile.Decode, profile.Simplify} {
+		assert.Greater(t, m.NsPerPoint, 0.0)
+	}
+	assert.Greater(t, profile.Decode.AllocsPerPoint, 0.0)
+	assert.Contains(t, profile.String(), "decode")
+
+	_, err = bench.RunProfile(bench.Dataset{Polylines: [][][]float64{{{0, 0}}}}, bench.Options{
+		Codec:       polyline.Codec{Dim: 3, Scale: 1e5},
+		MinDuration: time.Millisecond,
+	})
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+
+	profile, err = bench.RunProfile(bench.Dataset{Polylines: [][][]float64{{{1}, {2}, {3}}}}, bench.Options{
+		Codec:       polyline.Codec{Dim: 1, Scale: 1e5},
+		MinDuration: time.Millisecond,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, profile.Points)
+	assert.Zero(t, profile.Simplify)
+}
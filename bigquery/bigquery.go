@@ -0,0 +1,182 @@
+// Package bigquery converts between encoded polylines and the WKT and
+// GeoJSON forms of BigQuery GEOGRAPHY values, and formats polylines for
+// load jobs.
+//
+// BigQuery geographies are two-dimensional WGS84 geometries and normalize
+// a line that never moves to a point, so codecs must be two-dimensional and
+// a polyline whose vertices are all equal converts to a POINT. Polylines
+// also encode WGS84, so a codec's CRS and Transformer are ignored. See
+// https://cloud.google.com/bigquery/docs/geospatial-data.
+package bigquery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// ErrGeography is returned when a value is not a GEOGRAPHY that a polyline
+// can hold.
+var ErrGeography = errors.New("not a LineString or Point GEOGRAPHY")
+
+// wgs84 returns codec without its CRS and Transformer, so that it encodes
+// and decodes the WGS84 coordinates of GEOGRAPHY values.
+func wgs84(codec polyline.Codec) polyline.Codec {
+	codec.CRS, codec.Transformer = "", nil
+	return codec
+}
+
+// decode decodes buf with codec, which must be two-dimensional, and drops
+// consecutive duplicate vertices as BigQuery does.
+func decode(buf []byte, codec polyline.Codec) ([][]float64, error) {
+	if codec.Dim != 2 {
+		return nil, fmt.Errorf("%w: %d dimensions", polyline.ErrDimensionalMismatch, codec.Dim)
+	}
+	coords, _, err := wgs84(codec).DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, coord := range coords {
+		if n > 0 && coord[0] == coords[n-1][0] && coord[1] == coords[n-1][1] {
+			continue
+		}
+		coords[n] = coord
+		n++
+	}
+	return coords[:n], nil
+}
+
+// ToWKT decodes buf with codec, which must be two-dimensional, and returns
+// it as WKT for ST_GEOGFROMTEXT: a LINESTRING, a POINT if it has only one
+// distinct vertex, or LINESTRING EMPTY.
+func ToWKT(buf []byte, codec polyline.Codec) (string, error) {
+	coords, err := decode(buf, codec)
+	if err != nil {
+		return "", err
+	}
+	switch len(coords) {
+	case 0:
+		return "LINESTRING EMPTY", nil
+	case 1:
+		return "POINT(" + wktPosition(coords[0]) + ")", nil
+	}
+	positions := make([]string, len(coords))
+	for i, coord := range coords {
+		positions[i] = wktPosition(coord)
+	}
+	return "LINESTRING(" + strings.Join(positions, ",") + ")", nil
+}
+
+// wktPosition returns coord as a WKT position, longitude first.
+func wktPosition(coord []float64) string {
+	return strconv.FormatFloat(coord[1], 'f', -1, 64) + " " + strconv.FormatFloat(coord[0], 'f', -1, 64)
+}
+
+// FromWKT encodes with codec the WKT of a GEOGRAPHY, as returned by
+// ST_ASTEXT: a LINESTRING, a POINT, or an empty geometry. It returns
+// ErrGeography for other geometries.
+func FromWKT(wkt string, codec polyline.Codec) ([]byte, error) {
+	s := strings.TrimSpace(wkt)
+	upper := strings.ToUpper(s)
+	switch {
+	case strings.HasPrefix(upper, "LINESTRING"):
+		return wgs84(codec).EncodeWKT(nil, []byte(s))
+	case strings.HasPrefix(upper, "POINT"):
+		return wgs84(codec).EncodeWKT(nil, []byte("LINESTRING"+s[len("POINT"):]))
+	case strings.HasSuffix(upper, " EMPTY"):
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%w: %.20q", ErrGeography, s)
+}
+
+// ToGeoJSON decodes buf with codec, which must be two-dimensional, and
+// returns it as a GeoJSON geometry for ST_GEOGFROMGEOJSON: a LineString,
+// or a Point if it has only one distinct vertex.
+func ToGeoJSON(buf []byte, codec polyline.Codec) (string, error) {
+	coords, err := decode(buf, codec)
+	if err != nil {
+		return "", err
+	}
+	var geometry interface{}
+	if len(coords) == 1 {
+		geometry = struct {
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		}{"Point", []float64{coords[0][1], coords[0][0]}}
+	} else {
+		geometry = struct {
+			Type        string      `json:"type"`
+			Coordinates [][]float64 `json:"coordinates"`
+		}{"LineString", polyline.SwapAxes(coords)}
+	}
+	b, err := json.Marshal(geometry)
+	return string(b), err
+}
+
+// FromGeoJSON encodes with codec the GeoJSON of a GEOGRAPHY, as returned by
+// ST_ASGEOJSON: a LineString, a Point, or an empty GeometryCollection. It
+// returns ErrGeography for other geometries.
+func FromGeoJSON(geojson string, codec polyline.Codec) ([]byte, error) {
+	var g struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+		Geometries  []interface{}   `json:"geometries"`
+	}
+	if err := json.Unmarshal([]byte(geojson), &g); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGeography, err)
+	}
+	switch {
+	case g.Type == "LineString":
+		return wgs84(codec).FromGeoJSON([]byte(geojson))
+	case g.Type == "Point":
+		var position []float64
+		if err := json.Unmarshal(g.Coordinates, &position); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrGeography, err)
+		}
+		if len(position) != codec.Dim {
+			return nil, fmt.Errorf("%w: position has %d values", polyline.ErrDimensionalMismatch, len(position))
+		}
+		return wgs84(codec).EncodeCoords(nil, polyline.SwapAxes([][]float64{position})), nil
+	case g.Type == "GeometryCollection" && len(g.Geometries) == 0:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%w: type %q", ErrGeography, g.Type)
+}
+
+// A Row is a row of a load job.
+type Row struct {
+	// Columns holds the values of the row's other columns by name.
+	Columns map[string]interface{}
+	// Polyline is the row's geography.
+	Polyline []byte
+}
+
+// WriteNDJSON writes rows to w as newline-delimited JSON for a load job with
+// source format NEWLINE_DELIMITED_JSON, with each row's polyline decoded
+// with codec, which must be two-dimensional, and converted to WKT in the
+// column named column. Columns are written in name order.
+func WriteNDJSON(w io.Writer, column string, rows []Row, codec polyline.Codec) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for i, row := range rows {
+		wkt, err := ToWKT(row.Polyline, codec)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+		record := make(map[string]interface{}, len(row.Columns)+1)
+		for name, v := range row.Columns {
+			record[name] = v
+		}
+		record[column] = wkt
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
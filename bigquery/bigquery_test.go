@@ -0,0 +1,126 @@
+package bigquery_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/bigquery"
+	"github.com/stretchr/testify/assert"
+)
+
+const line = "_p~iF~ps|U_ulLnnqC"
+
+func TestWKT(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	point := codec.EncodeCoords(nil, [][]float64{{38.5, -120.2}, {38.5, -120.2}})
+	for _, tc := range []struct {
+		name     string
+		buf      []byte
+		expected string
+	}{
+		{name: "line", buf: []byte(line), expected: "LINESTRING(-120.2 38.5,-120.95 40.7)"},
+		{name: "point", buf: point, expected: "POINT(-120.2 38.5)"},
+		{name: "empty", expected: "LINESTRING EMPTY"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			wkt, err := bigquery.ToWKT(tc.buf, codec)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, wkt)
+		})
+	}
+
+	for _, tc := range []struct {
+		wkt      string
+		expected string
+	}{
+		{wkt: "LINESTRING(-120.2 38.5, -120.95 40.7)", expected: line},
+		{wkt: "POINT(-120.2 38.5)", expected: "_p~iF~ps|U"},
+		{wkt: "GEOMETRYCOLLECTION EMPTY"},
+	} {
+		buf, err := bigquery.FromWKT(tc.wkt, codec)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, string(buf))
+	}
+	_, err := bigquery.FromWKT("POLYGON((0 0, 1 0, 1 1, 0 0))", codec)
+	assert.ErrorIs(t, err, bigquery.ErrGeography)
+	_, err = bigquery.ToWKT([]byte(line), codec.WithDim(3))
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+	_, err = bigquery.ToWKT([]byte("_"), codec)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+
+	// GEOGRAPHY values are WGS84 whatever the codec's CRS.
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	wkt, err := bigquery.ToWKT([]byte(line), mercator)
+	assert.NoError(t, err)
+	assert.Equal(t, "LINESTRING(-120.2 38.5,-120.95 40.7)", wkt)
+	buf, err := bigquery.FromWKT(wkt, mercator)
+	assert.NoError(t, err)
+	assert.Equal(t, line, string(buf))
+}
+
+func TestGeoJSON(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	geojson, err := bigquery.ToGeoJSON([]byte(line), codec)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"LineString","coordinates":[[-120.2,38.5],[-120.95,40.7]]}`, geojson)
+	geojson, err = bigquery.ToGeoJSON([]byte("_p~iF~ps|U??"), codec)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Point","coordinates":[-120.2,38.5]}`, geojson)
+	geojson, err = bigquery.ToGeoJSON(nil, codec)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"LineString","coordinates":[]}`, geojson)
+
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	geojson, err = bigquery.ToGeoJSON([]byte("_p~iF~ps|U??"), mercator)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Point","coordinates":[-120.2,38.5]}`, geojson)
+	buf, err := bigquery.FromGeoJSON(geojson, mercator)
+	assert.NoError(t, err)
+	assert.Equal(t, "_p~iF~ps|U", string(buf))
+
+	for _, tc := range []struct {
+		geojson  string
+		expected string
+	}{
+		{geojson: `{"type":"LineString","coordinates":[[-120.2,38.5],[-120.95,40.7]]}`, expected: line},
+		{geojson: `{"type":"Point","coordinates":[-120.2,38.5]}`, expected: "_p~iF~ps|U"},
+		{geojson: `{"type":"GeometryCollection","geometries":[]}`},
+	} {
+		buf, err := bigquery.FromGeoJSON(tc.geojson, codec)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, string(buf))
+	}
+	for _, tc := range []struct {
+		geojson string
+		err     error
+	}{
+		{geojson: `{"type":"Polygon","coordinates":[]}`, err: bigquery.ErrGeography},
+		{geojson: `{"type":"Point","coordinates":"x"}`, err: bigquery.ErrGeography},
+		{geojson: `{"type":"Point","coordinates":[1,2,3]}`, err: polyline.ErrDimensionalMismatch},
+		{geojson: `[`, err: bigquery.ErrGeography},
+	} {
+		_, err := bigquery.FromGeoJSON(tc.geojson, codec)
+		assert.ErrorIs(t, err, tc.err)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	var b bytes.Buffer
+	err := bigquery.WriteNDJSON(&b, "route", []bigquery.Row{
+		{Columns: map[string]interface{}{"id": "a", "km": 250.5}, Polyline: []byte(line)},
+		{Columns: map[string]interface{}{"id": "b<c>"}},
+	}, codec)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"a","km":250.5,"route":"LINESTRING(-120.2 38.5,-120.95 40.7)"}`+"\n"+
+		`{"id":"b<c>","route":"LINESTRING EMPTY"}`+"\n", b.String())
+
+	err = bigquery.WriteNDJSON(&b, "route", []bigquery.Row{{Polyline: []byte("_")}}, codec)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
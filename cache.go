@@ -0,0 +1,131 @@
+package polyline
+
+import (
+	"container/list"
+	"hash/maphash"
+	"math"
+	"sync"
+)
+
+// A CachingEncoder is an encoder that memoizes the encodings of the most
+// recently encoded coordinates, for services that repeatedly encode the same
+// small set of routes. It is safe for concurrent use.
+type CachingEncoder struct {
+	c        Codec
+	capacity int
+	seed     maphash.Seed
+
+	mu      sync.Mutex
+	entries map[uint64]*list.Element
+	lru     *list.List // Of *cacheEntry, most recently used first
+	hits    int
+	misses  int
+}
+
+// A cacheEntry is a memoized encoding.
+type cacheEntry struct {
+	hash    uint64
+	coords  [][]float64
+	encoded []byte
+}
+
+// NewCachingEncoder returns a new CachingEncoder that encodes with c and
+// remembers up to capacity encodings, evicting the least recently used.
+func (c Codec) NewCachingEncoder(capacity int) *CachingEncoder {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &CachingEncoder{
+		c:        c,
+		capacity: capacity,
+		seed:     maphash.MakeSeed(),
+		entries:  make(map[uint64]*list.Element, capacity),
+		lru:      list.New(),
+	}
+}
+
+// EncodeCoords appends the encoding of coords to buf and returns the new buf,
+// as Codec.EncodeCoords does. Coordinates are looked up by a hash of their
+// values and compared in full, so colliding hashes cannot return the wrong
+// encoding.
+func (e *CachingEncoder) EncodeCoords(buf []byte, coords [][]float64) []byte {
+	hash := e.hash(coords)
+	e.mu.Lock()
+	if element, ok := e.entries[hash]; ok {
+		if entry := element.Value.(*cacheEntry); equalCoords(entry.coords, coords) {
+			e.lru.MoveToFront(element)
+			e.hits++
+			e.mu.Unlock()
+			return append(buf, entry.encoded...)
+		}
+	}
+	e.misses++
+	e.mu.Unlock()
+
+	encoded := e.c.EncodeCoords(nil, coords)
+	entry := &cacheEntry{
+		hash:    hash,
+		coords:  make([][]float64, len(coords)),
+		encoded: encoded,
+	}
+	for i, coord := range coords {
+		entry.coords[i] = cloneCoord(coord)
+	}
+
+	e.mu.Lock()
+	if element, ok := e.entries[hash]; ok {
+		e.lru.Remove(element)
+	}
+	e.entries[hash] = e.lru.PushFront(entry)
+	if e.lru.Len() > e.capacity {
+		oldest := e.lru.Remove(e.lru.Back()).(*cacheEntry)
+		delete(e.entries, oldest.hash)
+	}
+	e.mu.Unlock()
+	return append(buf, encoded...)
+}
+
+// Stats returns the number of calls to EncodeCoords that were answered from
+// the cache and the number that were not.
+func (e *CachingEncoder) Stats() (hits, misses int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.hits, e.misses
+}
+
+// hash returns a hash of the values of coords.
+func (e *CachingEncoder) hash(coords [][]float64) uint64 {
+	var h maphash.Hash
+	h.SetSeed(e.seed)
+	var b [8]byte
+	for _, coord := range coords {
+		for _, x := range coord {
+			u := math.Float64bits(x)
+			for i := range b {
+				b[i] = byte(u >> (8 * i))
+			}
+			h.Write(b[:])
+		}
+		// Mark the end of each coordinate.
+		h.WriteByte(0)
+	}
+	return h.Sum64()
+}
+
+// equalCoords returns whether a and b have identical values.
+func equalCoords(a, b [][]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if math.Float64bits(a[i][j]) != math.Float64bits(b[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
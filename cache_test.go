@@ -0,0 +1,58 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/polytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachingEncoder(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	e := codec.NewCachingEncoder(2)
+	a := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	b := [][]float64{{1, 2}}
+	c := [][]float64{{1}, {2}}
+
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", string(e.EncodeCoords(nil, a)))
+	assert.Equal(t, "x_p~iF~ps|U_ulLnnqC_mqNvxq`@", string(e.EncodeCoords([]byte("x"), a)))
+	assert.Equal(t, string(codec.EncodeCoords(nil, b)), string(e.EncodeCoords(nil, b)))
+	hits, misses := e.Stats()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 2, misses)
+
+	// Modifying the input after encoding does not affect the cache.
+	a[0][0] = 0
+	assert.Equal(t, string(codec.EncodeCoords(nil, a)), string(e.EncodeCoords(nil, a)))
+	// Adding the modified a evicted the original, the least recently used.
+	e.EncodeCoords(nil, b)
+	a[0][0] = 38.5
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", string(e.EncodeCoords(nil, a)))
+	hits, misses = e.Stats()
+	assert.Equal(t, 2, hits)
+	assert.Equal(t, 4, misses)
+
+	// The same values in a different shape are a different input.
+	assert.Equal(t, string(codec.WithDim(1).EncodeCoords(nil, c)), string(codec.WithDim(1).NewCachingEncoder(0).EncodeCoords(nil, c)))
+}
+
+func TestCachingEncoderConcurrent(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	e := codec.NewCachingEncoder(4)
+	routes := make([][][]float64, 8)
+	for i := range routes {
+		routes[i] = benchmarkCoords(10 + i)
+	}
+	polytest.RequireConcurrent(t, 8, func(goroutine int) error {
+		for i := 0; i < 100; i++ {
+			route := routes[(goroutine+i)%len(routes)]
+			assert.Equal(t, codec.EncodeCoords(nil, route), e.EncodeCoords(nil, route))
+		}
+		return nil
+	})
+	hits, misses := e.Stats()
+	assert.Equal(t, 800, hits+misses)
+}
@@ -0,0 +1,133 @@
+package polyline
+
+import "math"
+
+// A centroidSum accumulates the vertex mean and the length-weighted segment
+// centroid of a path one coordinate at a time. Longitudes are unwrapped
+// relative to the first coordinate so that paths across the antimeridian
+// average correctly.
+type centroidSum struct {
+	origin   float64   // Longitude of the first coordinate
+	vertices []float64 // Sum of the unwrapped vertices
+	n        int
+	segments []float64 // Sum of the unwrapped segment midpoints times lengths
+	length   float64
+}
+
+// unwrap returns the longitude lng unwrapped relative to s's origin.
+func (s *centroidSum) unwrap(lng float64) float64 {
+	return s.origin + math.Remainder(lng-s.origin, 360)
+}
+
+// add adds coord, which follows prev, or is the first coordinate if prev is
+// nil.
+func (s *centroidSum) add(prev, coord []float64) {
+	if prev == nil {
+		s.origin = coord[1]
+		s.vertices = make([]float64, len(coord))
+		s.segments = make([]float64, len(coord))
+	}
+	// Dimensions beyond those of the first coordinate are ignored.
+	dim := len(s.vertices)
+	if len(coord) < dim {
+		dim = len(coord)
+	}
+	for j, x := range coord[:dim] {
+		if j == 1 {
+			x = s.unwrap(x)
+		}
+		s.vertices[j] += x
+	}
+	s.n++
+	if prev == nil {
+		return
+	}
+	d := haversine(prev, coord)
+	for j := 0; j < dim && j < len(prev); j++ {
+		a, b := prev[j], coord[j]
+		if j == 1 {
+			a, b = s.unwrap(a), s.unwrap(b)
+		}
+		s.segments[j] += (a + b) / 2 * d
+	}
+	s.length += d
+}
+
+// mean returns the vertex mean, or nil if no coordinates were added.
+func (s *centroidSum) mean() []float64 {
+	return s.result(s.vertices, float64(s.n))
+}
+
+// centroid returns the length-weighted centroid, or the vertex mean if the
+// path has no length.
+func (s *centroidSum) centroid() []float64 {
+	if s.length == 0 {
+		return s.mean()
+	}
+	return s.result(s.segments, s.length)
+}
+
+func (s *centroidSum) result(sum []float64, weight float64) []float64 {
+	if s.n == 0 {
+		return nil
+	}
+	result := make([]float64, len(sum))
+	for j, x := range sum {
+		result[j] = x / weight
+	}
+	if len(result) > 1 {
+		result[1] = math.Remainder(result[1], 360)
+	}
+	return result
+}
+
+// Centroid returns the centroid of the path coords, the mean of the
+// midpoints of its segments weighted by their great-circle lengths, for
+// example to place a label on a route. Unlike MeanPoint it is not pulled
+// towards densely sampled stretches. If the path has no length then it is
+// MeanPoint. Further dimensions are averaged in the same way. It returns nil
+// if coords is empty.
+func Centroid(coords [][]float64) []float64 {
+	var s centroidSum
+	for i, coord := range coords {
+		if i == 0 {
+			s.add(nil, coord)
+		} else {
+			s.add(coords[i-1], coord)
+		}
+	}
+	return s.centroid()
+}
+
+// MeanPoint returns the mean of the vertices of coords, for example to
+// cluster routes by their typical position. Longitudes are averaged across
+// the antimeridian when that is shorter. It returns nil if coords is empty.
+func MeanPoint(coords [][]float64) []float64 {
+	var s centroidSum
+	for i, coord := range coords {
+		if i == 0 {
+			s.add(nil, coord)
+		} else {
+			s.add(coords[i-1], coord)
+		}
+	}
+	return s.mean()
+}
+
+// Centroid returns the Centroid of the polyline buf, computed while decoding
+// without storing the coordinates. The codec must be at least
+// two-dimensional. It returns ErrEmpty if buf contains no
+// coordinates.
+func (c Codec) Centroid(buf []byte) ([]float64, error) {
+	var s centroidSum
+	err := c.walk(buf, func(i int, prev, coord []float64) {
+		s.add(prev, coord)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.n == 0 {
+		return nil, ErrEmpty
+	}
+	return s.centroid(), nil
+}
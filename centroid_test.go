@@ -0,0 +1,59 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCentroid(t *testing.T) {
+	t.Parallel()
+	// A long segment and a short one densely sampled: the mean point is
+	// pulled towards the samples but the centroid is not.
+	coords := [][]float64{{0, 0}, {0, 2}, {0, 2.1}, {0, 2.2}, {0, 2.3}, {0, 2.4}}
+	assert.InDeltaSlice(t, []float64{0, 1.2}, polyline.Centroid(coords), 1e-9)
+	assert.InDeltaSlice(t, []float64{0, 1.8333333}, polyline.MeanPoint(coords), 1e-6)
+
+	for _, tc := range []struct {
+		name     string
+		coords   [][]float64
+		centroid []float64
+		mean     []float64
+	}{
+		{name: "empty"},
+		{name: "one", coords: [][]float64{{1, 2}}, centroid: []float64{1, 2}, mean: []float64{1, 2}},
+		{name: "stationary", coords: [][]float64{{1, 2}, {1, 2}}, centroid: []float64{1, 2}, mean: []float64{1, 2}},
+		{name: "antimeridian", coords: [][]float64{{0, 179}, {0, -179}}, centroid: []float64{0, 180}, mean: []float64{0, 180}},
+		{name: "3d", coords: [][]float64{{0, 0, 10}, {0, 1, 20}, {0, 3, 40}}, centroid: []float64{0, 1.5, 25}, mean: []float64{0, 4.0 / 3, 70.0 / 3}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			centroid, mean := polyline.Centroid(tc.coords), polyline.MeanPoint(tc.coords)
+			if tc.centroid == nil {
+				assert.Nil(t, centroid)
+				assert.Nil(t, mean)
+				return
+			}
+			assert.InDeltaSlice(t, tc.centroid, centroid, 1e-6)
+			assert.InDeltaSlice(t, tc.mean, mean, 1e-6)
+		})
+	}
+}
+
+func TestCodecCentroid(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	coords, _, err := codec.DecodeCoords(buf)
+	assert.NoError(t, err)
+	centroid, err := codec.Centroid(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.Centroid(coords), centroid)
+
+	_, err = codec.Centroid(nil)
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+	_, err = codec.Centroid([]byte("_p~iF~ps|U_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
@@ -0,0 +1,64 @@
+package polyline
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Chainage returns the distance in meters along coords from their start to
+// each vertex, as used to label alignments in road and rail engineering.
+func Chainage(coords [][]float64) []float64 {
+	return cumulativeDistances(coords)
+}
+
+// A Station is a vertex of an alignment labeled with its chainage.
+type Station struct {
+	Chainage float64 // Distance from the start of the alignment in meters
+	Lat      float64
+	Lng      float64
+}
+
+// Label returns the conventional label of s, kilometers and meters separated
+// by a plus sign, for example "1+234.50".
+func (s Station) Label() string {
+	cm := math.Round(s.Chainage * 100)
+	km := math.Floor(cm / 100000)
+	return fmt.Sprintf("%.0f+%06.2f", km, (cm-km*100000)/100)
+}
+
+// Stations returns the stations of the vertices of coords.
+func Stations(coords [][]float64) []Station {
+	chainage := Chainage(coords)
+	stations := make([]Station, len(coords))
+	for i, coord := range coords {
+		stations[i] = Station{
+			Chainage: chainage[i],
+			Lat:      coord[0],
+			Lng:      coord[1],
+		}
+	}
+	return stations
+}
+
+// WriteStations writes stations to w as CSV with a header row and the columns
+// station, lat, and lng.
+func WriteStations(w io.Writer, stations []Station) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"station", "lat", "lng"}); err != nil {
+		return err
+	}
+	for _, s := range stations {
+		if err := cw.Write([]string{
+			strconv.FormatFloat(s.Chainage, 'f', 2, 64),
+			strconv.FormatFloat(s.Lat, 'f', -1, 64),
+			strconv.FormatFloat(s.Lng, 'f', -1, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
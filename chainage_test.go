@@ -0,0 +1,28 @@
+package polyline_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStations(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {0.001, 0}, {0.011, 0}}
+	chainage := polyline.Chainage(coords)
+	assert.Len(t, chainage, 3)
+	assert.InDelta(t, 111.195, chainage[1], 1e-3)
+	assert.InDelta(t, 1223.146, chainage[2], 1e-3)
+
+	stations := polyline.Stations(coords)
+	assert.Equal(t, "0+000.00", stations[0].Label())
+	assert.Equal(t, "0+111.20", stations[1].Label())
+	assert.Equal(t, "1+223.15", stations[2].Label())
+	assert.Equal(t, "12+000.00", polyline.Station{Chainage: 11999.999}.Label())
+
+	var sb strings.Builder
+	assert.NoError(t, polyline.WriteStations(&sb, stations))
+	assert.Equal(t, "station,lat,lng\n0.00,0,0\n111.20,0.001,0\n1223.15,0.011,0\n", sb.String())
+}
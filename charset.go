@@ -0,0 +1,28 @@
+package polyline
+
+// The encoding alphabet. Every byte of an encoded polyline is in the range
+// MinByte to MaxByte. Each byte carries ValueBits bits of a value, least
+// significant first, offset by MinByte. Bytes at or above ContinuationByte,
+// whose offset has ContinuationBit set, are followed by more bytes of the
+// same value, and the first byte below ContinuationByte terminates it.
+const (
+	MinByte          = 63 // '?', which terminates a value of zero
+	MaxByte          = 126
+	ValueBits        = 5
+	ContinuationBit  = 1 << ValueBits
+	ContinuationByte = MinByte + ContinuationBit // '_'
+)
+
+// IsValidPolylineByte returns whether b can appear in an encoded polyline.
+func IsValidPolylineByte(b byte) bool {
+	return MinByte <= b && b <= MaxByte
+}
+
+// IsTerminalPolylineByte returns whether b is a valid polyline byte that
+// terminates a value.
+func IsTerminalPolylineByte(b byte) bool {
+	return MinByte <= b && b < ContinuationByte
+}
+
+// alphabetSize is the number of valid polyline bytes.
+const alphabetSize = MaxByte - MinByte + 1
@@ -0,0 +1,32 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharset(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, byte('?'), byte(polyline.MinByte))
+	assert.Equal(t, byte('~'), byte(polyline.MaxByte))
+	assert.Equal(t, byte('_'), byte(polyline.ContinuationByte))
+
+	var valid, terminal int
+	for b := 0; b < 256; b++ {
+		if polyline.IsValidPolylineByte(byte(b)) {
+			valid++
+		}
+		if polyline.IsTerminalPolylineByte(byte(b)) {
+			terminal++
+			assert.True(t, polyline.IsValidPolylineByte(byte(b)))
+		}
+	}
+	assert.Equal(t, 64, valid)
+	assert.Equal(t, polyline.ContinuationBit, terminal)
+
+	for _, b := range polyline.EncodeCoords([][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}) {
+		assert.True(t, polyline.IsValidPolylineByte(b))
+	}
+}
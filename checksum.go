@@ -0,0 +1,69 @@
+package polyline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+)
+
+// ErrChecksum is returned when a checksummed polyline is missing its
+// checksum or does not match it.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// ChecksumDelimiter separates a polyline from its checksum. It is not a
+// valid polyline byte.
+const ChecksumDelimiter = '#'
+
+// AppendChecksum appends ChecksumDelimiter and the IEEE CRC-32 of polyline,
+// as eight lowercase hex digits, to polyline and returns the result. To
+// checksum each chunk of a chunked polyline, apply it to each chunk
+// returned by Codec.Chunk.
+func AppendChecksum(polyline []byte) []byte {
+	sum := crc32.ChecksumIEEE(polyline)
+	polyline = append(polyline, ChecksumDelimiter)
+	for shift := 28; shift >= 0; shift -= 4 {
+		polyline = append(polyline, "0123456789abcdef"[sum>>shift&0xf])
+	}
+	return polyline
+}
+
+// VerifyChecksum checks a polyline framed by AppendChecksum and returns the
+// polyline without its checksum. It returns ErrChecksum if the checksum is
+// missing or malformed or does not match.
+func VerifyChecksum(framed []byte) ([]byte, error) {
+	i := bytes.LastIndexByte(framed, ChecksumDelimiter)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: no checksum", ErrChecksum)
+	}
+	polyline, digits := framed[:i], framed[i+1:]
+	if len(digits) != 8 {
+		return nil, fmt.Errorf("%w: malformed checksum %q", ErrChecksum, digits)
+	}
+	want, err := strconv.ParseUint(string(digits), 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed checksum %q", ErrChecksum, digits)
+	}
+	if got := crc32.ChecksumIEEE(polyline); uint32(want) != got {
+		return nil, fmt.Errorf("%w: got %08x, want %08x", ErrChecksum, got, want)
+	}
+	return polyline, nil
+}
+
+// EncodeCoordsChecksum returns the encoding of coords framed by
+// AppendChecksum.
+func (c Codec) EncodeCoordsChecksum(coords [][]float64) []byte {
+	return AppendChecksum(c.EncodeCoords(nil, coords))
+}
+
+// DecodeCoordsChecksum verifies a polyline framed by AppendChecksum and
+// returns its coordinates.
+func (c Codec) DecodeCoordsChecksum(framed []byte) ([][]float64, error) {
+	buf, err := VerifyChecksum(framed)
+	if err != nil {
+		return nil, err
+	}
+	coords, _, err := c.DecodeCoords(buf)
+	return coords, err
+}
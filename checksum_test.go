@@ -0,0 +1,62 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksum(t *testing.T) {
+	t.Parallel()
+	const framed = "_p~iF~ps|U_ulLnnqC_mqNvxq`@#"
+	sum := string(polyline.AppendChecksum([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")))
+	assert.Len(t, sum, len(framed)+8)
+	assert.Equal(t, framed, sum[:len(framed)])
+	assert.Equal(t, "#00000000", string(polyline.AppendChecksum(nil)))
+	for _, tc := range []struct {
+		name     string
+		framed   string
+		expected string
+		err      error
+	}{
+		{name: "valid", framed: sum, expected: "_p~iF~ps|U_ulLnnqC_mqNvxq`@"},
+		{name: "empty", framed: string(polyline.AppendChecksum(nil)), expected: ""},
+		{name: "corrupted", framed: "_p~iF~ps|U_ulLnnqC_mqNvxq`A" + sum[len(framed)-1:], err: polyline.ErrChecksum},
+		{name: "truncated", framed: sum[:len(sum)-1], err: polyline.ErrChecksum},
+		{name: "missing", framed: "_p~iF~ps|U", err: polyline.ErrChecksum},
+		{name: "malformed", framed: "_p~iF~ps|U#0000000g", err: polyline.ErrChecksum},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			buf, err := polyline.VerifyChecksum([]byte(tc.framed))
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, string(buf))
+		})
+	}
+}
+
+func TestCodecChecksum(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	framed := codec.EncodeCoordsChecksum(coords)
+	got, err := codec.DecodeCoordsChecksum(framed)
+	assert.NoError(t, err)
+	assert.Equal(t, coords, got)
+
+	framed[3]++
+	_, err = codec.DecodeCoordsChecksum(framed)
+	assert.ErrorIs(t, err, polyline.ErrChecksum)
+	_, err = codec.DecodeCoordsChecksum(polyline.AppendChecksum([]byte("_")))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+
+	chunks, err := codec.Chunk(codec.EncodeCoords(nil, benchmarkCoords(100)), 64)
+	assert.NoError(t, err)
+	for _, chunk := range chunks {
+		buf, err := polyline.VerifyChecksum(polyline.AppendChecksum(chunk))
+		assert.NoError(t, err)
+		assert.Equal(t, chunk, buf)
+	}
+}
@@ -0,0 +1,88 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrChunkTooSmall is returned by Codec.Chunk when a chunk cannot hold two
+// coordinates.
+var ErrChunkTooSmall = errors.New("chunk size too small")
+
+// Chunk splits the polyline buf into polylines of at most maxBytes bytes
+// each, for transports that limit the size of string fields. Every chunk is
+// an independent polyline whose first coordinate is the last coordinate of
+// the previous chunk, so the chunks can be drawn separately without gaps and
+// rejoined with DecodeChunks. A polyline that already fits is returned as a
+// single chunk, as is an empty polyline. It returns ErrChunkTooSmall if
+// maxBytes cannot hold a coordinate on its own, or a coordinate and its
+// successor.
+func (c Codec) Chunk(buf []byte, maxBytes int) ([][]byte, error) {
+	// Chunks are re-encoded from the decoded coordinates, which must not be
+	// transformed or coalesced on the way through.
+	c = c.untransformed()
+	c.CoalesceQuantumDuplicates = false
+	coords, _, err := c.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) <= maxBytes || len(coords) == 0 {
+		return [][]byte{buf}, nil
+	}
+
+	var chunks [][]byte
+	start := 0
+	size := c.coordLen(coords[0], nil)
+	if size > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes for coordinate 0", ErrChunkTooSmall, size)
+	}
+	for i := 1; i < len(coords); i++ {
+		n := c.coordLen(coords[i], coords[i-1])
+		if size+n <= maxBytes {
+			size += n
+			continue
+		}
+		if i-1 == start {
+			return nil, fmt.Errorf("%w: %d bytes for coordinates %d and %d", ErrChunkTooSmall, size+n, start, i)
+		}
+		chunks = append(chunks, c.EncodeCoords(nil, coords[start:i]))
+		start = i - 1
+		size = c.coordLen(coords[start], nil) + n
+		if size > maxBytes {
+			return nil, fmt.Errorf("%w: %d bytes for coordinates %d and %d", ErrChunkTooSmall, size, start, i)
+		}
+	}
+	return append(chunks, c.EncodeCoords(nil, coords[start:])), nil
+}
+
+// coordLen returns the length of the encoding of coord following prev, or as
+// the first coordinate if prev is nil.
+func (c Codec) coordLen(coord, prev []float64) int {
+	var n int
+	for i, x := range coord {
+		delta := c.quantize(i, x)
+		if prev != nil {
+			delta -= c.quantize(i, prev[i])
+		}
+		n += intLen(delta)
+	}
+	return n
+}
+
+// DecodeChunks decodes chunks produced by Chunk and returns the coordinates
+// of the original polyline, dropping the coordinate each chunk shares with
+// the one before.
+func (c Codec) DecodeChunks(chunks [][]byte) ([][]float64, error) {
+	var coords [][]float64
+	for i, chunk := range chunks {
+		chunkCoords, _, err := c.DecodeCoords(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d: %w", i, err)
+		}
+		if i > 0 && len(chunkCoords) > 0 {
+			chunkCoords = chunkCoords[1:]
+		}
+		coords = append(coords, chunkCoords...)
+	}
+	return coords, nil
+}
@@ -0,0 +1,80 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	coords := benchmarkCoords(200)
+	buf := codec.EncodeCoords(nil, coords)
+	for _, tc := range []struct {
+		name     string
+		maxBytes int
+		chunks   int
+		err      error
+	}{
+		{name: "fits", maxBytes: len(buf), chunks: 1},
+		{name: "halves", maxBytes: len(buf)/2 + 20, chunks: 2},
+		{name: "small", maxBytes: 64},
+		{name: "too_small", maxBytes: 12, err: polyline.ErrChunkTooSmall},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			chunks, err := codec.Chunk(buf, tc.maxBytes)
+			assert.ErrorIs(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+			if tc.chunks != 0 {
+				assert.Len(t, chunks, tc.chunks)
+			}
+			for i, chunk := range chunks {
+				assert.LessOrEqual(t, len(chunk), tc.maxBytes)
+				chunkCoords, _, err := codec.DecodeCoords(chunk)
+				assert.NoError(t, err)
+				if i > 0 {
+					prev, _, _ := codec.DecodeCoords(chunks[i-1])
+					assert.Equal(t, prev[len(prev)-1], chunkCoords[0])
+				}
+			}
+			got, err := codec.DecodeChunks(chunks)
+			assert.NoError(t, err)
+			want, _, _ := codec.DecodeCoords(buf)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestChunkEdgeCases(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+
+	chunks, err := codec.Chunk(nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{nil}, chunks)
+	chunks, err = codec.Chunk(nil, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{nil}, chunks)
+
+	_, err = codec.Chunk([]byte("_p~iF~ps|U"), 9)
+	assert.ErrorIs(t, err, polyline.ErrChunkTooSmall)
+
+	// Each coordinate needs 10 bytes on its own and 8 or 9 as a delta.
+	_, err = codec.Chunk([]byte("_p~iF~ps|U_ulLnnqC"), 9)
+	assert.ErrorIs(t, err, polyline.ErrChunkTooSmall)
+	chunks, err = codec.Chunk([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"), 19)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC", string(chunks[0]))
+
+	_, err = codec.Chunk([]byte("_"), 10)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	_, err = codec.DecodeChunks([][]byte{[]byte("??"), []byte("_")})
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
@@ -0,0 +1,63 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesceQuantumDuplicates(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name      string
+		coords    [][]float64
+		plain     string
+		coalesced string
+	}{
+		{
+			name:      "negative_zero",
+			coords:    [][]float64{{negativeZero(), 0}, {0, negativeZero()}},
+			plain:     "????",
+			coalesced: "??",
+		},
+		{
+			name:      "wiggle",
+			coords:    [][]float64{{0.0000049, 0.0000049}, {0.0000051, 0.0000051}, {0.0000049, 0.0000049}},
+			plain:     "??AA@@",
+			coalesced: "??",
+		},
+		{
+			name:      "drift",
+			coords:    [][]float64{{0, 0}, {0.000004, 0}, {0.000008, 0}, {0.000012, 0}},
+			plain:     "????A???",
+			coalesced: "??A?",
+		},
+		{
+			name:      "move",
+			coords:    [][]float64{{38.5, -120.2}, {38.5, -120.2}, {40.7, -120.95}},
+			plain:     "_p~iF~ps|U??_ulLnnqC",
+			coalesced: "_p~iF~ps|U_ulLnnqC",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			plain := polyline.Codec{Dim: 2, Scale: 1e5}
+			coalesce := polyline.Codec{Dim: 2, Scale: 1e5, CoalesceQuantumDuplicates: true}
+			assert.Equal(t, tc.plain, string(plain.EncodeCoords(nil, tc.coords)))
+			assert.Equal(t, tc.coalesced, string(coalesce.EncodeCoords(nil, tc.coords)))
+
+			var fcs []float64
+			points := make([]polyline.Point, len(tc.coords))
+			for i, c := range tc.coords {
+				fcs = append(fcs, c...)
+				points[i] = polyline.ChartPoint{X: c[0], Y: c[1]}
+			}
+			got, err := coalesce.EncodeFlatCoords(nil, fcs)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.coalesced, string(got))
+			assert.Equal(t, tc.coalesced, string(coalesce.EncodePoints(points, 1e-9, true)))
+		})
+	}
+}
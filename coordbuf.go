@@ -0,0 +1,106 @@
+package polyline
+
+// A CoordBuffer holds coordinates in a single flat backing array and hands out
+// views of individual coordinates. Decoding into a CoordBuffer that is reused
+// with Reset avoids allocating in steady state. The zero value is not usable;
+// create CoordBuffers with NewCoordBuffer. A CoordBuffer is not safe for
+// concurrent use.
+type CoordBuffer struct {
+	dim    int
+	flat   []float64
+	coords [][]float64
+}
+
+// NewCoordBuffer returns a new CoordBuffer for coordinates of dimension dim
+// with space for capacity coordinates.
+func NewCoordBuffer(dim, capacity int) *CoordBuffer {
+	return &CoordBuffer{
+		dim:  dim,
+		flat: make([]float64, 0, dim*capacity),
+	}
+}
+
+// Dim returns the dimension of the coordinates in b.
+func (b *CoordBuffer) Dim() int {
+	return b.dim
+}
+
+// Len returns the number of coordinates in b.
+func (b *CoordBuffer) Len() int {
+	return len(b.flat) / b.dim
+}
+
+// Reset removes all coordinates from b, retaining its storage. Views
+// previously returned by b must not be used after Reset.
+func (b *CoordBuffer) Reset() {
+	b.flat = b.flat[:0]
+	b.coords = b.coords[:0]
+}
+
+// Append appends coord, which must have dimension b.Dim(), to b.
+func (b *CoordBuffer) Append(coord []float64) {
+	b.flat = append(b.flat, coord...)
+}
+
+// Coord returns a view of the ith coordinate in b.
+func (b *CoordBuffer) Coord(i int) []float64 {
+	return b.flat[i*b.dim : (i+1)*b.dim : (i+1)*b.dim]
+}
+
+// Flat returns the coordinates in b as a flat array. The returned slice
+// aliases b's storage.
+func (b *CoordBuffer) Flat() []float64 {
+	return b.flat
+}
+
+// Coords returns views of all the coordinates in b. The returned slices alias
+// b's storage and are invalidated by Reset and by appending to b.
+func (b *CoordBuffer) Coords() [][]float64 {
+	n := b.Len()
+	if n == 0 {
+		return nil
+	}
+	if cap(b.coords) < n {
+		b.coords = make([][]float64, 0, n)
+	}
+	b.coords = b.coords[:0]
+	for i := 0; i < n; i++ {
+		b.coords = append(b.coords, b.Coord(i))
+	}
+	return b.coords
+}
+
+// DecodeInto decodes coordinates from buf and appends them to b. It returns
+// the remaining unconsumed bytes of buf and any error. If an error occurs then
+// b is left unchanged. It returns ErrDimensionalMismatch if b's dimension is
+// not c.Dim.
+func (c Codec) DecodeInto(b *CoordBuffer, buf []byte) ([]byte, error) {
+	if b.dim != c.Dim {
+		return nil, ErrDimensionalMismatch
+	}
+	n := len(b.flat)
+	if values := countValues(buf); cap(b.flat)-n < values {
+		flat := make([]float64, n, n+values)
+		copy(flat, b.flat)
+		b.flat = flat
+	}
+	flat, rest, err := c.DecodeFlatCoords(b.flat, buf)
+	if err != nil {
+		b.flat = b.flat[:n]
+		return nil, err
+	}
+	b.flat = flat
+	return rest, nil
+}
+
+// countValues returns the number of encoded values in buf, which is the
+// number of bytes that terminate a value.
+func countValues(buf []byte) int {
+	var n int
+	for _, b := range buf {
+		if b < ContinuationByte {
+			n++
+		}
+	}
+	return n
+}
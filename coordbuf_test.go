@@ -0,0 +1,61 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoordBuffer(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	b := polyline.NewCoordBuffer(2, 0)
+	assert.Equal(t, 2, b.Dim())
+	assert.Nil(t, b.Coords())
+
+	rest, err := codec.DecodeInto(b, []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+	assert.NoError(t, err)
+	assert.Empty(t, rest)
+	assert.Equal(t, 3, b.Len())
+	assert.Equal(t, []float64{40.7, -120.95}, b.Coord(1))
+	assert.Equal(t, [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}, b.Coords())
+
+	// A second polyline is decoded independently and appended.
+	_, err = codec.DecodeInto(b, []byte("??"))
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{38.5, -120.2, 40.7, -120.95, 43.252, -126.453, 0, 0}, b.Flat())
+
+	// Errors leave the buffer unchanged.
+	_, err = codec.DecodeInto(b, []byte("??_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	assert.Equal(t, 4, b.Len())
+
+	// Views cannot grow into their neighbors.
+	coord := append(b.Coord(0), 1)
+	assert.Equal(t, []float64{38.5, -120.2, 1}, coord)
+	assert.Equal(t, []float64{40.7, -120.95}, b.Coord(1))
+
+	b.Reset()
+	assert.Zero(t, b.Len())
+	b.Append([]float64{1, 2})
+	assert.Equal(t, [][]float64{{1, 2}}, b.Coords())
+
+	_, err = polyline.Codec{Dim: 3, Scale: 1e5}.DecodeInto(b, []byte("???"))
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+}
+
+//nolint:paralleltest // AllocsPerRun cannot be used in parallel tests.
+func TestCoordBufferAllocs(t *testing.T) {
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	buf := polyline.EncodeCoords(benchmarkCoords(1024))
+	b := polyline.NewCoordBuffer(2, 1024)
+	allocs := testing.AllocsPerRun(10, func() {
+		b.Reset()
+		if _, err := codec.DecodeInto(b, buf); err != nil {
+			t.Fatal(err)
+		}
+		_ = b.Coords()
+	})
+	assert.Equal(t, 0.0, allocs)
+}
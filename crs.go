@@ -0,0 +1,128 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrNoTransformer is returned by Codec.Validate when a codec has a CRS other
+// than WGS84 but no Transformer.
+var ErrNoTransformer = errors.New("no transformer for coordinate reference system")
+
+// A Transformer reprojects the first two dimensions of coordinates between a
+// coordinate reference system and WGS84 latitudes and longitudes, which are
+// what polylines encode. x and y are in the order used by the reference
+// system, for example easting and northing.
+type Transformer interface {
+	ToWGS84(x, y float64) (lat, lng float64)
+	FromWGS84(lat, lng float64) (x, y float64)
+}
+
+// isWGS84 returns whether crs names WGS84 latitudes and longitudes.
+func isWGS84(crs string) bool {
+	switch strings.ToUpper(crs) {
+	case "", "EPSG:4326", "WGS84", "OGC:CRS84":
+		return true
+	default:
+		return false
+	}
+}
+
+// srid returns the EPSG code of c's CRS, 4326 for WGS84, or zero if the CRS
+// is not named by an EPSG code.
+func (c Codec) srid() uint32 {
+	if isWGS84(c.CRS) {
+		return 4326
+	}
+	if code := strings.ToUpper(c.CRS); strings.HasPrefix(code, "EPSG:") {
+		if n, err := strconv.ParseUint(code[len("EPSG:"):], 10, 32); err == nil {
+			return uint32(n)
+		}
+	}
+	return 0
+}
+
+// xyAxes returns coords in the x, y order of well-known text and binary:
+// swapped to longitude, latitude if c has no Transformer, and unchanged if it
+// has, since a Transformer already works in the order of its CRS.
+func (c Codec) xyAxes(coords [][]float64) [][]float64 {
+	if c.Transformer != nil {
+		return coords
+	}
+	return SwapAxes(coords)
+}
+
+// Validate returns an error if c cannot encode or decode coordinates: if its
+// dimensionality or scale is not positive, its Scales do not match its
+// dimensionality, or it has a CRS other than WGS84 but no Transformer, in
+// which case projected coordinates would be encoded as if they were degrees.
+func (c Codec) Validate() error {
+	switch {
+	case c.Dim < 1:
+		return fmt.Errorf("%w: dimensionality %d", ErrDimensionalMismatch, c.Dim)
+	case c.Scales != nil && len(c.Scales) != c.Dim:
+		return fmt.Errorf("%w: %d scales for dimensionality %d", ErrDimensionalMismatch, len(c.Scales), c.Dim)
+	case c.Scales == nil && !(c.Scale > 0):
+		return fmt.Errorf("invalid scale %g", c.Scale)
+	case c.Transformer == nil && !isWGS84(c.CRS):
+		return fmt.Errorf("%w: %s", ErrNoTransformer, c.CRS)
+	}
+	for i, s := range c.Scales {
+		if !(s > 0) {
+			return fmt.Errorf("invalid scale %g for dimension %d", s, i)
+		}
+	}
+	return nil
+}
+
+// untransformed returns c without its Transformer.
+func (c Codec) untransformed() Codec {
+	c.Transformer = nil
+	return c
+}
+
+// toWGS84 returns a copy of coords transformed to WGS84 by c's Transformer.
+func (c Codec) toWGS84(coords [][]float64) [][]float64 {
+	result := make([][]float64, len(coords))
+	for i, coord := range coords {
+		result[i] = cloneCoord(coord)
+		result[i][0], result[i][1] = c.Transformer.ToWGS84(coord[0], coord[1])
+	}
+	return result
+}
+
+// toWGS84Flat returns a copy of fcs transformed to WGS84 by c's Transformer.
+func (c Codec) toWGS84Flat(fcs []float64) []float64 {
+	result := append([]float64(nil), fcs...)
+	for i := 0; i+1 < len(result); i += c.Dim {
+		result[i], result[i+1] = c.Transformer.ToWGS84(result[i], result[i+1])
+	}
+	return result
+}
+
+// fromWGS84Flat transforms fcs in place from WGS84 by c's Transformer.
+func (c Codec) fromWGS84Flat(fcs []float64) {
+	for i := 0; i+1 < len(fcs); i += c.Dim {
+		fcs[i], fcs[i+1] = c.Transformer.FromWGS84(fcs[i], fcs[i+1])
+	}
+}
+
+// webMercator is the Transformer returned by WebMercator.
+type webMercator struct{}
+
+func (webMercator) ToWGS84(x, y float64) (lat, lng float64) {
+	const r = 6378137
+	return degrees(2*math.Atan(math.Exp(y/r)) - math.Pi/2), degrees(x / r)
+}
+
+func (webMercator) FromWGS84(lat, lng float64) (x, y float64) {
+	const r = 6378137
+	return r * radians(lng), r * math.Log(math.Tan(math.Pi/4+radians(lat)/2))
+}
+
+// WebMercator transforms between spherical Web Mercator, EPSG:3857, easting
+// and northing in meters and WGS84.
+var WebMercator Transformer = webMercator{}
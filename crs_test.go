@@ -0,0 +1,159 @@
+package polyline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecTransformer(t *testing.T) {
+	t.Parallel()
+	wgs84 := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	mercator := make([][]float64, len(wgs84))
+	for i, coord := range wgs84 {
+		x, y := polyline.WebMercator.FromWGS84(coord[0], coord[1])
+		mercator[i] = []float64{x, y}
+	}
+	assert.InDelta(t, -13380603, mercator[0][0], 1)
+	assert.InDelta(t, 4650301, mercator[0][1], 1)
+
+	codec := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	buf := codec.EncodeCoords(nil, mercator)
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", string(buf))
+	flat, err := codec.EncodeFlatCoords(nil, []float64{mercator[0][0], mercator[0][1]})
+	assert.NoError(t, err)
+	assert.Equal(t, "_p~iF~ps|U", string(flat))
+
+	got, _, err := codec.DecodeCoords(buf)
+	assert.NoError(t, err)
+	assertCoordsWithin(t, mercator, got, 1)
+	got, err = codec.SafeDecode(buf)
+	assert.NoError(t, err)
+	assertCoordsWithin(t, mercator, got, 1)
+}
+
+func TestCodecValidate(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name  string
+		codec polyline.Codec
+		err   error
+	}{
+		{
+			name:  "default",
+			codec: polyline.DefaultCodec(),
+		},
+		{
+			name:  "wgs84",
+			codec: polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:4326"},
+		},
+		{
+			name:  "dim",
+			codec: polyline.Codec{Scale: 1e5},
+			err:   polyline.ErrDimensionalMismatch,
+		},
+		{
+			name:  "no_transformer",
+			codec: polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857"},
+			err:   polyline.ErrNoTransformer,
+		},
+		{
+			name:  "scales",
+			codec: polyline.Codec{Dim: 3}.WithScales(1e5, 1e5, 1e2),
+		},
+		{
+			name:  "scales_dim",
+			codec: polyline.Codec{Dim: 3, Scale: 1e5}.WithScales(1e5, 1e5),
+			err:   polyline.ErrDimensionalMismatch,
+		},
+		{
+			name:  "transformer",
+			codec: polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.ErrorIs(t, tc.codec.Validate(), tc.err)
+		})
+	}
+	assert.Error(t, polyline.Codec{Dim: 2}.Validate())
+	assert.Error(t, polyline.Codec{Dim: 2}.WithScales(1e5, 0).Validate())
+}
+
+func TestCodecTransformerGeometry(t *testing.T) {
+	t.Parallel()
+	wgs84 := polyline.DefaultCodec()
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	coords := [][]float64{{38.5, -120.2}, {38.51, -120.2}, {38.51, -120.21}, {38.52, -120.21}}
+	buf := wgs84.EncodeCoords(nil, coords)
+
+	// Geometry methods work in WGS84 whatever the codec's CRS, so they give
+	// the same results as with a WGS84 codec.
+	expectedSummary, err := wgs84.Summarize(buf)
+	assert.NoError(t, err)
+	summary, err := mercator.Summarize(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSummary, summary)
+	length, err := mercator.Length(buf)
+	assert.NoError(t, err)
+	assert.InDelta(t, length, summary.Length, 1e-6)
+
+	times := []time.Time{time.Unix(0, 0), time.Unix(60, 0), time.Unix(120, 0), time.Unix(180, 0)}
+	trips := []polyline.Trip{{ID: "a", Polyline: buf, Times: times}}
+	expectedMileage, err := polyline.Mileage(trips, polyline.MileageOptions{Codec: wgs84})
+	assert.NoError(t, err)
+	mileage, err := polyline.Mileage(trips, polyline.MileageOptions{Codec: mercator})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedMileage, mileage)
+
+	thumbnail, err := mercator.Thumbnail(buf, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, string(wgs84.EncodeCoords(nil, [][]float64{coords[0], coords[3]})), string(thumbnail))
+
+	expectedTiles, err := wgs84.CorridorTiles(buf, 100, []int{14})
+	assert.NoError(t, err)
+	tiles, err := mercator.CorridorTiles(buf, 100, []int{14})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTiles, tiles)
+
+	stops := [][]float64{{38.5, -120.2}, {38.52, -120.21}}
+	expectedPositions, err := wgs84.SnapStops(buf, stops, 10)
+	assert.NoError(t, err)
+	positions, err := mercator.SnapStops(buf, stops, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPositions, positions)
+
+	it, err := mercator.AlignAnnotations(buf, polyline.Annotations{})
+	assert.NoError(t, err)
+	assert.True(t, it.Next())
+	assert.Equal(t, coords[0], it.Segment().From)
+
+	circle := mercator.EncodeCircle(nil, []float64{51.5, -0.1}, 1000, 16)
+	assert.Equal(t, string(wgs84.EncodeCircle(nil, []float64{51.5, -0.1}, 1000, 16)), string(circle))
+	shape, err := mercator.DecodeShape(circle)
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.ShapeCircle, shape.Kind)
+	assert.InDelta(t, 1000, shape.Radius, 5)
+	box := mercator.EncodeBounds(nil, []float64{38.5, -120.21}, []float64{38.52, -120.2})
+	assert.Equal(t, string(wgs84.EncodeBounds(nil, []float64{38.5, -120.21}, []float64{38.52, -120.2})), string(box))
+	shape, err = mercator.DecodeShape(box)
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.ShapeBounds, shape.Kind)
+	assert.Equal(t, []float64{38.5, -120.21}, shape.Min)
+
+	// Encoding estimates and audits apply to the coordinates transformed to
+	// WGS84, which are what the polyline encodes.
+	projected := make([][]float64, len(coords))
+	for i, coord := range coords {
+		x, y := polyline.WebMercator.FromWGS84(coord[0], coord[1])
+		projected[i] = []float64{x, y}
+	}
+	assert.Equal(t, len(mercator.EncodeCoords(nil, projected)), polyline.EstimateEncodedSize(projected, mercator))
+	exact, err := mercator.EncodeCoordsExact(nil, projected)
+	assert.NoError(t, err)
+	assert.Equal(t, string(buf), string(exact))
+	assert.NoError(t, mercator.AuditPrecision(projected))
+}
@@ -0,0 +1,46 @@
+package polyline
+
+import "math"
+
+// Curvature returns the curvature at each vertex of coords, in 1/meters. The
+// curvature at a vertex is the reciprocal of the radius of the circle through
+// it and its neighbors. The curvature at the endpoints, and at vertices that
+// are collinear with or coincide with a neighbor, is zero.
+func Curvature(coords [][]float64) []float64 {
+	result := make([]float64, len(coords))
+	for i := 1; i+1 < len(coords); i++ {
+		result[i] = curvature(coords[i-1], coords[i], coords[i+1])
+	}
+	return result
+}
+
+// A Bend is a vertex where a polyline turns sharply.
+type Bend struct {
+	Index  int     // Index of the vertex
+	Radius float64 // Radius of the bend in meters
+}
+
+// SharpBends returns the bends in coords with a radius smaller than
+// minRadius meters, for example to find turns that are too tight for long
+// vehicles.
+func SharpBends(coords [][]float64, minRadius float64) []Bend {
+	var bends []Bend
+	for i, k := range Curvature(coords) {
+		if k == 0 {
+			continue
+		}
+		if radius := 1 / k; radius < minRadius {
+			bends = append(bends, Bend{Index: i, Radius: radius})
+		}
+	}
+	return bends
+}
+
+// maxCurvature returns the maximum curvature of coords.
+func maxCurvature(coords [][]float64) float64 {
+	var max float64
+	for _, k := range Curvature(coords) {
+		max = math.Max(max, k)
+	}
+	return max
+}
@@ -0,0 +1,34 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurvature(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{
+		{0, 0},
+		{0.00899, 0.00899},
+		{0, 0.01798},
+		{0, 0.0299},
+		{0, 0.03},
+		{0.0001, 0.0301},
+	}
+	got := polyline.Curvature(coords)
+	assert.Len(t, got, len(coords))
+	assert.Zero(t, got[0])
+	assert.InDelta(t, 1e-3, got[1], 1e-5)
+	assert.Zero(t, got[3])
+	assert.Zero(t, got[5])
+
+	bends := polyline.SharpBends(coords, 100)
+	if assert.Len(t, bends, 1) {
+		assert.Equal(t, 4, bends[0].Index)
+		assert.InDelta(t, 17.6, bends[0].Radius, 0.1)
+	}
+	assert.Len(t, polyline.SharpBends(coords, 2000), 3)
+	assert.Empty(t, polyline.Curvature(nil))
+}
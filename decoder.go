@@ -0,0 +1,84 @@
+package polyline
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// A Decoder decodes coordinates incrementally from a stream, without holding
+// the whole encoded polyline in memory. A Decoder is not safe for concurrent
+// use.
+type Decoder struct {
+	c    Codec
+	r    io.ByteReader
+	last []int
+	buf  [strconv.IntSize/ValueBits + 1]byte
+}
+
+// NewDecoder returns a new Decoder that reads from r using c. If r is not an
+// io.ByteReader then it is buffered.
+func (c Codec) NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Decoder{
+		c:    c,
+		r:    br,
+		last: make([]int, c.Dim),
+	}
+}
+
+// NewDecoder returns a new Decoder that reads from r using the default codec.
+func NewDecoder(r io.Reader) *Decoder {
+	return defaultCodec.NewDecoder(r)
+}
+
+// readInt reads and decodes a single signed integer. It returns io.EOF if the
+// stream ends before the integer starts.
+func (d *Decoder) readInt() (int, error) {
+	n := 0
+	for {
+		b, err := d.r.ReadByte()
+		switch {
+		case err == io.EOF && n == 0:
+			return 0, io.EOF
+		case err == io.EOF:
+			return 0, ErrUnterminatedSequence
+		case err != nil:
+			return 0, err
+		}
+		d.buf[n] = b
+		n++
+		if b < ContinuationByte || b > MaxByte || n == len(d.buf) {
+			break
+		}
+	}
+	k, _, err := decodeInt(d.buf[:n])
+	return k, err
+}
+
+// Decode decodes and returns the next coordinate. It returns io.EOF when the
+// stream ends cleanly between coordinates, and ErrEmpty or
+// ErrUnterminatedSequence if it ends in the middle of one.
+func (d *Decoder) Decode() ([]float64, error) {
+	coord := make([]float64, d.c.Dim)
+	for j := range coord {
+		k, err := d.readInt()
+		switch {
+		case err == io.EOF && j == 0:
+			return nil, io.EOF
+		case err == io.EOF:
+			return nil, ErrEmpty
+		case err != nil:
+			return nil, err
+		}
+		d.last[j] += k
+		coord[j] = float64(d.last[j]) / d.c.scale(j)
+	}
+	if d.c.Transformer != nil {
+		d.c.fromWGS84Flat(coord)
+	}
+	return coord, nil
+}
@@ -0,0 +1,99 @@
+package polyline_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeAll(d *polyline.Decoder) ([][]float64, error) {
+	var coords [][]float64
+	for {
+		coord, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return coords, nil
+		}
+		if err != nil {
+			return coords, err
+		}
+		coords = append(coords, coord)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		s        string
+		expected [][]float64
+		err      error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "coords",
+			s:        "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			expected: [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}},
+		},
+		{
+			name:     "half_coord",
+			s:        "_p~iF~ps|U_ulL",
+			expected: [][]float64{{38.5, -120.2}},
+			err:      polyline.ErrEmpty,
+		},
+		{
+			name:     "unterminated",
+			s:        "_p~iF~ps|U_ulLnnq",
+			expected: [][]float64{{38.5, -120.2}},
+			err:      polyline.ErrUnterminatedSequence,
+		},
+		{
+			name:     "invalid_byte",
+			s:        "_p~iF~ps|U!",
+			expected: [][]float64{{38.5, -120.2}},
+			err:      polyline.ErrInvalidByte,
+		},
+		{
+			name: "overflow",
+			s:    "________________?",
+			err:  polyline.ErrOverflow,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// OneByteReader hides io.ByteReader so the Decoder buffers.
+			got, err := decodeAll(polyline.NewDecoder(iotest.OneByteReader(strings.NewReader(tc.s))))
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, got)
+
+			want, _, wantErr := polyline.DecodeCoords([]byte(tc.s))
+			if wantErr == nil {
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+}
+
+func TestDecoderReadError(t *testing.T) {
+	t.Parallel()
+	d := polyline.NewDecoder(iotest.TimeoutReader(bytes.NewReader([]byte("_p~iF~ps|U_ulLnnqC"))))
+	_, err := decodeAll(d)
+	assert.ErrorIs(t, err, iotest.ErrTimeout)
+}
+
+func TestDecoderCodec(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 3, Scale: 1e6}
+	coords := [][]float64{{1, 2, 3}, {-1.5, 2.25, 100}, {0, 0, 0}}
+	got, err := decodeAll(codec.NewDecoder(bytes.NewReader(codec.EncodeCoords(nil, coords))))
+	assert.NoError(t, err)
+	assert.Equal(t, coords, got)
+}
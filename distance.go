@@ -0,0 +1,129 @@
+package polyline
+
+import "math"
+
+// A DistanceModel computes the distance in meters between two coordinates.
+// Functions that measure distances take a DistanceModel so that callers can
+// trade accuracy for speed per call; a nil DistanceModel means Haversine.
+type DistanceModel interface {
+	Distance(a, b []float64) float64
+}
+
+// haversineModel is the DistanceModel returned by Haversine.
+type haversineModel struct{}
+
+func (haversineModel) Distance(a, b []float64) float64 {
+	return haversine(a, b)
+}
+
+// Haversine is the great-circle distance on a spherical Earth of mean radius.
+// It is within about 0.5% of the geodesic distance.
+var Haversine DistanceModel = haversineModel{}
+
+// equirectangularModel is the DistanceModel returned by Equirectangular.
+type equirectangularModel struct{}
+
+func (equirectangularModel) Distance(a, b []float64) float64 {
+	dLng := math.Remainder(b[1]-a[1], 360) * math.Cos(radians((a[0]+b[0])/2))
+	dLat := b[0] - a[0]
+	return math.Hypot(dLng, dLat) * metersPerDegree
+}
+
+// Equirectangular is the distance in an equirectangular projection about the
+// mean latitude of the two coordinates. It is several times faster than
+// Haversine and within 0.1% of it for coordinates less than about 10km apart,
+// such as the consecutive fixes of a dense track. For a whole track near one
+// place a LocalProjection is faster still.
+var Equirectangular DistanceModel = equirectangularModel{}
+
+// WGS84 ellipsoid parameters.
+const (
+	wgs84A = 6378137
+	wgs84F = 1 / 298.257223563
+	wgs84B = wgs84A * (1 - wgs84F)
+)
+
+// vincentyModel is the DistanceModel returned by Vincenty.
+type vincentyModel struct{}
+
+func (vincentyModel) Distance(a, b []float64) float64 {
+	d, ok := vincenty(a, b)
+	if !ok {
+		return karney(a, b)
+	}
+	return d
+}
+
+// Vincenty is the geodesic distance on the WGS84 ellipsoid computed with
+// Vincenty's inverse formula, which is accurate to within a millimeter. For
+// nearly antipodal coordinates, where the formula does not converge, it
+// falls back to Karney.
+var Vincenty DistanceModel = vincentyModel{}
+
+// karneyModel is the DistanceModel returned by Karney.
+type karneyModel struct{}
+
+func (karneyModel) Distance(a, b []float64) float64 {
+	return karney(a, b)
+}
+
+// Karney is the geodesic distance on the WGS84 ellipsoid computed with
+// Karney's algorithm, as used by GeographicLib. It is accurate to about 15
+// nanometers for all coordinates, including nearly antipodal ones, making it
+// suitable for surveying-grade lengths, and is about half as fast as
+// Vincenty.
+var Karney DistanceModel = karneyModel{}
+
+// vincenty returns the geodesic distance between a and b on the WGS84
+// ellipsoid, and false if Vincenty's inverse formula does not converge.
+func vincenty(a, b []float64) (float64, bool) {
+	l := radians(b[1] - a[1])
+	u1 := math.Atan((1 - wgs84F) * math.Tan(radians(a[0])))
+	u2 := math.Atan((1 - wgs84F) * math.Tan(radians(b[0])))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := l
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, true // Coincident points
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0 // Equatorial line
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		c := wgs84F / 16 * cosSqAlpha * (4 + wgs84F*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = l + (1-c)*wgs84F*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) > 1e-12 {
+			continue
+		}
+
+		uSq := cosSqAlpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+		bigA := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+		bigB := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+		deltaSigma := bigB * sinSigma * (cos2SigmaM + bigB/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+			bigB/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+		return wgs84B * bigA * (sigma - deltaSigma), true
+	}
+	return 0, false
+}
+
+// Length returns the length of the path coords in meters, measured with
+// model.
+func Length(coords [][]float64, model DistanceModel) float64 {
+	if model == nil {
+		model = Haversine
+	}
+	var length float64
+	for i := 1; i < len(coords); i++ {
+		length += model.Distance(coords[i-1], coords[i])
+	}
+	return length
+}
@@ -0,0 +1,91 @@
+package polyline_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceModels(t *testing.T) {
+	t.Parallel()
+	// Flinders Peak to Buninyong, from Vincenty's paper.
+	flinders := []float64{-37.95103342, 144.42486789}
+	buninyong := []float64{-37.65282114, 143.92649554}
+	for _, tc := range []struct {
+		name     string
+		model    polyline.DistanceModel
+		a, b     []float64
+		expected float64
+		delta    float64
+	}{
+		{name: "vincenty", model: polyline.Vincenty, a: flinders, b: buninyong, expected: 54972.271, delta: 0.001},
+		{name: "vincenty_equator", model: polyline.Vincenty, a: []float64{0, 0}, b: []float64{0, 1}, expected: 111319.491, delta: 0.001},
+		{name: "vincenty_meridian", model: polyline.Vincenty, a: []float64{0, 0}, b: []float64{1, 0}, expected: 110574.389, delta: 0.001},
+		{name: "vincenty_coincident", model: polyline.Vincenty, a: flinders, b: flinders},
+		// Vincenty's formula does not converge for nearly antipodal points.
+		{name: "vincenty_antipodal", model: polyline.Vincenty, a: []float64{0, 0}, b: []float64{0.5, 179.7}, expected: 19944127.421, delta: 0.001},
+		// JFK to LHR, from the GeographicLib documentation.
+		{name: "karney", model: polyline.Karney, a: []float64{40.6, -73.8}, b: []float64{51.6, -0.5}, expected: 5551759.400319, delta: 1e-6},
+		{name: "karney_vincenty", model: polyline.Karney, a: flinders, b: buninyong, expected: polyline.Vincenty.Distance(flinders, buninyong), delta: 1e-6},
+		{name: "karney_antipodal", model: polyline.Karney, a: []float64{0, 0}, b: []float64{0.5, 179.7}, expected: 19944127.421, delta: 0.001},
+		{name: "karney_poles", model: polyline.Karney, a: []float64{0, 0}, b: []float64{0, 180}, expected: 20003931.459, delta: 0.001},
+		{name: "karney_pole", model: polyline.Karney, a: []float64{0, 0}, b: []float64{90, 0}, expected: 10001965.729, delta: 0.001},
+		{name: "karney_equator", model: polyline.Karney, a: []float64{0, 0}, b: []float64{0, 1}, expected: 111319.491, delta: 0.001},
+		{name: "karney_coincident", model: polyline.Karney, a: flinders, b: flinders},
+		{name: "haversine", model: polyline.Haversine, a: flinders, b: buninyong, expected: 54902.0, delta: 200},
+		{name: "equirectangular", model: polyline.Equirectangular, a: flinders, b: buninyong, expected: polyline.Haversine.Distance(flinders, buninyong), delta: 50},
+		{name: "equirectangular_antimeridian", model: polyline.Equirectangular, a: []float64{0, 179.999}, b: []float64{0, -179.999}, expected: 222.4, delta: 0.1},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tc.expected, tc.model.Distance(tc.a, tc.b), tc.delta)
+		})
+	}
+}
+
+func TestLength(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {0, 1}, {1, 1}}
+	assert.InDelta(t, 111319.491+110575.0, polyline.Length(coords, polyline.Vincenty), 1)
+	assert.Equal(t, polyline.Length(coords, polyline.Haversine), polyline.Length(coords, nil))
+	assert.InDelta(t, 2*111195.08, polyline.Length(coords, nil), 0.01)
+	assert.Equal(t, 0.0, polyline.Length(coords[:1], nil))
+}
+
+// doubledModel measures twice the Haversine distance, to check that
+// functions taking a DistanceModel measure with it.
+type doubledModel struct{}
+
+func (doubledModel) Distance(a, b []float64) float64 {
+	return 2 * polyline.Haversine.Distance(a, b)
+}
+
+// latitudeModel measures only the difference in latitude.
+type latitudeModel struct{}
+
+func (latitudeModel) Distance(a, b []float64) float64 {
+	return math.Abs(a[0]-b[0]) * 111195
+}
+
+func BenchmarkDistanceModels(b *testing.B) {
+	coords := benchmarkCoords(1024)
+	for _, bc := range []struct {
+		name  string
+		model polyline.DistanceModel
+	}{
+		{name: "haversine", model: polyline.Haversine},
+		{name: "equirectangular", model: polyline.Equirectangular},
+		{name: "vincenty", model: polyline.Vincenty},
+		{name: "karney", model: polyline.Karney},
+	} {
+		bc := bc
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				polyline.Length(coords, bc.model)
+			}
+		})
+	}
+}
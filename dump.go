@@ -0,0 +1,54 @@
+package polyline
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Dump writes a table of the values encoded in the polyline buf to w, one row
+// per value, for debugging encoding mismatches between implementations. Each
+// row holds the index of the point and of its dimension, the offset of the
+// value in buf, its raw bytes as text and in hex, the delta they decode to,
+// the running integer after adding the delta, and the coordinate value it
+// scales to. If buf does not decode, Dump writes the rows before the error
+// and returns the error with its offset.
+func Dump(w io.Writer, buf []byte, c Codec) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "POINT\tDIM\tOFFSET\tBYTES\tHEX\tDELTA\tVALUE\tCOORD")
+	last := make([]int, c.Dim)
+	var derr error
+	for offset, i := 0, 0; offset < len(buf) && derr == nil; i++ {
+		for j := range last {
+			k, rest, err := decodeInt(buf[offset:])
+			if err != nil {
+				derr = fmt.Errorf("%w: offset %d", err, offset)
+				break
+			}
+			raw := buf[offset : len(buf)-len(rest)]
+			last[j] += k
+			fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%s\t%d\t%d\t%s\n",
+				i, j, offset, raw, hexBytes(raw), k, last[j],
+				strconv.FormatFloat(float64(last[j])/c.scale(j), 'f', -1, 64))
+			offset += len(raw)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return derr
+}
+
+// hexBytes returns the bytes of buf in hex separated by spaces.
+func hexBytes(buf []byte) string {
+	var sb strings.Builder
+	for i, b := range buf {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%02x", b)
+	}
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+package polyline_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+	var sb strings.Builder
+	assert.NoError(t, polyline.Dump(&sb, []byte("_p~iF~ps|U_ulLnnqC"), polyline.DefaultCodec()))
+	assert.Equal(t, ""+
+		"POINT  DIM  OFFSET  BYTES  HEX             DELTA      VALUE      COORD\n"+
+		"0      0    0       _p~iF  5f 70 7e 69 46  3850000    3850000    38.5\n"+
+		"0      1    5       ~ps|U  7e 70 73 7c 55  -12020000  -12020000  -120.2\n"+
+		"1      0    10      _ulL   5f 75 6c 4c     220000     4070000    40.7\n"+
+		"1      1    14      nnqC   6e 6e 71 43     -75000     -12095000  -120.95\n",
+		sb.String())
+
+	sb.Reset()
+	err := polyline.Dump(&sb, []byte("_p~iF~ps|U_ul"), polyline.DefaultCodec())
+	assert.True(t, errors.Is(err, polyline.ErrUnterminatedSequence))
+	assert.EqualError(t, err, "unterminated sequence: offset 10")
+	assert.Equal(t, 3, strings.Count(sb.String(), "\n"))
+
+	sb.Reset()
+	assert.NoError(t, polyline.Dump(&sb, nil, polyline.DefaultCodec()))
+	assert.Equal(t, 1, strings.Count(sb.String(), "\n"))
+}
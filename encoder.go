@@ -0,0 +1,55 @@
+package polyline
+
+import "io"
+
+// An Encoder encodes coordinates incrementally to a stream, keeping the delta
+// state between coordinates itself, for example to encode a live GPS feed
+// directly into an HTTP response body. An Encoder is not safe for concurrent
+// use.
+type Encoder struct {
+	c   Codec
+	w   io.Writer
+	s   *deltaState
+	buf []byte
+	err error
+}
+
+// NewEncoder returns a new Encoder that writes to w using c.
+func (c Codec) NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		c: c,
+		w: w,
+		s: c.newDeltaState(),
+	}
+}
+
+// NewEncoder returns a new Encoder that writes to w using the default codec.
+func NewEncoder(w io.Writer) *Encoder {
+	return defaultCodec.NewEncoder(w)
+}
+
+// Encode encodes coord and writes it to the underlying writer with a single
+// call to Write. It returns ErrDimensionalMismatch if coord does not have the
+// codec's dimensionality. Once a write fails, Encode returns the same error
+// for every further coordinate, as the delta state is lost.
+func (e *Encoder) Encode(coord []float64) error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(coord) != len(e.s.last) {
+		return ErrDimensionalMismatch
+	}
+	if t := e.c.Transformer; t != nil {
+		coord = cloneCoord(coord)
+		coord[0], coord[1] = t.ToWGS84(coord[0], coord[1])
+	}
+	e.buf = e.buf[:0]
+	e.s.deltas(coord, func(delta int) {
+		e.buf = encodeInt(e.buf, delta)
+	})
+	if len(e.buf) == 0 {
+		return nil
+	}
+	_, e.err = e.w.Write(e.buf)
+	return e.err
+}
@@ -0,0 +1,75 @@
+package polyline_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoder(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	for _, codec := range []polyline.Codec{
+		polyline.DefaultCodec(),
+		{Dim: 2, Scale: 1e6, Rounding: polyline.RoundJS},
+		{Dim: 2, Scale: 1e5, CoalesceQuantumDuplicates: true},
+		{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator},
+	} {
+		input := coords
+		if codec.Transformer != nil {
+			input = make([][]float64, len(coords))
+			for i, coord := range coords {
+				x, y := polyline.WebMercator.FromWGS84(coord[0], coord[1])
+				input[i] = []float64{x, y}
+			}
+		}
+		var buf bytes.Buffer
+		e := codec.NewEncoder(&buf)
+		for _, coord := range input {
+			assert.NoError(t, e.Encode(coord))
+		}
+		assert.Equal(t, string(codec.EncodeCoords(nil, input)), buf.String())
+	}
+}
+
+func TestEncoderCoalesce(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5, CoalesceQuantumDuplicates: true}
+	coords := [][]float64{{1, 1}, {1.000004, 1}, {0.999996, 1}, {1.00001, 1}}
+	var w countingWriter
+	e := codec.NewEncoder(&w)
+	for _, coord := range coords {
+		assert.NoError(t, e.Encode(coord))
+	}
+	assert.Equal(t, string(codec.EncodeCoords(nil, coords)), w.buf.String())
+	assert.Equal(t, 2, w.writes)
+}
+
+func TestEncoderErrors(t *testing.T) {
+	t.Parallel()
+	e := polyline.NewEncoder(&bytes.Buffer{})
+	assert.ErrorIs(t, e.Encode([]float64{1, 2, 3}), polyline.ErrDimensionalMismatch)
+
+	errWrite := errors.New("write")
+	e = polyline.NewEncoder(&countingWriter{err: errWrite})
+	assert.ErrorIs(t, e.Encode([]float64{1, 2}), errWrite)
+	assert.ErrorIs(t, e.Encode([]float64{1, 2}), errWrite)
+}
+
+// countingWriter is a writer that counts calls to Write and optionally fails.
+type countingWriter struct {
+	buf    bytes.Buffer
+	writes int
+	err    error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
@@ -0,0 +1,43 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidSpeed is returned by ETA when a speed is not positive.
+var ErrInvalidSpeed = errors.New("invalid speed")
+
+// ETA returns the time needed to travel the rest of route from the point the
+// given fraction of its length along it, as returned by ProgressAlong, where
+// speeds[i] is the speed in meters per second on segment i, for example from
+// Annotations.Speed, and the arrival time when continuing at now. It returns
+// ErrDimensionalMismatch if there is not one speed per segment and
+// ErrInvalidSpeed if a speed on the rest of the route is not positive.
+func ETA(route [][]float64, speeds []float64, fraction float64, now time.Time) (time.Duration, time.Time, error) {
+	if len(route) < 2 {
+		return 0, now, nil
+	}
+	if len(speeds) != len(route)-1 {
+		return 0, time.Time{}, fmt.Errorf("%w: %d speeds for %d segments", ErrDimensionalMismatch, len(speeds), len(route)-1)
+	}
+	cum := cumulativeDistances(route)
+	along := math.Max(0, math.Min(1, fraction)) * cum[len(cum)-1]
+	_, first := pointAtDistance(route, cum, along)
+
+	var seconds float64
+	for i := first; i < len(speeds); i++ {
+		d := cum[i+1] - math.Max(along, cum[i])
+		if d <= 0 {
+			continue
+		}
+		if !(speeds[i] > 0) {
+			return 0, time.Time{}, fmt.Errorf("%w: %g on segment %d", ErrInvalidSpeed, speeds[i], i)
+		}
+		seconds += d / speeds[i]
+	}
+	remaining := time.Duration(seconds * float64(time.Second))
+	return remaining, now.Add(remaining), nil
+}
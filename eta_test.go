@@ -0,0 +1,49 @@
+package polyline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETA(t *testing.T) {
+	t.Parallel()
+	// Two segments of 0.01 degrees of latitude, about 1112m each.
+	route := [][]float64{{0, 0}, {0.01, 0}, {0.02, 0}}
+	speeds := []float64{10, 20}
+	now := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		fraction float64
+		expected float64
+	}{
+		{name: "start", fraction: 0, expected: 111.2 + 55.6},
+		{name: "quarter", fraction: 0.25, expected: 55.6 + 55.6},
+		{name: "half", fraction: 0.5, expected: 55.6},
+		{name: "three_quarters", fraction: 0.75, expected: 27.8},
+		{name: "end", fraction: 1, expected: 0},
+		{name: "beyond", fraction: 2, expected: 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			remaining, arrival, err := polyline.ETA(route, speeds, tc.fraction, now)
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expected, remaining.Seconds(), 0.1)
+			assert.Equal(t, now.Add(remaining), arrival)
+		})
+	}
+
+	_, _, err := polyline.ETA(route, []float64{10}, 0, now)
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+	_, _, err = polyline.ETA(route, []float64{10, 0}, 0, now)
+	assert.ErrorIs(t, err, polyline.ErrInvalidSpeed)
+	_, _, err = polyline.ETA(route, []float64{0, 10}, 0.75, now)
+	assert.NoError(t, err)
+	remaining, arrival, err := polyline.ETA(route[:1], nil, 0, now)
+	assert.NoError(t, err)
+	assert.Zero(t, remaining)
+	assert.Equal(t, now, arrival)
+}
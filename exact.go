@@ -0,0 +1,127 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// ErrPrecisionLoss is returned when exact arithmetic shows that encoding loses
+// more precision than quantization alone.
+var ErrPrecisionLoss = errors.New("precision loss")
+
+var (
+	bigHalf  = big.NewRat(1, 2)
+	minInt64 = new(big.Rat).SetInt64(math.MinInt64)
+	maxInt64 = new(big.Rat).SetInt64(math.MaxInt64)
+)
+
+// quantizeExact returns x, a value of dimension i, scaled and rounded to an
+// integer with exact rational arithmetic, following c.Rounding. It returns
+// an error if the result does not fit in an int64.
+func (c Codec) quantizeExact(i int, x float64) (int64, error) {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0, fmt.Errorf("%w: %g is not finite", ErrPrecisionLoss, x)
+	}
+	scaled := new(big.Rat).SetFloat64(x)
+	scaled.Mul(scaled, new(big.Rat).SetFloat64(c.scale(i)))
+	if scaled.Cmp(minInt64) < 0 || scaled.Cmp(maxInt64) > 0 {
+		return 0, fmt.Errorf("%w: %g overflows", ErrPrecisionLoss, x)
+	}
+
+	// floor is scaled rounded towards negative infinity, and frac is the
+	// remaining fractional part in [0, 1).
+	floor := new(big.Int).Div(scaled.Num(), scaled.Denom())
+	frac := new(big.Rat).Sub(scaled, new(big.Rat).SetInt(floor))
+	result := floor.Int64()
+	switch cmp := frac.Cmp(bigHalf); {
+	case cmp > 0:
+		result++
+	case cmp == 0 && (c.Rounding == RoundJS || scaled.Sign() > 0):
+		result++
+	}
+	return result, nil
+}
+
+// EncodeCoordsExact appends the encoding of coords to buf, as EncodeCoords
+// does, but quantizes with exact rational arithmetic and accumulates deltas
+// in int64. It returns an error wrapping ErrPrecisionLoss if any value
+// cannot be represented exactly, or if the output differs from that of
+// EncodeCoords, which would mean that EncodeCoords loses precision beyond
+// quantization. It is much slower than EncodeCoords and is intended to audit
+// the codec on representative data, for example in billing-sensitive
+// pipelines. With a Transformer, coords are transformed to WGS84 before they
+// are quantized.
+func (c Codec) EncodeCoordsExact(buf []byte, coords [][]float64) ([]byte, error) {
+	if c.CoalesceQuantumDuplicates {
+		return nil, fmt.Errorf("%w: exact encoding does not support CoalesceQuantumDuplicates", ErrPrecisionLoss)
+	}
+	start := len(buf)
+	last := make([]int64, c.Dim)
+	for i, coord := range coords {
+		if len(coord) != c.Dim {
+			return nil, ErrDimensionalMismatch
+		}
+		if c.Transformer != nil {
+			coord = cloneCoord(coord)
+			coord[0], coord[1] = c.Transformer.ToWGS84(coord[0], coord[1])
+		}
+		for j, x := range coord {
+			ex, err := c.quantizeExact(j, x)
+			if err != nil {
+				return nil, fmt.Errorf("coordinate %d: %w", i, err)
+			}
+			if got := c.quantize(j, x); int64(got) != ex {
+				return nil, fmt.Errorf("%w: coordinate %d: %g quantized to %d, want %d", ErrPrecisionLoss, i, x, got, ex)
+			}
+			delta := ex - last[j]
+			if (ex >= 0) != (last[j] >= 0) && (delta >= 0) != (ex >= 0) {
+				return nil, fmt.Errorf("%w: coordinate %d: delta overflows", ErrPrecisionLoss, i)
+			}
+			buf = encodeInt(buf, int(delta))
+			last[j] = ex
+		}
+	}
+	if fast := c.EncodeCoords(nil, coords); string(fast) != string(buf[start:]) {
+		return nil, fmt.Errorf("%w: encodings differ", ErrPrecisionLoss)
+	}
+	return buf, nil
+}
+
+// AuditPrecision verifies, with exact arithmetic, that encoding and decoding
+// coords with c loses no precision beyond quantization: every value is
+// quantized exactly, and every decoded value is the float64 nearest to its
+// quantized value and within half a quantum of the original. It returns an
+// error wrapping ErrPrecisionLoss describing the first problem. With a
+// Transformer, the audit applies to the coordinates transformed to WGS84,
+// which are what the polyline encodes.
+func (c Codec) AuditPrecision(coords [][]float64) error {
+	buf, err := c.EncodeCoordsExact(nil, coords)
+	if err != nil {
+		return err
+	}
+	if c.Transformer != nil {
+		coords, c = c.toWGS84(coords), c.untransformed()
+	}
+	decoded, _, err := c.DecodeCoords(buf)
+	if err != nil {
+		return err
+	}
+	for i, coord := range coords {
+		for j, x := range coord {
+			scale := new(big.Rat).SetFloat64(c.scale(j))
+			halfQuantum := new(big.Rat).Quo(bigHalf, scale)
+			ex, _ := c.quantizeExact(j, x)
+			want := new(big.Rat).Quo(new(big.Rat).SetInt64(ex), scale)
+			if nearest, _ := want.Float64(); decoded[i][j] != nearest {
+				return fmt.Errorf("%w: coordinate %d: decoded %g, want %g", ErrPrecisionLoss, i, decoded[i][j], nearest)
+			}
+			diff := new(big.Rat).Sub(want, new(big.Rat).SetFloat64(x))
+			if diff.Abs(diff).Cmp(halfQuantum) > 0 {
+				return fmt.Errorf("%w: coordinate %d: %g moved more than half a quantum", ErrPrecisionLoss, i, x)
+			}
+		}
+	}
+	return nil
+}
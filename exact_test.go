@@ -0,0 +1,74 @@
+package polyline_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeCoordsExact(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	got, err := codec.EncodeCoordsExact([]byte("x"), coords)
+	assert.NoError(t, err)
+	assert.Equal(t, "x_p~iF~ps|U_ulLnnqC_mqNvxq`@", string(got))
+
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, codec.AuditPrecision(polyline.Generate(r, polyline.GenOptions{})))
+	}
+	jsCodec := polyline.Codec{Dim: 2, Scale: 1e5, Rounding: polyline.RoundJS}
+	assert.NoError(t, jsCodec.AuditPrecision([][]float64{{0.000025, 38.5}}))
+	// The float64 nearest to -0.000025 is slightly smaller than -2.5e-5, but
+	// multiplying it by 1e5 rounds to exactly -2.5.
+	assert.ErrorIs(t, jsCodec.AuditPrecision([][]float64{{-0.000025, 0}}), polyline.ErrPrecisionLoss)
+}
+
+func TestEncodeCoordsExactErrors(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	for _, tc := range []struct {
+		name   string
+		codec  polyline.Codec
+		coords [][]float64
+		err    error
+	}{
+		{
+			// The float64 nearest to 0.000155 is slightly smaller than
+			// 1.55e-4, but multiplying it by 1e5 rounds to exactly 15.5.
+			name:   "double_rounding",
+			codec:  codec,
+			coords: [][]float64{{0.000155, 0}},
+			err:    polyline.ErrPrecisionLoss,
+		},
+		{
+			name:   "nan",
+			codec:  codec,
+			coords: [][]float64{{math.NaN(), 0}},
+			err:    polyline.ErrPrecisionLoss,
+		},
+		{
+			name:   "overflow",
+			codec:  codec,
+			coords: [][]float64{{1e300, 0}},
+			err:    polyline.ErrPrecisionLoss,
+		},
+		{
+			name:   "dimension",
+			codec:  codec,
+			coords: [][]float64{{0}},
+			err:    polyline.ErrDimensionalMismatch,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tc.codec.EncodeCoordsExact(nil, tc.coords)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -22,3 +22,9 @@ func ExampleDecodeCoords() {
 	fmt.Println(coords)
 	// Output: [[38.5 -120.2] [40.7 -120.95] [43.252 -126.453]]
 }
+
+func ExampleSafeDecode() {
+	coords, err := polyline.SafeDecode([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+	fmt.Println(coords, err)
+	// Output: [[38.5 -120.2] [40.7 -120.95] [43.252 -126.453]] <nil>
+}
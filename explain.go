@@ -0,0 +1,52 @@
+package polyline
+
+import "fmt"
+
+// A ValueByte is one byte of an encoded value.
+type ValueByte struct {
+	Byte         byte // The encoded byte
+	Bits         uint // The ValueBits bits of the value carried by Byte
+	Continuation bool // Whether Byte has ContinuationBit set, so more bytes follow
+}
+
+// A ValueTrace explains how a single value is encoded.
+type ValueTrace struct {
+	Offset int         // Offset of the value's first byte
+	Bytes  []ValueByte // Bytes of the value, least significant bits first
+	ZigZag uint        // Unsigned value assembled from the bytes' bits
+	Value  int         // Signed value, ZigZag shifted right one bit, and inverted if its low bit is set
+}
+
+// Explain returns a trace of how each value in the polyline buf is encoded:
+// its 5-bit groups, their continuation bits, and the zigzag step from the
+// unsigned to the signed value, for teaching and diagnosing the encoding.
+// Values are the integer deltas of the encoding, independent of any codec.
+// If buf does not decode, Explain returns the traces of the values before
+// the error and the error with its offset.
+func Explain(buf []byte) ([]ValueTrace, error) {
+	var traces []ValueTrace
+	for offset := 0; offset < len(buf); {
+		k, rest, err := decodeInt(buf[offset:])
+		if err != nil {
+			return traces, fmt.Errorf("%w: offset %d", err, offset)
+		}
+		raw := buf[offset : len(buf)-len(rest)]
+		trace := ValueTrace{
+			Offset: offset,
+			Bytes:  make([]ValueByte, len(raw)),
+			Value:  k,
+		}
+		for i, b := range raw {
+			bits := uint(b-MinByte) & (ContinuationBit - 1)
+			trace.Bytes[i] = ValueByte{
+				Byte:         b,
+				Bits:         bits,
+				Continuation: b >= ContinuationByte,
+			}
+			trace.ZigZag |= bits << (ValueBits * uint(i))
+		}
+		traces = append(traces, trace)
+		offset += len(raw)
+	}
+	return traces, nil
+}
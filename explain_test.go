@@ -0,0 +1,53 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+	traces, err := polyline.Explain([]byte("_p~iF?@"))
+	assert.NoError(t, err)
+	assert.Equal(t, []polyline.ValueTrace{
+		{
+			Offset: 0,
+			Bytes: []polyline.ValueByte{
+				{Byte: '_', Bits: 0, Continuation: true},
+				{Byte: 'p', Bits: 17, Continuation: true},
+				{Byte: '~', Bits: 31, Continuation: true},
+				{Byte: 'i', Bits: 10, Continuation: true},
+				{Byte: 'F', Bits: 7, Continuation: false},
+			},
+			ZigZag: 7700000,
+			Value:  3850000,
+		},
+		{
+			Offset: 5,
+			Bytes:  []polyline.ValueByte{{Byte: '?', Bits: 0}},
+			ZigZag: 0,
+			Value:  0,
+		},
+		{
+			Offset: 6,
+			Bytes:  []polyline.ValueByte{{Byte: '@', Bits: 1}},
+			ZigZag: 1,
+			Value:  -1,
+		},
+	}, traces)
+
+	traces, err = polyline.Explain([]byte("?_p"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	assert.EqualError(t, err, "unterminated sequence: offset 1")
+	assert.Len(t, traces, 1)
+
+	traces, err = polyline.Explain([]byte("? "))
+	assert.ErrorIs(t, err, polyline.ErrInvalidByte)
+	assert.Len(t, traces, 1)
+
+	traces, err = polyline.Explain(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, traces)
+}
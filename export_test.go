@@ -0,0 +1,13 @@
+package polyline
+
+var (
+	DecodeUint  = decodeUint
+	DecodeInt   = decodeInt
+	DecodeCoord = decodeCoord
+	EncodeUint  = encodeUint
+	EncodeInt   = encodeInt
+)
+
+func EncodeCoord(coord []float64) []byte {
+	return defaultCodec.encodeCoord(nil, coord)
+}
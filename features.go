@@ -0,0 +1,112 @@
+package polyline
+
+import (
+	"math"
+	"time"
+)
+
+// FeatureOptions configures Analyze. Zero fields take default values.
+type FeatureOptions struct {
+	TurnDegrees  float64       // Minimum change in heading counted as a turn, default 45
+	MinSegment   float64       // Segments shorter than this in meters are ignored for turns, default 5
+	StopSpeed    float64       // Speed in meters per second below which the track is stopped, default 0.5
+	StopDuration time.Duration // Minimum duration of a stop, default 30s
+}
+
+// withDefaults returns o with zero fields replaced by their defaults.
+func (o FeatureOptions) withDefaults() FeatureOptions {
+	if o.TurnDegrees == 0 {
+		o.TurnDegrees = 45
+	}
+	if o.MinSegment == 0 {
+		o.MinSegment = 5
+	}
+	if o.StopSpeed == 0 {
+		o.StopSpeed = 0.5
+	}
+	if o.StopDuration == 0 {
+		o.StopDuration = 30 * time.Second
+	}
+	return o
+}
+
+// TripFeatures are features of a trip for use in machine learning pipelines.
+type TripFeatures struct {
+	Distance          float64       // Length in meters
+	StraightLine      float64       // Distance between the endpoints in meters
+	StraightLineRatio float64       // StraightLine divided by Distance, or zero if Distance is zero
+	TurnCount         int           // Number of changes in heading of at least TurnDegrees
+	MaxCurvature      float64       // Maximum curvature at any vertex, in 1/meters
+	StopCount         int           // Number of stops, zero without times
+	Duration          time.Duration // Duration, zero without times
+	AverageSpeed      float64       // Average speed in meters per second, zero without times
+}
+
+// Analyze computes the features of the trip coords. times, if not nil, are
+// the times of each coordinate and enable the time-based features. It returns
+// ErrDimensionalMismatch if times is not nil and has a different length to
+// coords.
+func Analyze(coords [][]float64, times []time.Time, opts FeatureOptions) (TripFeatures, error) {
+	if times != nil && len(times) != len(coords) {
+		return TripFeatures{}, ErrDimensionalMismatch
+	}
+	opts = opts.withDefaults()
+
+	var f TripFeatures
+	if len(coords) == 0 {
+		return f, nil
+	}
+	f.Distance = pathLength(coords)
+	f.StraightLine = haversine(coords[0], coords[len(coords)-1])
+	if f.Distance > 0 {
+		f.StraightLineRatio = f.StraightLine / f.Distance
+	}
+
+	// Turns are measured between segments long enough to have a meaningful
+	// heading, so that GPS jitter while slow is not counted.
+	prevBearing := math.NaN()
+	for i, j := 0, 1; j < len(coords); j++ {
+		if haversine(coords[i], coords[j]) < opts.MinSegment {
+			continue
+		}
+		b := bearing(coords[i], coords[j])
+		if !math.IsNaN(prevBearing) && turnAngle(prevBearing, b) >= opts.TurnDegrees {
+			f.TurnCount++
+		}
+		prevBearing = b
+		i = j
+	}
+
+	f.MaxCurvature = maxCurvature(coords)
+
+	if times != nil {
+		f.Duration = times[len(times)-1].Sub(times[0])
+		if f.Duration > 0 {
+			f.AverageSpeed = f.Distance / f.Duration.Seconds()
+		}
+		f.StopCount = countStops(coords, times, opts)
+	}
+	return f, nil
+}
+
+// countStops returns the number of runs of segments slower than
+// opts.StopSpeed that last at least opts.StopDuration.
+func countStops(coords [][]float64, times []time.Time, opts FeatureOptions) int {
+	var stops int
+	var stopped time.Duration
+	for i := 1; i < len(coords); i++ {
+		dt := times[i].Sub(times[i-1])
+		if dt > 0 && haversine(coords[i-1], coords[i])/dt.Seconds() < opts.StopSpeed {
+			stopped += dt
+			continue
+		}
+		if stopped >= opts.StopDuration {
+			stops++
+		}
+		stopped = 0
+	}
+	if stopped >= opts.StopDuration {
+		stops++
+	}
+	return stops
+}
@@ -0,0 +1,57 @@
+package polyline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+	// An L-shaped trip of two 1.1km legs with a one minute stop at the
+	// corner.
+	coords := [][]float64{{0, 0}, {0.005, 0}, {0.01, 0}, {0.01, 0}, {0.01, 0.005}, {0.01, 0.01}}
+	t0 := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+	times := []time.Time{
+		t0,
+		t0.Add(1 * time.Minute),
+		t0.Add(2 * time.Minute),
+		t0.Add(3 * time.Minute),
+		t0.Add(4 * time.Minute),
+		t0.Add(5 * time.Minute),
+	}
+
+	f, err := polyline.Analyze(coords, times, polyline.FeatureOptions{})
+	assert.NoError(t, err)
+	assert.InDelta(t, 2223.9, f.Distance, 0.1)
+	assert.InDelta(t, 1572.5, f.StraightLine, 0.1)
+	assert.InDelta(t, 0.7071, f.StraightLineRatio, 1e-4)
+	assert.Equal(t, 1, f.TurnCount)
+	assert.Equal(t, 1, f.StopCount)
+	assert.Equal(t, 5*time.Minute, f.Duration)
+	assert.InDelta(t, 7.413, f.AverageSpeed, 1e-3)
+	assert.InDelta(t, 0, f.MaxCurvature, 1e-12)
+
+	f, err = polyline.Analyze(coords, nil, polyline.FeatureOptions{})
+	assert.NoError(t, err)
+	assert.Zero(t, f.StopCount)
+	assert.Zero(t, f.AverageSpeed)
+
+	_, err = polyline.Analyze(coords, times[1:], polyline.FeatureOptions{})
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+
+	f, err = polyline.Analyze(nil, nil, polyline.FeatureOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.TripFeatures{}, f)
+}
+
+func TestAnalyzeCurvature(t *testing.T) {
+	t.Parallel()
+	// Three points on a circle of radius 1km.
+	coords := [][]float64{{0, 0}, {0.00899, 0.00899}, {0, 0.01798}}
+	f, err := polyline.Analyze(coords, nil, polyline.FeatureOptions{})
+	assert.NoError(t, err)
+	assert.InDelta(t, 1e-3, f.MaxCurvature, 1e-5)
+}
@@ -0,0 +1,271 @@
+package polyline
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrFlatBuffer is returned by ReadFlatBuffer when the input is not a
+// TraceBatch FlatBuffer.
+var ErrFlatBuffer = errors.New("not a TraceBatch FlatBuffer")
+
+// Field ids of the Trace and TraceBatch tables defined in polyline.fbs.
+const (
+	fbTraceDim         = 0
+	fbTraceCoords      = 1
+	fbTraceBatchTraces = 0
+)
+
+// ToFlatBuffer decodes each of polylines and returns them as a TraceBatch
+// FlatBuffer, defined in polyline.fbs, whose coordinates can be read in
+// place, without parsing or copying, by any FlatBuffers implementation.
+// Coordinate vectors are aligned to eight bytes.
+func (c Codec) ToFlatBuffer(polylines [][]byte) ([]byte, error) {
+	if c.Dim > math.MaxUint8 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrFlatBuffer, c.Dim)
+	}
+	traces := make([][][]float64, len(polylines))
+	size := 32 + 8*len(polylines)
+	for i, buf := range polylines {
+		coords, _, err := c.untransformed().DecodeCoords(buf)
+		if err != nil {
+			return nil, fmt.Errorf("polyline %d: %w", i, err)
+		}
+		traces[i] = coords
+		size += 24 + 8*c.Dim*len(coords)
+	}
+
+	// The buffer is written front to back, so every offset points forwards
+	// and is patched once its target is written.
+	fb := make([]byte, 0, size)
+	fb = appendUint32(fb, 0) // Root offset
+	// Trace vtable: dim at 8 and coords at 4 in a 12 byte table.
+	traceVTable := len(fb)
+	fb = appendUint16(fb, 8)
+	fb = appendUint16(fb, 12)
+	fb = appendUint16(fb, 8)
+	fb = appendUint16(fb, 4)
+	// TraceBatch vtable: traces at 4 in an 8 byte table.
+	batchVTable := len(fb)
+	fb = appendUint16(fb, 6)
+	fb = appendUint16(fb, 8)
+	fb = appendUint16(fb, 4)
+	fb = fbPad(fb, 4, 0)
+	batch := len(fb)
+	fbPatchOffset(fb, 0, batch)
+	fb = appendUint32(fb, uint32(batch-batchVTable))
+	fb = appendUint32(fb, 0) // Traces offset
+	vector := len(fb)
+	fbPatchOffset(fb, batch+4, vector)
+	fb = appendUint32(fb, uint32(len(traces)))
+	fb = append(fb, make([]byte, 4*len(traces))...)
+	for i, coords := range traces {
+		table := len(fb)
+		fbPatchOffset(fb, vector+4+4*i, table)
+		fb = appendUint32(fb, uint32(table-traceVTable))
+		fb = appendUint32(fb, 0) // Coords offset
+		fb = append(fb, byte(c.Dim), 0, 0, 0)
+		fb = fbPad(fb, 8, 4)
+		fbPatchOffset(fb, table+4, len(fb))
+		fb = appendUint32(fb, uint32(c.Dim*len(coords)))
+		for _, coord := range coords {
+			for _, x := range coord {
+				fb = appendUint64(fb, math.Float64bits(x))
+			}
+		}
+	}
+	return fb, nil
+}
+
+// fbPad appends zeros to fb until its length is rem modulo align.
+func fbPad(fb []byte, align, rem int) []byte {
+	for len(fb)%align != rem {
+		fb = append(fb, 0)
+	}
+	return fb
+}
+
+// fbPatchOffset sets the offset at pos in fb to point to target.
+func fbPatchOffset(fb []byte, pos, target int) {
+	binary.LittleEndian.PutUint32(fb[pos:], uint32(target-pos))
+}
+
+// A FlatTraceBatch is a TraceBatch FlatBuffer whose traces are read in place.
+type FlatTraceBatch struct {
+	traces []FlatTrace
+}
+
+// A FlatTrace is a Trace of a FlatTraceBatch.
+type FlatTrace struct {
+	buf    []byte
+	dim    int
+	coords int // Position of the first value
+	n      int // Number of values
+}
+
+// ReadFlatBuffer returns a view of the TraceBatch FlatBuffer fb, as written
+// by Codec.ToFlatBuffer or by any FlatBuffers implementation from
+// polyline.fbs. The whole buffer is validated so that reading from the view
+// cannot fail, but coordinates are not copied. It returns ErrFlatBuffer if fb
+// is malformed or a trace's values are not a whole number of coordinates.
+func ReadFlatBuffer(fb []byte) (FlatTraceBatch, error) {
+	root, ok := fbOffset(fb, 0)
+	if !ok {
+		return FlatTraceBatch{}, fmt.Errorf("%w: bad root offset", ErrFlatBuffer)
+	}
+	var batch FlatTraceBatch
+	pos, ok := fbField(fb, root, fbTraceBatchTraces, 4)
+	if !ok {
+		return FlatTraceBatch{}, fmt.Errorf("%w: bad TraceBatch", ErrFlatBuffer)
+	}
+	if pos == 0 {
+		return batch, nil
+	}
+	vector, n, ok := fbVector(fb, pos, 4)
+	if !ok {
+		return FlatTraceBatch{}, fmt.Errorf("%w: bad traces", ErrFlatBuffer)
+	}
+	batch.traces = make([]FlatTrace, n)
+	for i := range batch.traces {
+		t := FlatTrace{buf: fb, dim: 2}
+		table, ok := fbOffset(fb, vector+4*i)
+		if !ok {
+			return FlatTraceBatch{}, fmt.Errorf("%w: bad trace %d", ErrFlatBuffer, i)
+		}
+		pos, ok := fbField(fb, table, fbTraceDim, 1)
+		if ok && pos != 0 {
+			t.dim = int(fb[pos])
+		}
+		if ok {
+			pos, ok = fbField(fb, table, fbTraceCoords, 4)
+		}
+		if ok && pos != 0 {
+			t.coords, t.n, ok = fbVector(fb, pos, 8)
+		}
+		if !ok {
+			return FlatTraceBatch{}, fmt.Errorf("%w: bad trace %d", ErrFlatBuffer, i)
+		}
+		if t.dim == 0 || t.n%t.dim != 0 {
+			return FlatTraceBatch{}, fmt.Errorf("%w: trace %d has %d values of dimensionality %d", ErrFlatBuffer, i, t.n, t.dim)
+		}
+		batch.traces[i] = t
+	}
+	return batch, nil
+}
+
+// fbOffset returns the position that the offset at pos in fb points to.
+func fbOffset(fb []byte, pos int) (int, bool) {
+	if pos < 0 || pos+4 > len(fb) {
+		return 0, false
+	}
+	target := uint64(pos) + uint64(binary.LittleEndian.Uint32(fb[pos:]))
+	if target >= uint64(len(fb)) {
+		return 0, false
+	}
+	return int(target), true
+}
+
+// fbField returns the position of field id, of size bytes, of the table at
+// pos in fb, or zero if the field is absent.
+func fbField(fb []byte, pos, id, size int) (int, bool) {
+	if pos+4 > len(fb) {
+		return 0, false
+	}
+	vtable := int64(pos) - int64(int32(binary.LittleEndian.Uint32(fb[pos:])))
+	if vtable < 0 || vtable+4 > int64(len(fb)) {
+		return 0, false
+	}
+	vtableSize := int(binary.LittleEndian.Uint16(fb[vtable:]))
+	tableSize := int(binary.LittleEndian.Uint16(fb[vtable+2:]))
+	if vtableSize < 4 || vtable+int64(vtableSize) > int64(len(fb)) || pos+tableSize > len(fb) {
+		return 0, false
+	}
+	entry := int(vtable) + 4 + 2*id
+	if entry+2 > int(vtable)+vtableSize {
+		return 0, true
+	}
+	offset := int(binary.LittleEndian.Uint16(fb[entry:]))
+	if offset == 0 {
+		return 0, true
+	}
+	if offset+size > tableSize {
+		return 0, false
+	}
+	return pos + offset, true
+}
+
+// fbVector returns the position of the first element, and the number of
+// elements, of the vector of elements of size bytes referenced by the offset
+// at pos in fb.
+func fbVector(fb []byte, pos, size int) (int, int, bool) {
+	vector, ok := fbOffset(fb, pos)
+	if !ok || vector+4 > len(fb) {
+		return 0, 0, false
+	}
+	n := uint64(binary.LittleEndian.Uint32(fb[vector:]))
+	if uint64(vector)+4+n*uint64(size) > uint64(len(fb)) {
+		return 0, 0, false
+	}
+	return vector + 4, int(n), true
+}
+
+// Len returns the number of traces in b.
+func (b FlatTraceBatch) Len() int {
+	return len(b.traces)
+}
+
+// Trace returns the ith trace of b.
+func (b FlatTraceBatch) Trace(i int) FlatTrace {
+	return b.traces[i]
+}
+
+// Dim returns the dimensionality of t.
+func (t FlatTrace) Dim() int {
+	return t.dim
+}
+
+// Len returns the number of coordinates in t.
+func (t FlatTrace) Len() int {
+	return t.n / t.dim
+}
+
+// Value returns the jth value of the ith coordinate of t.
+func (t FlatTrace) Value(i, j int) float64 {
+	if i < 0 || j < 0 || j >= t.dim || i*t.dim+j >= t.n {
+		panic("polyline: FlatTrace value index out of range")
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(t.buf[t.coords+8*(i*t.dim+j):]))
+}
+
+// Coords returns a copy of the coordinates of t.
+func (t FlatTrace) Coords() [][]float64 {
+	coords := make([][]float64, t.Len())
+	for i := range coords {
+		coords[i] = make([]float64, t.dim)
+		for j := range coords[i] {
+			coords[i][j] = t.Value(i, j)
+		}
+	}
+	return coords
+}
+
+// FromFlatBuffer encodes each trace of the TraceBatch FlatBuffer fb. It
+// returns ErrFlatBuffer if fb is malformed and ErrDimensionalMismatch if a
+// trace's dimensionality is not the codec's.
+func (c Codec) FromFlatBuffer(fb []byte) ([][]byte, error) {
+	batch, err := ReadFlatBuffer(fb)
+	if err != nil {
+		return nil, err
+	}
+	polylines := make([][]byte, batch.Len())
+	for i := range polylines {
+		t := batch.Trace(i)
+		if t.Dim() != c.Dim {
+			return nil, fmt.Errorf("%w: trace %d has %d dimensions", ErrDimensionalMismatch, i, t.Dim())
+		}
+		polylines[i] = c.untransformed().EncodeCoords(nil, t.Coords())
+	}
+	return polylines, nil
+}
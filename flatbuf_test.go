@@ -0,0 +1,93 @@
+package polyline_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+// flatTrace is a TraceBatch FlatBuffer laid out as the reference builder
+// does, with the Trace vtable after its table and dim omitted, holding one
+// trace with coordinate 1.5, 2.5.
+const flatTrace = "0c000000" + "0600080004000000" + "08000000" + "04000000" +
+	"01000000" + "04000000" +
+	"e4ffffff" + "04000000" + "02000000" + "000000000000f83f" + "0000000000000440" +
+	"0800080000000400"
+
+func TestFlatBuffer(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	polylines := [][]byte{
+		[]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"),
+		nil,
+		codec.EncodeCoords(nil, [][]float64{{1.5, 2.5}}),
+	}
+	fb, err := codec.ToFlatBuffer(polylines)
+	assert.NoError(t, err)
+
+	batch, err := polyline.ReadFlatBuffer(fb)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, batch.Len())
+	trace := batch.Trace(0)
+	assert.Equal(t, 2, trace.Dim())
+	assert.Equal(t, 3, trace.Len())
+	assert.Equal(t, -120.95, trace.Value(1, 1))
+	assert.Equal(t, [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}, trace.Coords())
+	assert.Equal(t, 0, batch.Trace(1).Len())
+	assert.Panics(t, func() { trace.Value(3, 0) })
+	assert.Panics(t, func() { trace.Value(0, 2) })
+
+	got, err := codec.FromFlatBuffer(fb)
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+	for i := range polylines {
+		assert.Equal(t, string(polylines[i]), string(got[i]))
+	}
+
+	_, err = codec.WithDim(3).FromFlatBuffer(fb)
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+	_, err = codec.ToFlatBuffer([][]byte{[]byte("_")})
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+
+	fb, err = codec.ToFlatBuffer(nil)
+	assert.NoError(t, err)
+	batch, err = polyline.ReadFlatBuffer(fb)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, batch.Len())
+}
+
+func TestReadFlatBuffer(t *testing.T) {
+	t.Parallel()
+	fb, err := hex.DecodeString(flatTrace)
+	assert.NoError(t, err)
+	batch, err := polyline.ReadFlatBuffer(fb)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, batch.Len())
+	assert.Equal(t, [][]float64{{1.5, 2.5}}, batch.Trace(0).Coords())
+
+	for _, tc := range []struct {
+		name string
+		hex  string
+	}{
+		{name: "empty"},
+		{name: "root", hex: "ff000000"},
+		{name: "truncated", hex: flatTrace[:len(flatTrace)-20]},
+		{name: "vtable", hex: "0c000000" + "0600080004000000" + "ff000000" + "04000000" + "00000000"},
+		{name: "vector", hex: "0c000000" + "0600080004000000" + "08000000" + "04000000" + "ff000000"},
+		{name: "odd_values", hex: "0c000000" + "0600080004000000" + "08000000" + "04000000" +
+			"01000000" + "04000000" +
+			"e4ffffff" + "04000000" + "01000000" + "000000000000f83f" + "0000000000000440" +
+			"0800080000000400"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fb, err := hex.DecodeString(tc.hex)
+			assert.NoError(t, err)
+			_, err = polyline.ReadFlatBuffer(fb)
+			assert.ErrorIs(t, err, polyline.ErrFlatBuffer)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrFlexHeader is returned when a flexible polyline has an unsupported
+// version or an invalid header, or a FlexCodec is invalid.
+var ErrFlexHeader = errors.New("invalid flexible polyline header")
+
+// flexVersion is the version of the flexible polyline format.
+const flexVersion = 1
+
+// flexAlphabet maps 6-bit chunks to flexible polyline bytes. It is the URL
+// safe base64 alphabet.
+const flexAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// A ThirdDimension is the meaning of the third dimension of a flexible
+// polyline.
+type ThirdDimension int
+
+// Third dimensions.
+const (
+	ThirdDimAbsent ThirdDimension = iota
+	ThirdDimLevel
+	ThirdDimAltitude
+	ThirdDimElevation
+	_ // Reserved
+	_ // Reserved
+	ThirdDimCustom1
+	ThirdDimCustom2
+)
+
+// A FlexCodec is a codec for HERE flexible polylines, as returned by HERE
+// routing APIs. Flexible polylines use the same variable-length encoding of
+// deltas as Google polylines but a different alphabet, and start with a
+// header giving the precision of each dimension and the meaning of an
+// optional third dimension.
+//
+// See https://github.com/heremaps/flexible-polyline.
+type FlexCodec struct {
+	Precision         int            // Decimal places of latitudes and longitudes, 0 to 15
+	ThirdDim          ThirdDimension // Meaning of the third dimension, if any
+	ThirdDimPrecision int            // Decimal places of the third dimension, 0 to 15
+}
+
+// Dim returns the dimensionality of c, which is 3 if c has a third
+// dimension and 2 otherwise.
+func (c FlexCodec) Dim() int {
+	if c.ThirdDim == ThirdDimAbsent {
+		return 2
+	}
+	return 3
+}
+
+// Validate returns ErrFlexHeader if c cannot be represented in a header.
+func (c FlexCodec) Validate() error {
+	switch {
+	case c.Precision < 0 || c.Precision > 15:
+		return fmt.Errorf("%w: precision %d", ErrFlexHeader, c.Precision)
+	case c.ThirdDim < ThirdDimAbsent || c.ThirdDim > ThirdDimCustom2 || c.ThirdDim == 4 || c.ThirdDim == 5:
+		return fmt.Errorf("%w: third dimension %d", ErrFlexHeader, c.ThirdDim)
+	case c.ThirdDimPrecision < 0 || c.ThirdDimPrecision > 15:
+		return fmt.Errorf("%w: third dimension precision %d", ErrFlexHeader, c.ThirdDimPrecision)
+	}
+	return nil
+}
+
+// scales returns the scale of each dimension of c.
+func (c FlexCodec) scales() []float64 {
+	scales := []float64{math.Pow10(c.Precision), math.Pow10(c.Precision)}
+	if c.ThirdDim != ThirdDimAbsent {
+		scales = append(scales, math.Pow10(c.ThirdDimPrecision))
+	}
+	return scales
+}
+
+// EncodeCoords appends the flexible polyline encoding of coords, including
+// its header, to buf and returns the new buf. It returns ErrFlexHeader if c
+// is invalid and ErrDimensionalMismatch if a coordinate does not have c's
+// dimensionality.
+func (c FlexCodec) EncodeCoords(buf []byte, coords [][]float64) ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	start := len(buf)
+	buf = encodeUint(buf, flexVersion)
+	buf = encodeUint(buf, uint(c.Precision|int(c.ThirdDim)<<4|c.ThirdDimPrecision<<7))
+	scales := c.scales()
+	last := make([]int, len(scales))
+	for i, coord := range coords {
+		if len(coord) != len(scales) {
+			return nil, fmt.Errorf("%w: coordinate %d has %d values", ErrDimensionalMismatch, i, len(coord))
+		}
+		for j, x := range coord {
+			ex := round(scales[j] * x)
+			buf = encodeInt(buf, ex-last[j])
+			last[j] = ex
+		}
+	}
+	// Translate Google polyline bytes, which offset 6-bit chunks by MinByte,
+	// to the flexible polyline alphabet.
+	for i := start; i < len(buf); i++ {
+		buf[i] = flexAlphabet[buf[i]-MinByte]
+	}
+	return buf, nil
+}
+
+// DecodeFlexCoords decodes a flexible polyline. It returns its coordinates,
+// the FlexCodec described by its header, and any error.
+func DecodeFlexCoords(buf []byte) ([][]float64, FlexCodec, error) {
+	// Translate the flexible polyline alphabet to Google polyline bytes so
+	// that values can be decoded by decodeUint.
+	google := make([]byte, len(buf))
+	for i, b := range buf {
+		chunk := flexChunk(b)
+		if chunk < 0 {
+			return nil, FlexCodec{}, ErrInvalidByte
+		}
+		google[i] = byte(chunk) + MinByte
+	}
+
+	version, rest, err := decodeUint(google)
+	if err != nil {
+		return nil, FlexCodec{}, err
+	}
+	if version != flexVersion {
+		return nil, FlexCodec{}, fmt.Errorf("%w: version %d", ErrFlexHeader, version)
+	}
+	header, rest, err := decodeUint(rest)
+	if err != nil {
+		return nil, FlexCodec{}, err
+	}
+	c := FlexCodec{
+		Precision:         int(header & 15),
+		ThirdDim:          ThirdDimension(header >> 4 & 7),
+		ThirdDimPrecision: int(header >> 7 & 15),
+	}
+	if err := c.Validate(); err != nil {
+		return nil, FlexCodec{}, err
+	}
+	if header>>11 != 0 {
+		return nil, FlexCodec{}, fmt.Errorf("%w: header %d", ErrFlexHeader, header)
+	}
+
+	scales := c.scales()
+	last := make([]int, len(scales))
+	var coords [][]float64
+	for len(rest) > 0 {
+		coord := make([]float64, len(scales))
+		for j := range coord {
+			var delta int
+			if delta, rest, err = decodeInt(rest); err != nil {
+				return nil, FlexCodec{}, err
+			}
+			last[j] += delta
+			coord[j] = float64(last[j]) / scales[j]
+		}
+		coords = append(coords, coord)
+	}
+	return coords, c, nil
+}
+
+// flexChunk returns the 6-bit chunk encoded by the flexible polyline byte b,
+// or -1 if b is not in flexAlphabet.
+func flexChunk(b byte) int {
+	switch {
+	case 'A' <= b && b <= 'Z':
+		return int(b - 'A')
+	case 'a' <= b && b <= 'z':
+		return int(b-'a') + 26
+	case '0' <= b && b <= '9':
+		return int(b-'0') + 52
+	case b == '-':
+		return 62
+	case b == '_':
+		return 63
+	}
+	return -1
+}
@@ -0,0 +1,107 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vectors from https://github.com/heremaps/flexible-polyline.
+var flexTestCases = []struct {
+	name    string
+	codec   polyline.FlexCodec
+	coords  [][]float64
+	encoded string
+}{
+	{
+		name:  "2d",
+		codec: polyline.FlexCodec{Precision: 5},
+		coords: [][]float64{
+			{50.10228, 8.69821},
+			{50.10201, 8.69567},
+			{50.10063, 8.69150},
+			{50.09878, 8.68752},
+		},
+		encoded: "BFoz5xJ67i1B1B7PzIhaxL7Y",
+	},
+	{
+		name:  "3d",
+		codec: polyline.FlexCodec{Precision: 5, ThirdDim: polyline.ThirdDimAltitude},
+		coords: [][]float64{
+			{50.10228, 8.69821, 10},
+			{50.10201, 8.69567, 20},
+			{50.10063, 8.69150, 30},
+			{50.09878, 8.68752, 40},
+		},
+		encoded: "BlBoz5xJ67i1BU1B7PUzIhaUxL7YU",
+	},
+	{
+		name:    "empty",
+		codec:   polyline.FlexCodec{Precision: 7},
+		encoded: "BH",
+	},
+}
+
+func TestFlexCodecEncodeCoords(t *testing.T) {
+	t.Parallel()
+	for _, tc := range flexTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			buf, err := tc.codec.EncodeCoords(nil, tc.coords)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.encoded, string(buf))
+		})
+	}
+}
+
+func TestDecodeFlexCoords(t *testing.T) {
+	t.Parallel()
+	for _, tc := range flexTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			coords, codec, err := polyline.DecodeFlexCoords([]byte(tc.encoded))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.codec, codec)
+			assertCoordsWithin(t, tc.coords, coords, 1e-9)
+		})
+	}
+}
+
+func TestFlexCodecErrors(t *testing.T) {
+	t.Parallel()
+	codec := polyline.FlexCodec{Precision: 5}
+	assert.Equal(t, 2, codec.Dim())
+	_, err := codec.EncodeCoords(nil, [][]float64{{1, 2, 3}})
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+	for _, c := range []polyline.FlexCodec{
+		{Precision: 16},
+		{Precision: 5, ThirdDim: 4},
+		{Precision: 5, ThirdDim: polyline.ThirdDimLevel, ThirdDimPrecision: -1},
+	} {
+		_, err := c.EncodeCoords(nil, nil)
+		assert.ErrorIs(t, err, polyline.ErrFlexHeader)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		encoded string
+		err     error
+	}{
+		{name: "version", encoded: "CF", err: polyline.ErrFlexHeader},
+		{name: "unterminated", encoded: "BF_", err: polyline.ErrUnterminatedSequence},
+		{name: "reserved_third_dim", encoded: "BgC", err: polyline.ErrFlexHeader},
+		{name: "invalid_byte", encoded: "BF?", err: polyline.ErrInvalidByte},
+		{name: "no_header", encoded: "B", err: polyline.ErrEmpty},
+		{name: "partial", encoded: "BFoz5xJ", err: polyline.ErrEmpty},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := polyline.DecodeFlexCoords([]byte(tc.encoded))
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
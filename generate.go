@@ -0,0 +1,69 @@
+package polyline
+
+import (
+	"math"
+	"math/rand"
+)
+
+// GenOptions configures Generate. Zero fields take default values.
+type GenOptions struct {
+	Points     int     // Number of points, default 100
+	StepMeters float64 // Mean distance between consecutive points, default 10
+	TurnDeg    float64 // Standard deviation of the change in heading per step, default 15
+	MinLat     float64 // Southern edge of the region, default -85
+	MinLng     float64 // Western edge of the region, default -180
+	MaxLat     float64 // Northern edge of the region, default 85
+	MaxLng     float64 // Eastern edge of the region, default 180
+}
+
+// withDefaults returns o with zero fields replaced by their defaults.
+func (o GenOptions) withDefaults() GenOptions {
+	if o.Points == 0 {
+		o.Points = 100
+	}
+	if o.StepMeters == 0 {
+		o.StepMeters = 10
+	}
+	if o.TurnDeg == 0 {
+		o.TurnDeg = 15
+	}
+	if o.MinLat == 0 && o.MinLng == 0 && o.MaxLat == 0 && o.MaxLng == 0 {
+		o.MinLat, o.MinLng, o.MaxLat, o.MaxLng = -85, -180, 85, 180
+	}
+	return o
+}
+
+// Generate returns a random walk resembling a GPS track, for use in property
+// based tests and load tests. The walk starts at a random point in the region
+// and moves with a smoothly varying heading, turning back when it reaches the
+// edge of the region. The output is fully determined by the state of r.
+func Generate(r *rand.Rand, opts GenOptions) [][]float64 {
+	opts = opts.withDefaults()
+	if opts.Points < 0 {
+		return nil
+	}
+	coords := make([][]float64, opts.Points)
+
+	lat := opts.MinLat + r.Float64()*(opts.MaxLat-opts.MinLat)
+	lng := opts.MinLng + r.Float64()*(opts.MaxLng-opts.MinLng)
+	heading := 2 * math.Pi * r.Float64()
+	for i := range coords {
+		coords[i] = []float64{lat, lng}
+
+		heading += r.NormFloat64() * radians(opts.TurnDeg)
+		step := opts.StepMeters * (0.5 + r.Float64()) / metersPerDegree
+		dLat := step * math.Cos(heading)
+		dLng := step * math.Sin(heading) / math.Max(math.Cos(radians(lat)), 1e-6)
+		if lat+dLat < opts.MinLat || opts.MaxLat < lat+dLat {
+			heading = math.Pi - heading
+			dLat = -dLat
+		}
+		if lng+dLng < opts.MinLng || opts.MaxLng < lng+dLng {
+			heading = -heading
+			dLng = -dLng
+		}
+		lat = math.Max(opts.MinLat, math.Min(opts.MaxLat, lat+dLat))
+		lng = math.Max(opts.MinLng, math.Min(opts.MaxLng, lng+dLng))
+	}
+	return coords
+}
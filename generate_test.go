@@ -0,0 +1,48 @@
+package polyline_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	opts := polyline.GenOptions{
+		Points:     1000,
+		StepMeters: 50,
+		MinLat:     51.4,
+		MinLng:     -0.3,
+		MaxLat:     51.6,
+		MaxLng:     0.1,
+	}
+	coords := polyline.Generate(rand.New(rand.NewSource(1)), opts)
+	assert.Len(t, coords, 1000)
+	for i, c := range coords {
+		assert.True(t, opts.MinLat <= c[0] && c[0] <= opts.MaxLat)
+		assert.True(t, opts.MinLng <= c[1] && c[1] <= opts.MaxLng)
+		if i > 0 {
+			dLat := c[0] - coords[i-1][0]
+			assert.Less(t, math.Abs(dLat), 2*50/111e3)
+		}
+	}
+	assert.Equal(t, coords, polyline.Generate(rand.New(rand.NewSource(1)), opts))
+
+	assert.Len(t, polyline.Generate(rand.New(rand.NewSource(1)), polyline.GenOptions{}), 100)
+}
+
+func TestGenerateRoundTrip(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 10; i++ {
+		coords := polyline.Generate(r, polyline.GenOptions{})
+		got, _, err := polyline.DecodeCoords(polyline.EncodeCoords(coords))
+		assert.NoError(t, err)
+		for j := range coords {
+			assert.True(t, float64ArrayWithin(coords[j], got[j], 5e-6))
+		}
+	}
+}
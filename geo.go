@@ -0,0 +1,199 @@
+package polyline
+
+import (
+	"math"
+	"sort"
+)
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6371008.8
+
+// metersPerDegree is the length of one degree of latitude on a spherical
+// Earth.
+const metersPerDegree = 2 * math.Pi * earthRadius / 360
+
+// radians converts degrees to radians.
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// degrees converts radians to degrees.
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// cloneCoord returns a copy of coord.
+func cloneCoord(coord []float64) []float64 {
+	return append([]float64(nil), coord...)
+}
+
+// haversine returns the great-circle distance between a and b in meters.
+func haversine(a, b []float64) float64 {
+	lat1, lat2 := radians(a[0]), radians(b[0])
+	sinDLat := math.Sin((lat2 - lat1) / 2)
+	sinDLng := math.Sin(radians(b[1]-a[1]) / 2)
+	h := sinDLat*sinDLat + math.Cos(lat1)*math.Cos(lat2)*sinDLng*sinDLng
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// destination returns the point reached by travelling meters from coord on
+// the initial bearing bearing, in degrees clockwise from north.
+func destination(coord []float64, bearing, meters float64) []float64 {
+	lat1, lng1 := radians(coord[0]), radians(coord[1])
+	theta := radians(bearing)
+	delta := meters / earthRadius
+	sinLat2 := math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta)
+	lat2 := math.Asin(sinLat2)
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*sinLat2)
+	return []float64{degrees(lat2), math.Remainder(degrees(lng2), 360)}
+}
+
+// interpolate returns the point a fraction t of the way from a to b. Further
+// dimensions are interpolated too. Longitudes are interpolated across the
+// antimeridian when that is shorter.
+func interpolate(a, b []float64, t float64) []float64 {
+	result := make([]float64, len(a))
+	for i := range a {
+		d := b[i] - a[i]
+		if i == 1 {
+			d = math.Remainder(d, 360)
+		}
+		result[i] = a[i] + t*d
+	}
+	if len(result) > 1 {
+		result[1] = math.Remainder(result[1], 360)
+	}
+	return result
+}
+
+// pathLength returns the great-circle length of coords in meters.
+func pathLength(coords [][]float64) float64 {
+	var length float64
+	for i := 1; i < len(coords); i++ {
+		length += haversine(coords[i-1], coords[i])
+	}
+	return length
+}
+
+// bearing returns the initial great-circle bearing from a to b in degrees
+// clockwise from north, in the range [0, 360).
+func bearing(a, b []float64) float64 {
+	lat1, lat2 := radians(a[0]), radians(b[0])
+	dLng := radians(b[1] - a[1])
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// turnAngle returns the absolute change in heading, in degrees in the range
+// [0, 180], between bearings b1 and b2.
+func turnAngle(b1, b2 float64) float64 {
+	return math.Abs(math.Remainder(b2-b1, 360))
+}
+
+// project returns coord projected to meters east and north of origin in a
+// local equirectangular projection.
+func project(origin, coord []float64) (x, y float64) {
+	dLng := math.Remainder(coord[1]-origin[1], 360)
+	return dLng * metersPerDegree * math.Cos(radians(origin[0])), (coord[0] - origin[0]) * metersPerDegree
+}
+
+// curvature returns the curvature, the reciprocal of the radius in meters of
+// the circle through a, b, and c, at b. It returns zero if the points are
+// collinear or coincident.
+func curvature(a, b, c []float64) float64 {
+	ax, ay := project(b, a)
+	cx, cy := project(b, c)
+	ab := math.Hypot(ax, ay)
+	bc := math.Hypot(cx, cy)
+	ca := math.Hypot(cx-ax, cy-ay)
+	if ab == 0 || bc == 0 || ca == 0 {
+		return 0
+	}
+	// Twice the area of the triangle is the magnitude of the cross product.
+	area2 := math.Abs(ax*cy - ay*cx)
+	return 2 * area2 / (ab * bc * ca)
+}
+
+// cumulativeDistances returns the great-circle distance in meters from the
+// start of coords to each vertex.
+func cumulativeDistances(coords [][]float64) []float64 {
+	return cumulativeDistancesWith(coords, Haversine)
+}
+
+// cumulativeDistancesWith returns the distance in meters measured with model
+// from the start of coords to each vertex. A nil model means Haversine.
+func cumulativeDistancesWith(coords [][]float64, model DistanceModel) []float64 {
+	if model == nil {
+		model = Haversine
+	}
+	result := make([]float64, len(coords))
+	for i := 1; i < len(coords); i++ {
+		result[i] = result[i-1] + model.Distance(coords[i-1], coords[i])
+	}
+	return result
+}
+
+// pointAtDistance returns the point at distance meters along coords, where
+// cum are the cumulative distances of coords, and the index of the segment
+// containing it. Distances are clamped to the length of coords. coords must
+// not be empty.
+func pointAtDistance(coords [][]float64, cum []float64, distance float64) ([]float64, int) {
+	if distance <= 0 || len(coords) == 1 {
+		return cloneCoord(coords[0]), 0
+	}
+	last := len(coords) - 1
+	if distance >= cum[last] {
+		return cloneCoord(coords[last]), last - 1
+	}
+	i := sort.SearchFloat64s(cum, distance)
+	// cum[i-1] < distance <= cum[i]
+	d := cum[i] - cum[i-1]
+	if d == 0 {
+		return cloneCoord(coords[i]), i - 1
+	}
+	return interpolate(coords[i-1], coords[i], (distance-cum[i-1])/d), i - 1
+}
+
+// projectOntoSegment returns the parameter t in [0, 1] of the point on the
+// segment from a to b closest to p, and the distance in meters from p to that
+// point, computed in a local equirectangular projection about p.
+func projectOntoSegment(p, a, b []float64) (float64, float64) {
+	ax, ay := project(p, a)
+	bx, by := project(p, b)
+	dx, dy := bx-ax, by-ay
+	var t float64
+	if d2 := dx*dx + dy*dy; d2 > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/d2))
+	}
+	return t, math.Hypot(ax+t*dx, ay+t*dy)
+}
+
+// A projection is the result of projecting a point onto a polyline.
+type projection struct {
+	coord    []float64 // Closest point on the polyline
+	segment  int       // Index of the segment containing coord
+	t        float64   // Parameter of coord along the segment
+	distance float64   // Distance from the point to coord in meters
+}
+
+// projectOntoPath returns the projection of p onto the segments of coords
+// from first up to but not including last. If several segments are equally
+// close then the first is returned. coords must have at least two points and
+// first must be less than last. Segments are compared in a local projection
+// about p; if model is not nil then the distance to the closest point is
+// then measured with it.
+func projectOntoPath(coords [][]float64, p []float64, first, last int, model DistanceModel) projection {
+	best := projection{distance: math.Inf(1)}
+	for i := first; i < last; i++ {
+		t, d := projectOntoSegment(p, coords[i], coords[i+1])
+		if d < best.distance {
+			best = projection{segment: i, t: t, distance: d}
+		}
+	}
+	best.coord = interpolate(coords[best.segment], coords[best.segment+1], best.t)
+	if model != nil {
+		best.distance = model.Distance(p, best.coord)
+	}
+	return best
+}
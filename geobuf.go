@@ -0,0 +1,165 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrGeobuf is returned by Codec.FromGeobuf when the input is not a geobuf
+// LineString, and by Codec.ToGeobuf and Codec.FromGeobuf for codecs with
+// fewer than two dimensions.
+var ErrGeobuf = errors.New("not a geobuf LineString")
+
+// Fields of the geobuf Data and Geometry messages, and the LineString
+// geometry type. See
+// https://github.com/mapbox/geobuf/blob/master/geobuf.proto.
+const (
+	geobufDataDimensions  = 2
+	geobufDataPrecision   = 3
+	geobufDataFeature     = 5
+	geobufDataGeometry    = 6
+	geobufFeatureGeometry = 1
+	geobufGeometryType    = 1
+	geobufGeometryCoords  = 3
+	geobufLineString      = 2
+)
+
+// ToGeobuf decodes buf and returns it as a geobuf Data message containing a
+// LineString geometry, as written by geobuf.encode in JavaScript. The geobuf
+// precision is taken from the codec's Scale, which must be a power of ten and,
+// if Scales is set, the same for every dimension.
+// Geobuf coordinates are WGS84 longitude first, so the codec's Transformer is
+// not applied and the first two dimensions are swapped.
+func (c Codec) ToGeobuf(buf []byte) ([]byte, error) {
+	if c.Dim < 2 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrGeobuf, c.Dim)
+	}
+	precision := math.Log10(c.scale(0))
+	if precision != math.Trunc(precision) || precision < 0 {
+		return nil, fmt.Errorf("%w: scale %g is not a power of ten", ErrGeobuf, c.scale(0))
+	}
+	for i := 1; i < len(c.Scales); i++ {
+		if c.Scales[i] != c.Scales[0] {
+			return nil, fmt.Errorf("%w: scales differ between dimensions", ErrGeobuf)
+		}
+	}
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var packed []byte
+	last := make([]int, c.Dim)
+	for _, coord := range SwapAxes(coords) {
+		for i, x := range coord {
+			ex := c.quantize(i, x)
+			packed = appendUvarint(packed, zigzag64(int64(ex-last[i])))
+			last[i] = ex
+		}
+	}
+	geometry := appendProtoVarint(nil, geobufGeometryType, geobufLineString)
+	if len(packed) > 0 {
+		geometry = appendProtoBytes(geometry, geobufGeometryCoords, packed)
+	}
+
+	// Dimensions and precision are omitted when they take the defaults of 2
+	// and 6.
+	var data []byte
+	if c.Dim != 2 {
+		data = appendProtoVarint(data, geobufDataDimensions, uint64(c.Dim))
+	}
+	if precision != 6 {
+		data = appendProtoVarint(data, geobufDataPrecision, uint64(precision))
+	}
+	return appendProtoBytes(data, geobufDataGeometry, geometry), nil
+}
+
+// FromGeobuf encodes a geobuf Data message containing a LineString geometry,
+// or a Feature whose geometry is a LineString. Coordinates are rescaled from
+// the geobuf precision to the codec's. It returns ErrGeobuf if data is
+// anything else, including a precision above 15 decimal places, and
+// ErrDimensionalMismatch if its dimensionality is not the codec's. As in ToGeobuf, the codec's Transformer is not applied.
+func (c Codec) FromGeobuf(data []byte) ([]byte, error) {
+	if c.Dim < 2 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrGeobuf, c.Dim)
+	}
+	dim, precision := uint64(2), uint64(6)
+	var geometry []byte
+	var hasGeometry bool
+	err := readProto(data, func(num, typ int, u uint64, b []byte) error {
+		switch {
+		case num == geobufDataDimensions && typ == wireVarint:
+			dim = u
+		case num == geobufDataPrecision && typ == wireVarint:
+			precision = u
+		case num == geobufDataGeometry && typ == wireBytes:
+			geometry, hasGeometry = b, true
+		case num == geobufDataFeature && typ == wireBytes:
+			return readProto(b, func(num, typ int, u uint64, b []byte) error {
+				if num == geobufFeatureGeometry && typ == wireBytes {
+					geometry, hasGeometry = b, true
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGeobuf, err)
+	}
+	if !hasGeometry {
+		return nil, fmt.Errorf("%w: no geometry", ErrGeobuf)
+	}
+	if dim != uint64(c.Dim) {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrDimensionalMismatch, dim)
+	}
+	if precision > 15 {
+		return nil, fmt.Errorf("%w: precision %d", ErrGeobuf, precision)
+	}
+
+	geometryType := uint64(0)
+	var values []int64
+	err = readProto(geometry, func(num, typ int, u uint64, b []byte) error {
+		switch {
+		case num == geobufGeometryType && typ == wireVarint:
+			geometryType = u
+		case num == geobufGeometryCoords && typ == wireBytes:
+			return readPackedVarints(b, func(u uint64) {
+				values = append(values, unzigzag64(u))
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGeobuf, err)
+	}
+	if geometryType != geobufLineString {
+		return nil, fmt.Errorf("%w: geometry type %d", ErrGeobuf, geometryType)
+	}
+	if len(values)%c.Dim != 0 {
+		return nil, fmt.Errorf("%w: %d values", ErrDimensionalMismatch, len(values))
+	}
+
+	e := math.Pow10(int(precision))
+	coords := make([][]float64, len(values)/c.Dim)
+	last := make([]int64, c.Dim)
+	for i := range coords {
+		coords[i] = make([]float64, c.Dim)
+		for j := range coords[i] {
+			last[j] += values[i*c.Dim+j]
+			coords[i][j] = float64(last[j]) / e
+		}
+	}
+	return c.untransformed().EncodeCoords(nil, SwapAxes(coords)), nil
+}
+
+// zigzag64 and unzigzag64 map signed integers to and from unsigned integers
+// as protocol buffer sint64 fields do.
+func zigzag64(i int64) uint64 {
+	return uint64(i<<1) ^ uint64(i>>63)
+}
+
+func unzigzag64(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
@@ -0,0 +1,85 @@
+package polyline_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+// geobufLine is geobuf.encode of the GeoJSON LineString
+// [[-120.2,38.5],[-120.95,40.7]], which has precision 2.
+const geobufLine = "1802320d08021a09e7bb01943c9501b803"
+
+func TestToGeobuf(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	codec100 := codec.WithScale(100)
+	data, err := codec100.ToGeobuf(codec100.EncodeCoords(nil, [][]float64{{38.5, -120.2}, {40.7, -120.95}}))
+	assert.NoError(t, err)
+	assert.Equal(t, geobufLine, hex.EncodeToString(data))
+
+	// Default dimensions and precision are omitted.
+	data, err = codec.WithScale(1e6).ToGeobuf(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "32020802", hex.EncodeToString(data))
+	data, err = codec.WithDim(3).ToGeobuf(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "10031805"+"32020802", hex.EncodeToString(data))
+
+	_, err = codec.WithScale(3).ToGeobuf(nil)
+	assert.ErrorIs(t, err, polyline.ErrGeobuf)
+	_, err = codec.WithDim(1).ToGeobuf(codec.WithDim(1).EncodeCoords(nil, [][]float64{{1}}))
+	assert.ErrorIs(t, err, polyline.ErrGeobuf)
+	_, err = codec.ToGeobuf([]byte("_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+
+	// Geobuf is WGS84 whatever the codec's CRS.
+	mercator := polyline.Codec{Dim: 2, Scale: 100, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	line := codec100.EncodeCoords(nil, [][]float64{{38.5, -120.2}, {40.7, -120.95}})
+	data, err = mercator.ToGeobuf(line)
+	assert.NoError(t, err)
+	assert.Equal(t, geobufLine, hex.EncodeToString(data))
+	buf, err := mercator.FromGeobuf(data)
+	assert.NoError(t, err)
+	assert.Equal(t, line, buf)
+}
+
+func TestFromGeobuf(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	codec3 := codec.WithDim(3)
+	data3, err := codec3.ToGeobuf(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}, {1.5, 2.5, 40}}))
+	assert.NoError(t, err)
+	for _, tc := range []struct {
+		name     string
+		codec    polyline.Codec
+		hex      string
+		expected string
+		err      error
+	}{
+		{name: "geometry", codec: codec, hex: geobufLine, expected: "_p~iF~ps|U_ulLnnqC"},
+		{name: "feature", codec: codec, hex: "18022a0f0a0d08021a09e7bb01943c9501b803", expected: "_p~iF~ps|U_ulLnnqC"},
+		{name: "3d", codec: codec3, hex: hex.EncodeToString(data3), expected: string(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}, {1.5, 2.5, 40}}))},
+		{name: "empty", codec: codec, hex: "32020802"},
+		{name: "point", codec: codec, hex: "320608001a020204", err: polyline.ErrGeobuf},
+		{name: "no_geometry", codec: codec, hex: "1802", err: polyline.ErrGeobuf},
+		{name: "truncated", codec: codec, hex: geobufLine[:len(geobufLine)-2], err: polyline.ErrGeobuf},
+		{name: "dimensions", codec: codec3, hex: geobufLine, err: polyline.ErrDimensionalMismatch},
+		{name: "odd_values", codec: codec, hex: "320508021a0102", err: polyline.ErrDimensionalMismatch},
+		{name: "precision", codec: codec, hex: "1810" + "32020802", err: polyline.ErrGeobuf},
+		{name: "huge_precision", codec: codec, hex: "18ffffffffffffffff01" + "32020802", err: polyline.ErrGeobuf},
+		{name: "one_dimension", codec: codec.WithDim(1), hex: "1001" + "32020802", err: polyline.ErrGeobuf},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := hex.DecodeString(tc.hex)
+			assert.NoError(t, err)
+			buf, err := tc.codec.FromGeobuf(data)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, string(buf))
+		})
+	}
+}
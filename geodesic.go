@@ -0,0 +1,532 @@
+package polyline
+
+import "math"
+
+// This file implements the solution of the inverse geodesic problem on the
+// WGS84 ellipsoid given by C. F. F. Karney, "Algorithms for geodesics",
+// J. Geodesy 87, 43-55 (2013), following GeographicLib with series expansions
+// to sixth order in the flattening. Unlike Vincenty's formula it converges
+// for all pairs of points, including nearly antipodal ones, and is accurate
+// to about 15 nanometers.
+
+// Geodesic constants.
+const (
+	geodN      = wgs84F / (2 - wgs84F) // Third flattening
+	geodF1     = 1 - wgs84F
+	geodE2     = wgs84F * (2 - wgs84F)
+	geodEp2    = geodE2 / (geodF1 * geodF1) // Second eccentricity squared
+	geodOrder  = 6                          // Order of the series expansions
+	geodMaxit1 = 20                         // Newton iterations
+	geodMaxit2 = geodMaxit1 + 53 + 10       // Newton and bisection iterations
+)
+
+// Geodesic tolerances.
+var (
+	geodTiny    = math.Sqrt(math.SmallestNonzeroFloat64 * (1 << 52))
+	geodTol0    = math.Nextafter(1, 2) - 1
+	geodTol1    = 200 * geodTol0
+	geodTol2    = math.Sqrt(geodTol0)
+	geodTolb    = geodTol0 * geodTol2
+	geodXthresh = 1000 * geodTol2
+	geodEtol2   = 0.1 * geodTol2 / math.Sqrt(math.Max(0.001, wgs84F)*math.Min(1, 1-wgs84F/2)/2)
+	geodA3x     = geodA3Coeffs()
+	geodC3x     = geodC3Coeffs()
+)
+
+// geodesic holds the coefficient arrays used while solving one inverse
+// problem.
+type geodesic struct {
+	c1a, c2a, c3a [geodOrder + 1]float64
+}
+
+// karney returns the geodesic distance in meters between a and b on the
+// WGS84 ellipsoid.
+func karney(a, b []float64) float64 {
+	var g geodesic
+	return g.inverse(a[0], a[1], b[0], b[1])
+}
+
+func (g *geodesic) inverse(lat1, lon1, lat2, lon2 float64) float64 {
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := math.Copysign(1, lon12)
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := radians(lon12)
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	// Make lat1 <= -|lat2| so that the geodesic heads north.
+	lat1, lat2 = angRound(lat1), angRound(lat2)
+	if math.Abs(lat1) < math.Abs(lat2) {
+		lat1, lat2 = lat2, lat1
+	}
+	latsign := math.Copysign(1, -lat1)
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1, cbet1 = norm2(geodF1*sbet1, cbet1)
+	cbet1 = math.Max(geodTiny, cbet1)
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2, cbet2 = norm2(geodF1*sbet2, cbet2)
+	cbet2 = math.Max(geodTiny, cbet2)
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+	dn1 := math.Sqrt(1 + geodEp2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + geodEp2*sbet2*sbet2)
+
+	var sig12, s12x float64
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// The geodesic runs along a meridian.
+		ssig1, csig1 := sbet1, clam12*cbet1
+		ssig2, csig2 := sbet2, cbet2
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		var m12x float64
+		s12x, m12x = g.lengths(geodN, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*geodTiny || (sig12 < geodTol0 && (s12x < 0 || m12x < 0)) {
+				s12x = 0
+			}
+			return wgs84B * s12x
+		}
+		// The meridian is not the shortest path; fall through.
+	}
+
+	if sbet1 == 0 && lon12s >= wgs84F*180 {
+		// The geodesic runs along the equator.
+		return wgs84A * lam12
+	}
+
+	sig12, salp1, calp1, dnm := g.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12)
+	if sig12 >= 0 {
+		// Short lines, solved directly.
+		return sig12 * wgs84B * dnm
+	}
+
+	// Solve for the azimuth at the first point with Newton's method,
+	// falling back to bisection within the bracket [alp1a, alp1b].
+	var ssig1, csig1, ssig2, csig2, eps float64
+	tripn, tripb := false, false
+	salp1a, calp1a := geodTiny, 1.0
+	salp1b, calp1b := geodTiny, -1.0
+	for numit := 0; numit < geodMaxit2; {
+		var v, dv float64
+		v, sig12, ssig1, csig1, ssig2, csig2, eps, dv = g.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12, numit < geodMaxit1)
+		tol := geodTol0
+		if tripn {
+			tol *= 8
+		}
+		if tripb || !(math.Abs(v) >= tol) {
+			break
+		}
+		if v > 0 && (numit > geodMaxit1 || calp1/salp1 > calp1b/salp1b) {
+			salp1b, calp1b = salp1, calp1
+		} else if v < 0 && (numit > geodMaxit1 || calp1/salp1 < calp1a/salp1a) {
+			salp1a, calp1a = salp1, calp1
+		}
+		numit++
+		if numit < geodMaxit1 && dv > 0 {
+			dalp1 := -v / dv
+			if math.Abs(dalp1) < math.Pi {
+				sdalp1, cdalp1 := math.Sincos(dalp1)
+				if nsalp1 := salp1*cdalp1 + calp1*sdalp1; nsalp1 > 0 {
+					calp1 = calp1*cdalp1 - salp1*sdalp1
+					salp1, calp1 = norm2(nsalp1, calp1)
+					tripn = math.Abs(v) <= 16*geodTol0
+					continue
+				}
+			}
+		}
+		salp1, calp1 = norm2((salp1a+salp1b)/2, (calp1a+calp1b)/2)
+		tripn = false
+		tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodTolb ||
+			math.Abs(salp1-salp1b)+(calp1-calp1b) < geodTolb
+	}
+	s12x, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+	return wgs84B * s12x
+}
+
+// lengths returns the distance and reduced length, in units of the semi-minor
+// axis, of the geodesic segment with arc length sig12 between the points on
+// the auxiliary sphere with sigma (ssig1, csig1) and (ssig2, csig2).
+func (g *geodesic) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64) (s12b, m12b float64) {
+	a1 := geodA1m1(eps)
+	geodC1(eps, &g.c1a)
+	a2 := geodA2m1(eps)
+	geodC2(eps, &g.c2a)
+	m0x := a1 - a2
+	a1++
+	a2++
+	b1 := sinCosSeries(ssig2, csig2, g.c1a[:]) - sinCosSeries(ssig1, csig1, g.c1a[:])
+	s12b = a1 * (sig12 + b1)
+	b2 := sinCosSeries(ssig2, csig2, g.c2a[:]) - sinCosSeries(ssig1, csig1, g.c2a[:])
+	j12 := m0x*sig12 + (a1*b1 - a2*b2)
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	return s12b, m12b
+}
+
+// inverseStart returns a starting azimuth at the first point for Newton's
+// method. For short lines it also solves the problem, returning a
+// non-negative arc length sig12 and the factor dnm by which to scale it.
+func (g *geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12 float64) (sig12, salp1, calp1, dnm float64) {
+	sig12 = -1
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	somg12, comg12 := slam12, clam12
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + geodEp2*sbetm2)
+		somg12, comg12 = math.Sincos(lam12 / (geodF1 * dnm))
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	switch {
+	case shortline && ssig12 < geodEtol2:
+		sig12 = math.Atan2(ssig12, csig12)
+	case csig12 >= 0 || ssig12 >= 6*geodN*math.Pi*cbet1*cbet1:
+		// Not nearly antipodal; the spherical estimate will do.
+	default:
+		// Nearly antipodal: estimate the azimuth by solving the astroid
+		// problem.
+		lam12x := math.Atan2(-slam12, -clam12)
+		k2 := sbet1 * sbet1 * geodEp2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := wgs84F * cbet1 * geodA3(eps) * math.Pi
+		betscale := lamscale * cbet1
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+		if y > -geodTol1 && x > -1-geodXthresh {
+			salp1 = math.Min(1, -x)
+			calp1 = -math.Sqrt(1 - salp1*salp1)
+		} else {
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+	if salp1 > 0 {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return sig12, salp1, calp1, dnm
+}
+
+// lambda12 returns the error v in the longitude difference reached by the
+// geodesic leaving the first point with azimuth alp1, and, if diffp is true,
+// its derivative dv with respect to alp1.
+func (g *geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool) (v, sig12, ssig1, csig1, ssig2, csig2, eps, dv float64) {
+	if sbet1 == 0 && calp1 == 0 {
+		calp1 = -geodTiny
+	}
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	somg1 := salp0 * sbet1
+	comg1 := calp1 * cbet1
+	ssig1, csig1 = norm2(sbet1, comg1)
+
+	calp2 := math.Abs(calp1)
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		d := (sbet1 - sbet2) * (sbet1 + sbet2)
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		}
+		calp2 = math.Sqrt(calp1*cbet1*calp1*cbet1+d) / cbet2
+	}
+	somg2 := salp0 * sbet2
+	comg2 := calp2 * cbet2
+	ssig2, csig2 = norm2(sbet2, comg2)
+
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+
+	k2 := calp0 * calp0 * geodEp2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	geodC3(eps, &g.c3a)
+	b312 := sinCosSeries(ssig2, csig2, g.c3a[:]) - sinCosSeries(ssig1, csig1, g.c3a[:])
+	v = eta - wgs84F*geodA3(eps)*salp0*(sig12+b312)
+
+	if diffp {
+		if calp2 == 0 {
+			dv = -2 * geodF1 * dn1 / sbet1
+		} else {
+			_, dv = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2)
+			dv *= geodF1 / (calp2 * cbet2)
+		}
+	}
+	return v, sig12, ssig1, csig1, ssig2, csig2, eps, dv
+}
+
+// astroid returns the positive root k of k^4 + 2k^3 - (x^2 + y^2 - 1)k^2 -
+// 2y^2k - y^2 = 0, which gives the starting azimuth for nearly antipodal
+// points.
+func astroid(x, y float64) float64 {
+	p, q := x*x, y*y
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+	s := p * q / 4
+	r2 := r * r
+	r3 := r * r2
+	disc := s * (s + 2*r3)
+	u := r
+	if disc >= 0 {
+		t3 := s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+		t := math.Cbrt(t3)
+		u += t
+		if t != 0 {
+			u += r2 / t
+		}
+	} else {
+		ang := math.Atan2(math.Sqrt(-disc), -(s + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(u*u + q)
+	uv := u + v
+	if u < 0 {
+		uv = q / (v - u)
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+w*w) + w)
+}
+
+// sinCosSeries returns the sum of c[l]*sin(2*l*x) for l from 1, given sin(x)
+// and cos(x), using Clenshaw summation.
+func sinCosSeries(sinx, cosx float64, c []float64) float64 {
+	k := len(c)
+	n := k - 1
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for n /= 2; n > 0; n-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	return 2 * sinx * cosx * y0
+}
+
+// polyval evaluates the polynomial with coefficients p, highest degree
+// first, at x.
+func polyval(p []float64, x float64) float64 {
+	var y float64
+	for _, c := range p {
+		y = y*x + c
+	}
+	return y
+}
+
+// geodA1m1 returns A1 - 1, the scale of the distance integral.
+func geodA1m1(eps float64) float64 {
+	t := polyval([]float64{1, 4, 64, 0}, eps*eps) / 256
+	return (t + eps) / (1 - eps)
+}
+
+// geodA2m1 returns A2 - 1, the scale of the reduced length integral.
+func geodA2m1(eps float64) float64 {
+	t := polyval([]float64{-11, -28, -192, 0}, eps*eps) / 256
+	return (t - eps) / (1 + eps)
+}
+
+// geodC1Coeffs and geodC2Coeffs hold, for each order l, the numerators of the
+// Fourier coefficients C1l and C2l as polynomials in eps^2, followed by their
+// common denominator.
+var (
+	geodC1Coeffs = [geodOrder][]float64{
+		{-1, 6, -16, 32},
+		{-9, 64, -128, 2048},
+		{9, -16, 768},
+		{3, -5, 512},
+		{-7, 1280},
+		{-7, 2048},
+	}
+	geodC2Coeffs = [geodOrder][]float64{
+		{1, 2, 16, 32},
+		{35, 64, 384, 2048},
+		{15, 80, 768},
+		{7, 35, 512},
+		{63, 1280},
+		{77, 2048},
+	}
+)
+
+// geodC1 sets c[1:] to the Fourier coefficients of the distance integral.
+func geodC1(eps float64, c *[geodOrder + 1]float64) {
+	geodFourier(eps, &geodC1Coeffs, c)
+}
+
+// geodC2 sets c[1:] to the Fourier coefficients of the reduced length
+// integral.
+func geodC2(eps float64, c *[geodOrder + 1]float64) {
+	geodFourier(eps, &geodC2Coeffs, c)
+}
+
+func geodFourier(eps float64, coeffs *[geodOrder][]float64, c *[geodOrder + 1]float64) {
+	d := eps
+	for l, p := range coeffs {
+		m := len(p) - 1
+		c[l+1] = d * polyval(p[:m], eps*eps) / p[m]
+		d *= eps
+	}
+}
+
+// geodA3Coeffs returns the coefficients, highest degree first, of A3 as a
+// polynomial in eps.
+func geodA3Coeffs() []float64 {
+	return []float64{
+		-3.0 / 128,
+		polyval([]float64{-2, -3}, geodN) / 64,
+		polyval([]float64{-1, -3, -1}, geodN) / 16,
+		polyval([]float64{3, -1, -2}, geodN) / 8,
+		polyval([]float64{1, -1}, geodN) / 2,
+		1,
+	}
+}
+
+// geodC3Coeffs returns, for each order l, the coefficients, highest degree
+// first, of C3l/eps^l as a polynomial in eps.
+func geodC3Coeffs() [geodOrder][]float64 {
+	n := geodN
+	return [geodOrder][]float64{
+		1: {
+			3.0 / 128,
+			polyval([]float64{2, 5}, n) / 128,
+			polyval([]float64{-1, 3, 3}, n) / 64,
+			polyval([]float64{-1, 0, 1}, n) / 8,
+			polyval([]float64{-1, 1}, n) / 4,
+		},
+		2: {
+			5.0 / 256,
+			polyval([]float64{1, 3}, n) / 128,
+			polyval([]float64{-3, -2, 3}, n) / 64,
+			polyval([]float64{1, -3, 2}, n) / 32,
+		},
+		3: {
+			7.0 / 512,
+			polyval([]float64{-10, 9}, n) / 384,
+			polyval([]float64{5, -9, 5}, n) / 192,
+		},
+		4: {
+			7.0 / 512,
+			polyval([]float64{-14, 7}, n) / 512,
+		},
+		5: {
+			21.0 / 2560,
+		},
+	}
+}
+
+// geodA3 returns A3, the scale of the longitude integral.
+func geodA3(eps float64) float64 {
+	return polyval(geodA3x, eps)
+}
+
+// geodC3 sets c[1:geodOrder] to the Fourier coefficients of the longitude
+// integral.
+func geodC3(eps float64, c *[geodOrder + 1]float64) {
+	mult := 1.0
+	for l := 1; l < geodOrder; l++ {
+		mult *= eps
+		c[l] = mult * polyval(geodC3x[l], eps)
+	}
+}
+
+// angDiff returns lon2 - lon1 reduced to [-180, 180] as a sum d + e, where e
+// is the rounding error of d.
+func angDiff(lon1, lon2 float64) (d, e float64) {
+	d, e = twoSum(math.Remainder(-lon1, 360), math.Remainder(lon2, 360))
+	d = math.Remainder(d, 360)
+	if d == 0 || math.Abs(d) == 180 {
+		if e == 0 {
+			d = math.Copysign(d, lon2-lon1)
+		} else {
+			d = math.Copysign(d, -e)
+		}
+	}
+	return d + e, e
+}
+
+// twoSum returns a + b and the rounding error of the sum.
+func twoSum(a, b float64) (s, e float64) {
+	s = a + b
+	up := s - b
+	vpp := s - up
+	up -= a
+	vpp -= b
+	return s, -(up + vpp)
+}
+
+// angRound rounds tiny angles to zero, so that the signs of angles that
+// should be zero are handled consistently.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	y := math.Abs(x)
+	if y < z {
+		y = z - (z - y)
+	}
+	return math.Copysign(y, x)
+}
+
+// sincosd returns the sine and cosine of x in degrees, exact for multiples
+// of 90.
+func sincosd(x float64) (s, c float64) {
+	r := math.Mod(x, 360)
+	q := int(math.Round(r / 90))
+	s, c = math.Sincos(radians(r - 90*float64(q)))
+	switch q & 3 {
+	case 1:
+		s, c = c, -s
+	case 2:
+		s, c = -s, -c
+	case 3:
+		s, c = -c, s
+	}
+	if s == 0 {
+		s = math.Copysign(s, x)
+	}
+	return s, c + 0
+}
+
+// norm2 returns (x, y) scaled to unit length.
+func norm2(x, y float64) (float64, float64) {
+	r := math.Hypot(x, y)
+	return x / r, y / r
+}
@@ -0,0 +1,66 @@
+package polyline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrGeoJSON is returned by Codec.FromGeoJSON when the input is not a GeoJSON
+// LineString, and by Codec.ToGeoJSON and Codec.FromGeoJSON for codecs with
+// fewer than two dimensions, which cannot hold GeoJSON positions.
+var ErrGeoJSON = errors.New("not a GeoJSON LineString")
+
+// geoJSONGeometry is a GeoJSON geometry, or a Feature containing one.
+type geoJSONGeometry struct {
+	Type        string           `json:"type"`
+	Coordinates [][]float64      `json:"coordinates"`
+	Geometry    *geoJSONGeometry `json:"geometry,omitempty"`
+}
+
+// ToGeoJSON decodes buf and returns it as a GeoJSON LineString geometry.
+// GeoJSON positions are longitude first, so the first two dimensions are
+// swapped. Any further dimensions, such as altitude, follow. GeoJSON
+// positions are WGS84, so the codec's Transformer is not applied.
+func (c Codec) ToGeoJSON(buf []byte) ([]byte, error) {
+	if c.Dim < 2 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrGeoJSON, c.Dim)
+	}
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	if coords == nil {
+		coords = [][]float64{}
+	}
+	return json.Marshal(geoJSONGeometry{
+		Type:        "LineString",
+		Coordinates: SwapAxes(coords),
+	})
+}
+
+// FromGeoJSON encodes a GeoJSON LineString geometry, or a Feature whose
+// geometry is a LineString. It returns ErrGeoJSON if geojson is anything
+// else, and ErrDimensionalMismatch if a position does not have the codec's
+// dimensionality. As in ToGeoJSON, the codec's Transformer is not applied.
+func (c Codec) FromGeoJSON(geojson []byte) ([]byte, error) {
+	if c.Dim < 2 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrGeoJSON, c.Dim)
+	}
+	var g geoJSONGeometry
+	if err := json.Unmarshal(geojson, &g); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGeoJSON, err)
+	}
+	if g.Type == "Feature" && g.Geometry != nil {
+		g = *g.Geometry
+	}
+	if g.Type != "LineString" {
+		return nil, fmt.Errorf("%w: type %q", ErrGeoJSON, g.Type)
+	}
+	for i, position := range g.Coordinates {
+		if len(position) != c.Dim {
+			return nil, fmt.Errorf("%w: position %d has %d values", ErrDimensionalMismatch, i, len(position))
+		}
+	}
+	return c.untransformed().EncodeCoords(nil, SwapAxes(g.Coordinates)), nil
+}
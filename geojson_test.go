@@ -0,0 +1,87 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToGeoJSON(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	geojson, err := codec.ToGeoJSON([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"LineString","coordinates":[[-120.2,38.5],[-120.95,40.7],[-126.453,43.252]]}`, string(geojson))
+
+	geojson, err = codec.ToGeoJSON(nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"LineString","coordinates":[]}`, string(geojson))
+
+	codec3 := polyline.Codec{Dim: 3, Scale: 1e5}
+	geojson, err = codec3.ToGeoJSON(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}}))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"LineString","coordinates":[[2,1,30]]}`, string(geojson))
+
+	_, err = codec.ToGeoJSON([]byte("_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	codec1 := polyline.Codec{Dim: 1, Scale: 1e5}
+	_, err = codec1.ToGeoJSON(codec1.EncodeCoords(nil, [][]float64{{1}}))
+	assert.ErrorIs(t, err, polyline.ErrGeoJSON)
+	_, err = codec1.FromGeoJSON([]byte(`{"type":"LineString","coordinates":[[1]]}`))
+	assert.ErrorIs(t, err, polyline.ErrGeoJSON)
+
+	// GeoJSON is WGS84 whatever the codec's CRS.
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	geojson, err = mercator.ToGeoJSON([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"LineString","coordinates":[[-120.2,38.5],[-120.95,40.7],[-126.453,43.252]]}`, string(geojson))
+	buf, err := mercator.FromGeoJSON(geojson)
+	assert.NoError(t, err)
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", string(buf))
+}
+
+func TestFromGeoJSON(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	for _, tc := range []struct {
+		name     string
+		geojson  string
+		expected string
+		err      error
+	}{
+		{
+			name:     "geometry",
+			geojson:  `{"type":"LineString","coordinates":[[-120.2,38.5],[-120.95,40.7],[-126.453,43.252]]}`,
+			expected: "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+		},
+		{
+			name:     "feature",
+			geojson:  `{"type":"Feature","properties":{"name":"x"},"geometry":{"type":"LineString","coordinates":[[-120.2,38.5]]}}`,
+			expected: "_p~iF~ps|U",
+		},
+		{
+			name:    "point",
+			geojson: `{"type":"Point","coordinates":[-120.2,38.5]}`,
+			err:     polyline.ErrGeoJSON,
+		},
+		{
+			name:    "invalid",
+			geojson: `{"type":`,
+			err:     polyline.ErrGeoJSON,
+		},
+		{
+			name:    "dimension",
+			geojson: `{"type":"LineString","coordinates":[[-120.2,38.5,10]]}`,
+			err:     polyline.ErrDimensionalMismatch,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			buf, err := codec.FromGeoJSON([]byte(tc.geojson))
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, string(buf))
+		})
+	}
+}
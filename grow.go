@@ -0,0 +1,51 @@
+package polyline
+
+// growSample is the number of coordinates that encoders write before
+// estimating the size of the remaining output from the bytes used so far.
+const growSample = 16
+
+// defaultBytesPerDim is the bytes per dimension assumed by Grow when no
+// estimate is given. It corresponds to deltas of up to ±16383 quanta, which
+// covers the vast majority of GPS tracks at a scale of 1e5.
+const defaultBytesPerDim = 3
+
+// grow returns buf with capacity for at least n more bytes.
+func grow(buf []byte, n int) []byte {
+	if n <= cap(buf)-len(buf) {
+		return buf
+	}
+	newBuf := make([]byte, len(buf), len(buf)+n)
+	copy(newBuf, buf)
+	return newBuf
+}
+
+// growRemaining grows buf to hold the encoding of the remaining coordinates
+// when the deltas of encoded of total coordinates have been appended to buf
+// since start. The first coordinate of a polyline is absolute and usually much
+// longer than the deltas that follow, so callers should set start after it. If
+// buf has insufficient spare capacity for the mean bytes per coordinate so far
+// then it is grown with an additional 12.5% of slack so that a slightly
+// noisier tail does not trigger a final reallocation.
+func growRemaining(buf []byte, start, encoded, total int) []byte {
+	if encoded == 0 || encoded >= total {
+		return buf
+	}
+	n := (len(buf) - start) * (total - encoded) / encoded
+	if n <= cap(buf)-len(buf) {
+		return buf
+	}
+	return grow(buf, n+n/8)
+}
+
+// Grow returns buf with enough spare capacity to append the encoding of n
+// coordinates of bytesPerCoord bytes each. If bytesPerCoord is zero or
+// negative then an estimate for typical tracks is used. Grow allows callers
+// that know their data to avoid all reallocations in subsequent calls to the
+// encoding functions, which only grow buf when its spare capacity is
+// insufficient.
+func (c Codec) Grow(buf []byte, n, bytesPerCoord int) []byte {
+	if bytesPerCoord <= 0 {
+		bytesPerCoord = defaultBytesPerDim * c.Dim
+	}
+	return grow(buf, n*bytesPerCoord)
+}
@@ -0,0 +1,55 @@
+package polyline_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrow(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	buf := codec.Grow([]byte("ab"), 10, 0)
+	assert.Equal(t, []byte("ab"), buf)
+	assert.GreaterOrEqual(t, cap(buf), 2+10*2*3)
+	grown := codec.Grow(buf, 10, 1)
+	assert.Equal(t, cap(buf), cap(grown))
+}
+
+func TestEncodeCoordsGrowth(t *testing.T) {
+	t.Parallel()
+	coords := benchmarkCoords(4096)
+	want := polyline.EncodeCoords(coords)
+	for _, n := range []int{1, 15, 16, 17, 100} {
+		assert.True(t, bytes.HasPrefix(want, polyline.EncodeCoords(coords[:n])))
+	}
+	flat := make([]float64, 0, 2*len(coords))
+	for _, c := range coords {
+		flat = append(flat, c...)
+	}
+	for _, codec := range []polyline.Codec{{Dim: 2, Scale: 1e5}, {Dim: 1, Scale: 1e5}} {
+		got, err := codec.EncodeFlatCoords([]byte("prefix"), flat)
+		assert.NoError(t, err)
+		fcs, _, err := codec.DecodeFlatCoords(nil, got[len("prefix"):])
+		assert.NoError(t, err)
+		assert.True(t, float64ArrayWithin(flat, fcs, 5e-6))
+	}
+}
+
+//nolint:paralleltest // AllocsPerRun cannot be used in parallel tests.
+func TestEncodeCoordsAllocs(t *testing.T) {
+	coords := benchmarkCoords(4096)
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = polyline.EncodeCoords(coords)
+	})
+	assert.LessOrEqual(t, allocs, 6.0)
+
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	buf := codec.Grow(nil, len(coords)+1, 2*codec.Dim)
+	allocs = testing.AllocsPerRun(10, func() {
+		_ = codec.EncodeCoords(buf, coords)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
@@ -0,0 +1,171 @@
+// Package gtfs converts between GTFS shapes.txt files and encoded polylines.
+//
+// See https://gtfs.org/schedule/reference/#shapestxt.
+package gtfs
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// ErrMissingColumn is returned by ReadShapes when a required column is
+// missing from the header.
+var ErrMissingColumn = errors.New("missing column")
+
+// A Shape is the path of a GTFS shape.
+type Shape struct {
+	ID       string
+	Polyline []byte
+	// DistTraveled holds the shape_dist_traveled of each point, NaN where
+	// it is blank, or is nil if the file has no such column.
+	DistTraveled []float64
+}
+
+// A point is a row of shapes.txt.
+type point struct {
+	coord    []float64
+	sequence int
+	dist     float64
+}
+
+// ReadShapes reads a shapes.txt file from r and returns its shapes, in order
+// of first appearance, encoded with codec, which must be two-dimensional.
+// Points are ordered by shape_pt_sequence.
+func ReadShapes(r io.Reader, codec polyline.Codec) ([]Shape, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range []string{"shape_id", "shape_pt_lat", "shape_pt_lon", "shape_pt_sequence"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+	}
+	distColumn, hasDist := columns["shape_dist_traveled"]
+
+	var ids []string
+	points := make(map[string][]point)
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			return strings.TrimSpace(record[columns[name]])
+		}
+		var p point
+		lat, err := strconv.ParseFloat(field("shape_pt_lat"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: shape_pt_lat: %w", line, err)
+		}
+		lon, err := strconv.ParseFloat(field("shape_pt_lon"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: shape_pt_lon: %w", line, err)
+		}
+		p.coord = []float64{lat, lon}
+		if p.sequence, err = strconv.Atoi(field("shape_pt_sequence")); err != nil {
+			return nil, fmt.Errorf("line %d: shape_pt_sequence: %w", line, err)
+		}
+		p.dist = math.NaN()
+		if hasDist {
+			if s := strings.TrimSpace(record[distColumn]); s != "" {
+				if p.dist, err = strconv.ParseFloat(s, 64); err != nil {
+					return nil, fmt.Errorf("line %d: shape_dist_traveled: %w", line, err)
+				}
+			}
+		}
+		id := field("shape_id")
+		if _, ok := points[id]; !ok {
+			ids = append(ids, id)
+		}
+		points[id] = append(points[id], p)
+	}
+
+	shapes := make([]Shape, len(ids))
+	for i, id := range ids {
+		ps := points[id]
+		sort.SliceStable(ps, func(i, j int) bool {
+			return ps[i].sequence < ps[j].sequence
+		})
+		coords := make([][]float64, len(ps))
+		for j, p := range ps {
+			coords[j] = p.coord
+		}
+		shapes[i] = Shape{
+			ID:       id,
+			Polyline: codec.EncodeCoords(nil, coords),
+		}
+		if hasDist {
+			shapes[i].DistTraveled = make([]float64, len(ps))
+			for j, p := range ps {
+				shapes[i].DistTraveled[j] = p.dist
+			}
+		}
+	}
+	return shapes, nil
+}
+
+// WriteShapes writes shapes, decoded with codec, to w as a shapes.txt file.
+// Point sequences start at one. The shape_dist_traveled column is written if
+// any shape has DistTraveled, and is blank for shapes without it and for NaN
+// distances.
+func WriteShapes(w io.Writer, shapes []Shape, codec polyline.Codec) error {
+	var hasDist bool
+	for _, shape := range shapes {
+		hasDist = hasDist || shape.DistTraveled != nil
+	}
+	cw := csv.NewWriter(w)
+	header := []string{"shape_id", "shape_pt_lat", "shape_pt_lon", "shape_pt_sequence"}
+	if hasDist {
+		header = append(header, "shape_dist_traveled")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, shape := range shapes {
+		coords, _, err := codec.DecodeCoords(shape.Polyline)
+		if err != nil {
+			return fmt.Errorf("shape %s: %w", shape.ID, err)
+		}
+		if shape.DistTraveled != nil && len(shape.DistTraveled) != len(coords) {
+			return fmt.Errorf("shape %s: %w: %d distances for %d points", shape.ID, polyline.ErrDimensionalMismatch, len(shape.DistTraveled), len(coords))
+		}
+		for i, coord := range coords {
+			record := []string{
+				shape.ID,
+				strconv.FormatFloat(coord[0], 'f', -1, 64),
+				strconv.FormatFloat(coord[1], 'f', -1, 64),
+				strconv.Itoa(i + 1),
+			}
+			if hasDist {
+				var dist string
+				if shape.DistTraveled != nil && !math.IsNaN(shape.DistTraveled[i]) {
+					dist = strconv.FormatFloat(shape.DistTraveled[i], 'f', -1, 64)
+				}
+				record = append(record, dist)
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
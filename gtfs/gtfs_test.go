@@ -0,0 +1,95 @@
+package gtfs_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/gtfs"
+	"github.com/stretchr/testify/assert"
+)
+
+const shapesTxt = "\ufeffshape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence,shape_dist_traveled\n" +
+	"A,38.5,-120.2,1,0\n" +
+	"B,1,2,5,\n" +
+	"A,43.252,-126.453,3,800.5\n" +
+	"A,40.7,-120.95,2,250\n" +
+	"B,1.5,2.5,7,\n"
+
+func TestReadShapes(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	shapes, err := gtfs.ReadShapes(strings.NewReader(shapesTxt), codec)
+	assert.NoError(t, err)
+	// Blank distances are NaN, which is not equal to itself.
+	assert.Len(t, shapes, 2)
+	assert.True(t, math.IsNaN(shapes[1].DistTraveled[0]))
+	assert.True(t, math.IsNaN(shapes[1].DistTraveled[1]))
+	shapes[1].DistTraveled = nil
+	assert.Equal(t, []gtfs.Shape{
+		{
+			ID:           "A",
+			Polyline:     []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"),
+			DistTraveled: []float64{0, 250, 800.5},
+		},
+		{
+			ID:       "B",
+			Polyline: codec.EncodeCoords(nil, [][]float64{{1, 2}, {1.5, 2.5}}),
+		},
+	}, shapes)
+}
+
+func TestWriteShapes(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	var sb strings.Builder
+	assert.NoError(t, gtfs.WriteShapes(&sb, []gtfs.Shape{
+		{ID: "A", Polyline: []byte("_p~iF~ps|U_ulLnnqC")},
+	}, codec))
+	assert.Equal(t, "shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence\nA,38.5,-120.2,1\nA,40.7,-120.95,2\n", sb.String())
+
+	// Blank distances stay blank, and a shape without distances is blank.
+	shapes, err := gtfs.ReadShapes(strings.NewReader(shapesTxt), codec)
+	assert.NoError(t, err)
+	shapes = append(shapes, gtfs.Shape{ID: "C", Polyline: []byte("??")})
+	sb.Reset()
+	assert.NoError(t, gtfs.WriteShapes(&sb, shapes, codec))
+	assert.Equal(t, "shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence,shape_dist_traveled\n"+
+		"A,38.5,-120.2,1,0\n"+
+		"A,40.7,-120.95,2,250\n"+
+		"A,43.252,-126.453,3,800.5\n"+
+		"B,1,2,1,\n"+
+		"B,1.5,2.5,2,\n"+
+		"C,0,0,1,\n", sb.String())
+
+	err = gtfs.WriteShapes(&sb, []gtfs.Shape{{ID: "A", Polyline: []byte("??"), DistTraveled: []float64{1, 2}}}, codec)
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+	err = gtfs.WriteShapes(&sb, []gtfs.Shape{{ID: "A", Polyline: []byte("_")}}, codec)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
+
+func TestReadShapesErrors(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		s    string
+		err  error
+	}{
+		{name: "missing_column", s: "shape_id,shape_pt_lat,shape_pt_lon\n", err: gtfs.ErrMissingColumn},
+		{name: "invalid_lat", s: "shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence\nA,x,1,1\n"},
+		{name: "invalid_sequence", s: "shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence\nA,1,1,first\n"},
+		{name: "invalid_dist", s: "shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence,shape_dist_traveled\nA,1,1,1,far\n"},
+		{name: "empty"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := gtfs.ReadShapes(strings.NewReader(tc.s), polyline.DefaultCodec())
+			assert.Error(t, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+//go:build go1.23
+
+package polyline
+
+import "iter"
+
+// Coords returns an iterator over the coordinates decoded from buf. Decoding
+// is lazy, so callers that stop early do not pay for the rest of buf. If
+// decoding fails, the iterator yields a nil coordinate with the error and
+// stops.
+func (c Codec) Coords(buf []byte) iter.Seq2[[]float64, error] {
+	return func(yield func([]float64, error) bool) {
+		last := make([]int, c.Dim)
+		for len(buf) > 0 {
+			coord := make([]float64, c.Dim)
+			for j := range coord {
+				k, rest, err := decodeInt(buf)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				buf = rest
+				last[j] += k
+				coord[j] = float64(last[j]) / c.scale(j)
+			}
+			if c.Transformer != nil {
+				c.fromWGS84Flat(coord)
+			}
+			if !yield(coord, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Segments returns an iterator over the segments of coords, in order, for
+// algorithms that work segment by segment. A path with fewer than two
+// coordinates has no segments. The Start and End of each segment refer to
+// coords.
+func Segments(coords [][]float64) iter.Seq[Segment] {
+	return func(yield func(Segment) bool) {
+		var along float64
+		for i := 0; i+1 < len(coords); i++ {
+			s := newSegment(coords, i, along)
+			if !yield(s) {
+				return
+			}
+			along += s.Length
+		}
+	}
+}
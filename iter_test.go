@@ -0,0 +1,97 @@
+//go:build go1.23
+
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecCoords(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	for _, tc := range []struct {
+		name     string
+		s        string
+		expected [][]float64
+		err      error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "coords",
+			s:        "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			expected: [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}},
+		},
+		{
+			name:     "error",
+			s:        "_p~iF~ps|U_ulL",
+			expected: [][]float64{{38.5, -120.2}},
+			err:      polyline.ErrEmpty,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var got [][]float64
+			var err error
+			for coord, e := range codec.Coords([]byte(tc.s)) {
+				if e != nil {
+					err = e
+					break
+				}
+				got = append(got, coord)
+			}
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestCodecCoordsBreak(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	buf := codec.EncodeCoords(nil, benchmarkCoords(1000))
+	var n int
+	for coord, err := range codec.Coords(buf) {
+		assert.NoError(t, err)
+		assert.Len(t, coord, 2)
+		n++
+		if n == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, n)
+}
+
+func TestSegments(t *testing.T) {
+	t.Parallel()
+	// North 1112m, then east 1112m along the equator.
+	coords := [][]float64{{0.01, 0}, {0, 0}, {0, 0.01}}
+	var segments []polyline.Segment
+	for s := range polyline.Segments(coords) {
+		segments = append(segments, s)
+	}
+	assert.Len(t, segments, 2)
+	for i, s := range segments {
+		assert.Equal(t, i, s.Index)
+		assert.Equal(t, coords[i], s.Start)
+		assert.Equal(t, coords[i+1], s.End)
+		assert.InDelta(t, 1111.95, s.Length, 0.01)
+	}
+	assert.Equal(t, 0.0, segments[0].Along)
+	assert.InDelta(t, 1111.95, segments[1].Along, 0.01)
+	assert.InDelta(t, 180, segments[0].Bearing, 1e-9)
+	assert.InDelta(t, 90, segments[1].Bearing, 1e-9)
+
+	for s := range polyline.Segments(coords) {
+		assert.Equal(t, 0, s.Index)
+		break
+	}
+	for range polyline.Segments(coords[:1]) {
+		t.Fatal("single coordinate has a segment")
+	}
+}
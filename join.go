@@ -0,0 +1,78 @@
+package polyline
+
+import "fmt"
+
+// Join joins polylines into a single polyline, for example to assemble a
+// route from its legs. Only the first coordinate of each polyline is
+// re-encoded, as a delta from the last coordinate of the polylines before
+// it; the rest of its bytes are copied unchanged. Every coordinate is kept;
+// use JoinDedup to drop those repeated where consecutive legs meet. Empty
+// polylines are skipped. It returns an error identifying the polyline if one
+// does not decode.
+func (c Codec) Join(polylines ...[]byte) ([]byte, error) {
+	return c.join(false, polylines)
+}
+
+// JoinDedup joins polylines as Join does, but drops the first coordinate of
+// a polyline if it repeats the last coordinate before it, as where
+// consecutive legs meet.
+func (c Codec) JoinDedup(polylines ...[]byte) ([]byte, error) {
+	return c.join(true, polylines)
+}
+
+// join is the implementation of Join and JoinDedup.
+func (c Codec) join(dedup bool, polylines [][]byte) ([]byte, error) {
+	var n int
+	for _, polyline := range polylines {
+		n += len(polyline)
+	}
+	result := make([]byte, 0, n)
+	var last []int
+	for i, polyline := range polylines {
+		if len(polyline) == 0 {
+			continue
+		}
+		first := make([]int, c.Dim)
+		buf := polyline
+		for j := range first {
+			k, rest, err := decodeInt(buf)
+			if err != nil {
+				return nil, fmt.Errorf("polyline %d: %w", i, err)
+			}
+			first[j], buf = k, rest
+		}
+		switch {
+		case last == nil:
+			result = append(result, polyline[:len(polyline)-len(buf)]...)
+		case !dedup || !equalInts(first, last):
+			result = appendDeltas(result, first, last)
+		}
+		result = append(result, buf...)
+
+		// Find the last coordinate of the polyline, validating the rest of
+		// it.
+		last = first
+		for len(buf) > 0 {
+			for j := range last {
+				k, rest, err := decodeInt(buf)
+				if err != nil {
+					return nil, fmt.Errorf("polyline %d: %w", i, err)
+				}
+				last[j] += k
+				buf = rest
+			}
+		}
+	}
+	return result, nil
+}
+
+// Join joins polylines into a single polyline using the default codec.
+func Join(polylines ...[]byte) ([]byte, error) {
+	return defaultCodec.Join(polylines...)
+}
+
+// JoinDedup joins polylines into a single polyline, dropping repeated
+// coordinates where they meet, using the default codec.
+func JoinDedup(polylines ...[]byte) ([]byte, error) {
+	return defaultCodec.JoinDedup(polylines...)
+}
@@ -0,0 +1,76 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}, {44, -127}}
+	for _, tc := range []struct {
+		name      string
+		polylines [][][]float64
+		expected  [][]float64 // With repeated coordinates dropped
+	}{
+		{
+			name:      "disjoint",
+			polylines: [][][]float64{coords[:2], coords[2:]},
+			expected:  coords,
+		},
+		{
+			name:      "shared",
+			polylines: [][][]float64{coords[:2], coords[1:3], coords[2:]},
+			expected:  coords,
+		},
+		{
+			name:      "single",
+			polylines: [][][]float64{coords[1:2], coords[1:2], coords[2:3]},
+			expected:  coords[1:3],
+		},
+		{
+			name:      "empty",
+			polylines: [][][]float64{nil, coords[:2], nil, coords[2:]},
+			expected:  coords,
+		},
+		{
+			name: "none",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			polylines := make([][]byte, len(tc.polylines))
+			for i, coords := range tc.polylines {
+				polylines[i] = polyline.EncodeCoords(coords)
+			}
+			var all [][]float64
+			for _, coords := range tc.polylines {
+				all = append(all, coords...)
+			}
+			joined, err := polyline.Join(polylines...)
+			assert.NoError(t, err)
+			assert.Equal(t, string(polyline.EncodeCoords(all)), string(joined))
+			joined, err = polyline.JoinDedup(polylines...)
+			assert.NoError(t, err)
+			assert.Equal(t, string(polyline.EncodeCoords(tc.expected)), string(joined))
+		})
+	}
+
+	_, err := polyline.Join([]byte("_p~iF~ps|U"), []byte("_p~iF~ps|U_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	assert.EqualError(t, err, "polyline 1: unterminated sequence")
+	_, err = polyline.Join([]byte("_p~iF"))
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+
+	joined, err := polyline.DefaultCodec().WithDim(3).Join(
+		polyline.DefaultCodec().WithDim(3).EncodeCoords(nil, [][]float64{{1, 2, 3}}),
+		polyline.DefaultCodec().WithDim(3).EncodeCoords(nil, [][]float64{{4, 5, 6}, {7, 8, 9}}),
+	)
+	assert.NoError(t, err)
+	decoded, _, err := polyline.DefaultCodec().WithDim(3).DecodeCoords(joined)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, decoded)
+}
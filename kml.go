@@ -0,0 +1,97 @@
+package polyline
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrKML is returned by Codec.FromKML when the input is not KML containing
+// LineStrings.
+var ErrKML = errors.New("not KML with LineStrings")
+
+// ToKML decodes buf and returns it as a KML LineString element. KML
+// coordinates are longitude first, so the first two dimensions are swapped,
+// and a third dimension is written as altitude. Codecs with more than three
+// dimensions are not supported.
+func (c Codec) ToKML(buf []byte) ([]byte, error) {
+	if c.Dim < 2 || c.Dim > 3 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrKML, c.Dim)
+	}
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	kml := []byte("<LineString><coordinates>")
+	for i, coord := range SwapAxes(coords) {
+		if i > 0 {
+			kml = append(kml, ' ')
+		}
+		for j, v := range coord {
+			if j > 0 {
+				kml = append(kml, ',')
+			}
+			kml = strconv.AppendFloat(kml, v, 'f', -1, 64)
+		}
+	}
+	return append(kml, "</coordinates></LineString>"...), nil
+}
+
+// FromKML encodes the coordinates of every LineString element in kml, in
+// document order. kml may be a complete document, such as a Google My Maps
+// export, or a single LineString. Altitudes are dropped by two-dimensional
+// codecs and default to zero for three-dimensional ones. It returns ErrKML if
+// kml is malformed or contains no LineStrings.
+func (c Codec) FromKML(kml []byte) ([][]byte, error) {
+	if c.Dim < 2 || c.Dim > 3 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrKML, c.Dim)
+	}
+	var lineString struct {
+		Coordinates string `xml:"coordinates"`
+	}
+	var bufs [][]byte
+	d := xml.NewDecoder(bytes.NewReader(kml))
+	for {
+		token, err := d.Token()
+		if err == io.EOF && bufs != nil {
+			return bufs, nil
+		}
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: no LineString", ErrKML)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrKML, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "LineString" {
+			continue
+		}
+		if err := d.DecodeElement(&lineString, &start); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrKML, err)
+		}
+		var coords [][]float64
+		for i, tuple := range strings.Fields(lineString.Coordinates) {
+			fields := strings.Split(tuple, ",")
+			if len(fields) < 2 || len(fields) > 3 {
+				return nil, fmt.Errorf("%w: LineString %d: coordinate %d has %d values", ErrKML, len(bufs), i, len(fields))
+			}
+			coord := make([]float64, c.Dim)
+			for j, field := range fields {
+				v, err := strconv.ParseFloat(field, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: LineString %d: coordinate %d: %v", ErrKML, len(bufs), i, err)
+				}
+				if j < c.Dim {
+					coord[j] = v
+				}
+			}
+			coord[0], coord[1] = coord[1], coord[0]
+			coords = append(coords, coord)
+		}
+		bufs = append(bufs, c.untransformed().EncodeCoords(nil, coords))
+	}
+}
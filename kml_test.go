@@ -0,0 +1,125 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToKML(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	kml, err := codec.ToKML([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+	assert.NoError(t, err)
+	assert.Equal(t, "<LineString><coordinates>-120.2,38.5 -120.95,40.7 -126.453,43.252</coordinates></LineString>", string(kml))
+
+	codec3 := polyline.Codec{Dim: 3, Scale: 1e5}
+	kml, err = codec3.ToKML(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "<LineString><coordinates>2,1,30</coordinates></LineString>", string(kml))
+
+	_, err = codec.ToKML([]byte("_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	_, err = polyline.Codec{Dim: 4, Scale: 1e5}.ToKML(nil)
+	assert.ErrorIs(t, err, polyline.ErrKML)
+}
+
+const myMapsKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Trip</name>
+    <Placemark>
+      <name>Stop</name>
+      <Point><coordinates>-120.2,38.5,0</coordinates></Point>
+    </Placemark>
+    <Placemark>
+      <name>Day 1</name>
+      <LineString>
+        <tessellate>1</tessellate>
+        <coordinates>
+          -120.2,38.5,0
+          -120.95,40.7,0
+          -126.453,43.252,0
+        </coordinates>
+      </LineString>
+    </Placemark>
+    <Placemark>
+      <name>Day 2</name>
+      <MultiGeometry>
+        <LineString><coordinates>2,1 2.5,1.5</coordinates></LineString>
+      </MultiGeometry>
+    </Placemark>
+  </Document>
+</kml>`
+
+func TestFromKML(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	codec3 := polyline.Codec{Dim: 3, Scale: 1e5}
+	for _, tc := range []struct {
+		name     string
+		codec    polyline.Codec
+		kml      string
+		expected []string
+		err      error
+	}{
+		{
+			name:  "document",
+			codec: codec,
+			kml:   myMapsKML,
+			expected: []string{
+				"_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+				string(codec.EncodeCoords(nil, [][]float64{{1, 2}, {1.5, 2.5}})),
+			},
+		},
+		{
+			name:     "altitude",
+			codec:    codec3,
+			kml:      "<LineString><coordinates>2,1,30 2,1</coordinates></LineString>",
+			expected: []string{string(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}, {1, 2, 0}}))},
+		},
+		{
+			name:  "no_linestring",
+			codec: codec,
+			kml:   "<Point><coordinates>2,1</coordinates></Point>",
+			err:   polyline.ErrKML,
+		},
+		{
+			name:  "malformed",
+			codec: codec,
+			kml:   "<LineString><coordinates>2,1",
+			err:   polyline.ErrKML,
+		},
+		{
+			name:  "coordinate",
+			codec: codec,
+			kml:   "<LineString><coordinates>2</coordinates></LineString>",
+			err:   polyline.ErrKML,
+		},
+		{
+			name:  "number",
+			codec: codec,
+			kml:   "<LineString><coordinates>2,x</coordinates></LineString>",
+			err:   polyline.ErrKML,
+		},
+		{
+			name:  "dimensions",
+			codec: polyline.Codec{Dim: 4, Scale: 1e5},
+			kml:   myMapsKML,
+			err:   polyline.ErrKML,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			bufs, err := tc.codec.FromKML([]byte(tc.kml))
+			assert.ErrorIs(t, err, tc.err)
+			var got []string
+			for _, buf := range bufs {
+				got = append(got, string(buf))
+			}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
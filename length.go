@@ -0,0 +1,97 @@
+package polyline
+
+import "math"
+
+// walk decodes buf one coordinate at a time, without storing the
+// coordinates, and calls f with the index of each coordinate, the coordinate,
+// and the previous coordinate, which is nil for the first. f must not retain
+// prev or coord, which are overwritten by later coordinates.
+func (c Codec) walk(buf []byte, f func(i int, prev, coord []float64)) error {
+	last := make([]int, c.Dim)
+	prev, coord := make([]float64, c.Dim), make([]float64, c.Dim)
+	for i := 0; len(buf) > 0; i++ {
+		for j := range coord {
+			k, rest, err := decodeInt(buf)
+			if err != nil {
+				return err
+			}
+			buf = rest
+			last[j] += k
+			coord[j] = float64(last[j]) / c.scale(j)
+		}
+		if i == 0 {
+			f(i, nil, coord)
+		} else {
+			f(i, prev, coord)
+		}
+		prev, coord = coord, prev
+	}
+	return nil
+}
+
+// Length returns the great-circle length in meters of the polyline buf,
+// computed while decoding without storing the coordinates.
+func (c Codec) Length(buf []byte) (float64, error) {
+	return c.LengthWith(buf, Haversine)
+}
+
+// LengthWith returns the length in meters of the polyline buf as Length
+// does, measured with model, for example Vincenty or Karney for
+// surveying-grade lengths on the WGS84 ellipsoid where the error of
+// Haversine, up to about 0.5%, matters. A nil model means Haversine.
+func (c Codec) LengthWith(buf []byte, model DistanceModel) (float64, error) {
+	if model == nil {
+		model = Haversine
+	}
+	var meters float64
+	err := c.walk(buf, func(i int, prev, coord []float64) {
+		if prev != nil {
+			meters += model.Distance(prev, coord)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return meters, nil
+}
+
+// EncodedLength returns the great-circle length in meters of the polyline
+// buf using the default codec.
+func EncodedLength(buf []byte) (float64, error) {
+	return defaultCodec.Length(buf)
+}
+
+// Bounds returns the bounding box of the polyline buf, computed in a single
+// pass while decoding without storing the coordinates, for example to fit a
+// map viewport. The codec must be at least two-dimensional. It returns
+// ErrEmpty if buf contains no coordinates.
+func (c Codec) Bounds(buf []byte) (minLat, minLng, maxLat, maxLng float64, err error) {
+	points, bbox, err := c.bounds(buf, nil)
+	if err == nil && points == 0 {
+		err = ErrEmpty
+	}
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return bbox[0], bbox[1], bbox[2], bbox[3], nil
+}
+
+// bounds walks buf, calling f, if it is not nil, for each coordinate as walk
+// does, and returns the number of coordinates and their bounding box as min
+// latitude, min longitude, max latitude, and max longitude, which is nil if
+// there are none. The codec must be at least two-dimensional.
+func (c Codec) bounds(buf []byte, f func(i int, prev, coord []float64)) (points int, bbox []float64, err error) {
+	err = c.walk(buf, func(i int, prev, coord []float64) {
+		points++
+		if i == 0 {
+			bbox = []float64{coord[0], coord[1], coord[0], coord[1]}
+		} else {
+			bbox[0], bbox[2] = math.Min(bbox[0], coord[0]), math.Max(bbox[2], coord[0])
+			bbox[1], bbox[3] = math.Min(bbox[1], coord[1]), math.Max(bbox[3], coord[1])
+		}
+		if f != nil {
+			f(i, prev, coord)
+		}
+	})
+	return points, bbox, err
+}
@@ -0,0 +1,98 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecLength(t *testing.T) {
+	t.Parallel()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	coords, _, err := polyline.DecodeCoords(buf)
+	assert.NoError(t, err)
+	meters, err := polyline.EncodedLength(buf)
+	assert.NoError(t, err)
+	assert.InDelta(t, polyline.Length(coords, polyline.Haversine), meters, 1e-6)
+
+	codec3 := polyline.DefaultCodec().WithDim(3)
+	meters, err = codec3.Length(codec3.EncodeCoords(nil, [][]float64{{0, 0, 100}, {0, 1, 200}}))
+	assert.NoError(t, err)
+	assert.InDelta(t, 111195.08, meters, 0.01)
+
+	for _, buf := range []string{"", "_p~iF~ps|U"} {
+		meters, err = polyline.EncodedLength([]byte(buf))
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, meters)
+	}
+	_, err = polyline.EncodedLength([]byte("_p~iF~ps|U_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
+
+func TestCodecLengthWith(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	coords, _, err := codec.DecodeCoords(buf)
+	assert.NoError(t, err)
+	for _, model := range []polyline.DistanceModel{polyline.Haversine, polyline.Equirectangular, polyline.Vincenty, polyline.Karney} {
+		meters, err := codec.LengthWith(buf, model)
+		assert.NoError(t, err)
+		assert.Equal(t, polyline.Length(coords, model), meters)
+	}
+	meters, err := codec.LengthWith(buf, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.Length(coords, nil), meters)
+
+	// The ellipsoidal length differs from the spherical one by about 0.2%
+	// along a meridian at the equator.
+	meridian := codec.EncodeCoords(nil, [][]float64{{0, 0}, {1, 0}})
+	meters, err = codec.LengthWith(meridian, polyline.Karney)
+	assert.NoError(t, err)
+	assert.InDelta(t, 110574.389, meters, 0.001)
+	meters, err = codec.Length(meridian)
+	assert.NoError(t, err)
+	assert.InDelta(t, 111195.08, meters, 0.01)
+
+	_, err = codec.LengthWith([]byte("_"), polyline.Vincenty)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
+
+func TestCodecLengthAllocs(t *testing.T) {
+	long := polyline.EncodeCoords(benchmarkCoords(1000))
+	allocs := testing.AllocsPerRun(10, func() {
+		_, _ = polyline.EncodedLength(long)
+	})
+	assert.LessOrEqual(t, allocs, 4.0)
+}
+
+func BenchmarkCodecLength(b *testing.B) {
+	buf := polyline.EncodeCoords(benchmarkCoords(1024))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = polyline.EncodedLength(buf)
+	}
+}
+
+func TestCodecBounds(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	minLat, minLng, maxLat, maxLng, err := codec.Bounds([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{38.5, -126.453, 43.252, -120.2}, []float64{minLat, minLng, maxLat, maxLng})
+
+	minLat, minLng, maxLat, maxLng, err = codec.Bounds([]byte("_p~iF~ps|U"))
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{38.5, -120.2, 38.5, -120.2}, []float64{minLat, minLng, maxLat, maxLng})
+
+	codec3 := codec.WithDim(3)
+	minLat, minLng, maxLat, maxLng, err = codec3.Bounds(codec3.EncodeCoords(nil, [][]float64{{1, 2, 300}, {-1, 3, -300}}))
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{-1, 2, 1, 3}, []float64{minLat, minLng, maxLat, maxLng})
+
+	_, _, _, _, err = codec.Bounds(nil)
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+	_, _, _, _, err = codec.Bounds([]byte("_p~iF~ps|U_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
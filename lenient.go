@@ -0,0 +1,108 @@
+package polyline
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ErrWrapped is returned by Codec.DecodeCoordsWith when the input is a
+// percent-encoded or hex-dumped polyline and DecodeOptions.Unwrap is not set.
+var ErrWrapped = errors.New("wrapped polyline")
+
+// DecodeOptions configures Codec.DecodeCoordsWith. The zero value decodes
+// the same inputs as DecodeCoords.
+type DecodeOptions struct {
+	// SkipWhitespace skips ASCII spaces, tabs, and line breaks anywhere in
+	// the input, for example in polylines wrapped at 80 columns.
+	SkipWhitespace bool
+	// StripQuotes strips a surrounding pair of double or single quotes. A
+	// double-quoted input is unescaped as a JSON string, so polylines copied
+	// from JSON logs, where backslashes are doubled, decode correctly.
+	StripQuotes bool
+	// Unwrap decodes input that is percent-encoded, as in a URL, or
+	// hex-dumped, as by some loggers and databases. Otherwise such input is
+	// rejected with ErrWrapped naming the detected wrapping.
+	Unwrap bool
+}
+
+// clean returns buf with the wrappers allowed by opts removed. Whitespace
+// and quotes are never valid polyline bytes, so removing them cannot change
+// the meaning of a valid polyline.
+func (o DecodeOptions) clean(buf []byte) []byte {
+	if o.SkipWhitespace {
+		buf = bytes.TrimSpace(buf)
+	}
+	if o.StripQuotes && len(buf) >= 2 {
+		switch first, last := buf[0], buf[len(buf)-1]; {
+		case first == '"' && last == '"':
+			var s string
+			if err := json.Unmarshal(buf, &s); err == nil {
+				buf = []byte(s)
+			} else {
+				buf = buf[1 : len(buf)-1]
+			}
+		case first == '\'' && last == '\'':
+			buf = buf[1 : len(buf)-1]
+		}
+	}
+	if o.SkipWhitespace && bytes.IndexAny(buf, " \t\r\n") >= 0 {
+		cleaned := make([]byte, 0, len(buf))
+		for _, b := range buf {
+			switch b {
+			case ' ', '\t', '\r', '\n':
+			default:
+				cleaned = append(cleaned, b)
+			}
+		}
+		buf = cleaned
+	}
+	return buf
+}
+
+// validBytes returns whether every byte of buf is a valid polyline byte.
+func validBytes(buf []byte) bool {
+	for _, b := range buf {
+		if !IsValidPolylineByte(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// unwrap detects whether buf, which is not a valid polyline, is a
+// percent-encoded or hex-dumped one. It returns the name of the wrapping and
+// the unwrapped polyline, or an empty name if no wrapping is detected.
+func unwrap(buf []byte) (string, []byte) {
+	if bytes.IndexByte(buf, '%') >= 0 {
+		if s, err := url.PathUnescape(string(buf)); err == nil && validBytes([]byte(s)) {
+			return "percent-encoded", []byte(s)
+		}
+	}
+	digits := bytes.TrimPrefix(bytes.TrimPrefix(buf, []byte("0x")), []byte("0X"))
+	if decoded := make([]byte, hex.DecodedLen(len(digits))); len(digits) > 0 {
+		if _, err := hex.Decode(decoded, digits); err == nil && validBytes(decoded) {
+			return "hex", decoded
+		}
+	}
+	return "", nil
+}
+
+// DecodeCoordsWith decodes all of buf after removing the wrappers allowed by
+// opts.
+func (c Codec) DecodeCoordsWith(buf []byte, opts DecodeOptions) ([][]float64, error) {
+	buf = opts.clean(buf)
+	if !validBytes(buf) {
+		if name, unwrapped := unwrap(buf); name != "" {
+			if !opts.Unwrap {
+				return nil, fmt.Errorf("%w: input is %s", ErrWrapped, name)
+			}
+			buf = unwrapped
+		}
+	}
+	coords, _, err := c.DecodeCoords(buf)
+	return coords, err
+}
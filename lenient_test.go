@@ -0,0 +1,131 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCoordsWith(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	lenient := polyline.DecodeOptions{SkipWhitespace: true, StripQuotes: true}
+	want := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	backslash := [][]float64{{-0.00015, 0}}
+	for _, tc := range []struct {
+		name     string
+		s        string
+		opts     polyline.DecodeOptions
+		expected [][]float64
+		err      error
+	}{
+		{
+			name:     "strict",
+			s:        "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			expected: want,
+		},
+		{
+			name: "strict_whitespace",
+			s:    " _p~iF~ps|U_ulLnnqC_mqNvxq`@\n",
+			err:  polyline.ErrInvalidByte,
+		},
+		{
+			name:     "wrapped",
+			s:        "_p~iF~ps|U\n_ulLnnqC\r\n_mqNvxq`@\n",
+			opts:     polyline.DecodeOptions{SkipWhitespace: true},
+			expected: want,
+		},
+		{
+			name: "quoted_strict",
+			s:    `"_p~iF~ps|U_ulLnnqC_mqNvxq` + "`" + `@"`,
+			opts: polyline.DecodeOptions{SkipWhitespace: true},
+			err:  polyline.ErrInvalidByte,
+		},
+		{
+			name:     "double_quoted",
+			s:        ` "_p~iF~ps|U_ulLnnqC_mqNvxq` + "`" + `@" `,
+			opts:     lenient,
+			expected: want,
+		},
+		{
+			name:     "single_quoted",
+			s:        `'_p~iF~ps|U_ulLnnqC_mqNvxq` + "`" + `@'`,
+			opts:     polyline.DecodeOptions{StripQuotes: true},
+			expected: want,
+		},
+		{
+			name:     "json_escaped",
+			s:        `"\\?"`,
+			opts:     lenient,
+			expected: backslash,
+		},
+		{
+			name:     "json_escaped_newline",
+			s:        `"\\\n?"`,
+			opts:     lenient,
+			expected: backslash,
+		},
+		{
+			name:     "json_unicode_escaped",
+			s:        `"\u005c?"`,
+			opts:     lenient,
+			expected: backslash,
+		},
+		{
+			// \x is a Go escape but not a JSON one.
+			name: "go_escaped",
+			s:    `"\x5c?"`,
+			opts: lenient,
+			err:  polyline.ErrInvalidByte,
+		},
+		{
+			name: "unbalanced",
+			s:    `"??`,
+			opts: lenient,
+			err:  polyline.ErrInvalidByte,
+		},
+		{
+			name: "percent_strict",
+			s:    "_p~iF~ps%7CU_ulLnnqC_mqNvxq%60@",
+			err:  polyline.ErrWrapped,
+		},
+		{
+			name:     "percent",
+			s:        "_p~iF~ps%7CU_ulLnnqC_mqNvxq%60@",
+			opts:     polyline.DecodeOptions{Unwrap: true},
+			expected: want,
+		},
+		{
+			name:     "hex",
+			s:        "0x5f707e69467e70737c55",
+			opts:     polyline.DecodeOptions{Unwrap: true},
+			expected: want[:1],
+		},
+		{
+			name: "hex_strict",
+			s:    "3f3f",
+			err:  polyline.ErrWrapped,
+		},
+		{
+			name:     "hex_unwrapped",
+			s:        "3F3F",
+			opts:     polyline.DecodeOptions{Unwrap: true},
+			expected: [][]float64{{0, 0}},
+		},
+		{
+			name: "hex_invalid",
+			s:    "0102",
+			opts: polyline.DecodeOptions{Unwrap: true},
+			err:  polyline.ErrInvalidByte,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := codec.DecodeCoordsWith([]byte(tc.s), tc.opts)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors returned when decoding exceeds Limits.
+var (
+	ErrTooManyCoords = errors.New("too many coordinates")
+	ErrValueTooLong  = errors.New("value too long")
+	ErrMagnitude     = errors.New("magnitude out of range")
+)
+
+// Limits bounds the work done and the values accepted when decoding untrusted
+// input. Zero fields mean no limit.
+type Limits struct {
+	MaxCoords        int     // Maximum number of coordinates
+	MaxBytesPerValue int     // Maximum number of bytes in a single encoded value
+	MaxMagnitude     float64 // Maximum absolute value of a decoded latitude or longitude
+}
+
+// DefaultLimits are the limits used by SafeDecode. They accept any WGS84
+// geometry of up to a million points encoded at a scale of up to 1e7.
+var DefaultLimits = Limits{
+	MaxCoords:        1 << 20,
+	MaxBytesPerValue: 7,
+	MaxMagnitude:     180,
+}
+
+// valueLen returns the length of the encoded value at the start of buf, or
+// len(buf) if the value is unterminated.
+func valueLen(buf []byte) int {
+	for i, b := range buf {
+		if b < ContinuationByte {
+			return i + 1
+		}
+	}
+	return len(buf)
+}
+
+// DecodeCoordsLimits decodes an array of coordinates from buf, as
+// DecodeCoords does, but returns an error as soon as the input exceeds limits.
+// It returns the coordinates, the remaining unconsumed bytes of buf, and any
+// error.
+func (c Codec) DecodeCoordsLimits(buf []byte, limits Limits) ([][]float64, []byte, error) {
+	if len(buf) == 0 {
+		return nil, buf, nil
+	}
+	b := &CoordBuffer{dim: c.Dim}
+	last := make([]int, c.Dim)
+	for offset := 0; len(buf) > 0; {
+		if limits.MaxCoords > 0 && b.Len() == limits.MaxCoords {
+			return nil, nil, fmt.Errorf("%w: more than %d", ErrTooManyCoords, limits.MaxCoords)
+		}
+		for j := 0; j < c.Dim; j++ {
+			if n := valueLen(buf); limits.MaxBytesPerValue > 0 && n > limits.MaxBytesPerValue {
+				return nil, nil, fmt.Errorf("%w: %d bytes at offset %d", ErrValueTooLong, n, offset)
+			}
+			k, rest, err := decodeInt(buf)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w at offset %d", err, offset)
+			}
+			offset += len(buf) - len(rest)
+			buf = rest
+			last[j] += k
+			x := float64(last[j]) / c.scale(j)
+			if j < 2 && limits.MaxMagnitude > 0 && math.Abs(x) > limits.MaxMagnitude {
+				return nil, nil, fmt.Errorf("%w: coordinate %d has value %g", ErrMagnitude, b.Len(), x)
+			}
+			b.flat = append(b.flat, x)
+		}
+	}
+	if c.Transformer != nil {
+		c.fromWGS84Flat(b.flat)
+	}
+	return b.Coords(), nil, nil
+}
+
+// SafeDecode decodes all of buf with DefaultLimits. It is the recommended
+// way to decode polylines from untrusted sources.
+func (c Codec) SafeDecode(buf []byte) ([][]float64, error) {
+	coords, _, err := c.DecodeCoordsLimits(buf, DefaultLimits)
+	return coords, err
+}
+
+// SafeDecode decodes all of buf using the default codec and DefaultLimits. It
+// is the recommended way to decode polylines from untrusted sources.
+func SafeDecode(buf []byte) ([][]float64, error) {
+	return defaultCodec.SafeDecode(buf)
+}
@@ -0,0 +1,80 @@
+package polyline_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeDecode(t *testing.T) {
+	t.Parallel()
+	got, err := polyline.SafeDecode([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}, got)
+
+	got, err = polyline.SafeDecode(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	// Only latitudes and longitudes are bounded by MaxMagnitude.
+	codec3 := polyline.DefaultCodec().WithDim(3)
+	got, err = codec3.SafeDecode(codec3.EncodeCoords(nil, [][]float64{{38.5, -120.2, 500}}))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{38.5, -120.2, 500}}, got)
+}
+
+func TestDecodeCoordsLimits(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	for _, tc := range []struct {
+		name   string
+		s      string
+		limits polyline.Limits
+		err    error
+	}{
+		{
+			name:   "too_many_coords",
+			s:      "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			limits: polyline.Limits{MaxCoords: 2},
+			err:    polyline.ErrTooManyCoords,
+		},
+		{
+			name:   "value_too_long",
+			s:      "_p~iF~ps|U",
+			limits: polyline.Limits{MaxBytesPerValue: 4},
+			err:    polyline.ErrValueTooLong,
+		},
+		{
+			name:   "unterminated_value_too_long",
+			s:      strings.Repeat("~", 1000),
+			limits: polyline.DefaultLimits,
+			err:    polyline.ErrValueTooLong,
+		},
+		{
+			name:   "magnitude",
+			s:      string(codec.EncodeCoords(nil, [][]float64{{0, 0}, {0, 180.00001}})),
+			limits: polyline.DefaultLimits,
+			err:    polyline.ErrMagnitude,
+		},
+		{
+			name:   "invalid_byte",
+			s:      "_p~iF~ps|U>",
+			limits: polyline.DefaultLimits,
+			err:    polyline.ErrInvalidByte,
+		},
+		{
+			name:   "unlimited",
+			s:      "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			limits: polyline.Limits{},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := codec.DecodeCoordsLimits([]byte(tc.s), tc.limits)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
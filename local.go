@@ -0,0 +1,228 @@
+package polyline
+
+import "math"
+
+// A LocalProjection is an equirectangular projection about a fixed origin,
+// normally the centroid of a track. It is a DistanceModel that avoids the
+// trigonometry of Haversine, and of Equirectangular, for every pair of
+// coordinates, making it the fastest model for the lengths of dense
+// city-scale tracks. Within about 10km of the origin its distances are
+// within 0.1% of Haversine; further away, and particularly north or south of
+// the origin, its error grows.
+type LocalProjection struct {
+	origin []float64
+	kx     float64 // Meters per degree of longitude at the origin
+}
+
+// NewLocalProjection returns a LocalProjection about the MeanPoint of
+// coords. If coords is empty then the origin is 0, 0.
+func NewLocalProjection(coords [][]float64) LocalProjection {
+	origin := MeanPoint(coords)
+	if origin == nil {
+		origin = []float64{0, 0}
+	}
+	return LocalProjectionAt(origin)
+}
+
+// LocalProjectionAt returns a LocalProjection about origin.
+func LocalProjectionAt(origin []float64) LocalProjection {
+	return LocalProjection{
+		origin: []float64{origin[0], origin[1]},
+		kx:     metersPerDegree * math.Cos(radians(origin[0])),
+	}
+}
+
+// Origin returns the origin of p.
+func (p LocalProjection) Origin() []float64 {
+	return cloneCoord(p.origin)
+}
+
+// Project returns coord projected to meters east and north of the origin of
+// p.
+func (p LocalProjection) Project(coord []float64) (x, y float64) {
+	return math.Remainder(coord[1]-p.origin[1], 360) * p.kx, (coord[0] - p.origin[0]) * metersPerDegree
+}
+
+// Distance returns the distance in meters between a and b in p.
+func (p LocalProjection) Distance(a, b []float64) float64 {
+	dx := math.Remainder(b[1]-a[1], 360) * p.kx
+	dy := (b[0] - a[0]) * metersPerDegree
+	return math.Hypot(dx, dy)
+}
+
+// projectedPoint is a Point in meters in a LocalProjection, with the
+// coordinate it was projected from.
+type projectedPoint struct {
+	x, y  float64
+	coord []float64
+}
+
+func (p projectedPoint) GetX() float64 {
+	return p.x
+}
+
+func (p projectedPoint) GetY() float64 {
+	return p.y
+}
+
+// Simplify simplifies coords as configured by opts, with distances measured
+// in p, so that opts.Tolerance and opts.RadialTolerance are in meters. The
+// returned coordinates are elements of coords.
+func (p LocalProjection) Simplify(coords [][]float64, opts SimplifyOptions) [][]float64 {
+	if len(coords) <= 2 {
+		return coords
+	}
+	points := make([]Point, len(coords))
+	for i, coord := range coords {
+		x, y := p.Project(coord)
+		points[i] = projectedPoint{x: x, y: y, coord: coord}
+	}
+	points = SimplifyWith(points, opts)
+	simplified := make([][]float64, len(points))
+	for i, point := range points {
+		simplified[i] = point.(projectedPoint).coord
+	}
+	return simplified
+}
+
+// pointsProjection returns a LocalProjection about the centroid of points,
+// taking X and Y as latitude and longitude.
+func pointsProjection(points []Point) LocalProjection {
+	coords := make([][]float64, len(points))
+	for i, p := range points {
+		coords[i] = []float64{p.GetX(), p.GetY()}
+	}
+	return NewLocalProjection(coords)
+}
+
+// metricPoint is a Point projected to meters, with the Point it was
+// projected from.
+type metricPoint struct {
+	x, y float64
+	p    Point
+}
+
+func (p metricPoint) GetX() float64 {
+	return p.x
+}
+
+func (p metricPoint) GetY() float64 {
+	return p.y
+}
+
+// simplifyMeters simplifies points as SimplifyWith does with tolerances in
+// meters. See SimplifyOptions.Meters.
+func simplifyMeters(points []Point, opts SimplifyOptions) []Point {
+	if opts.Model != nil {
+		return simplifyModel(points, opts)
+	}
+	proj := pointsProjection(points)
+	projected := make([]Point, len(points))
+	for i, p := range points {
+		x, y := proj.Project([]float64{p.GetX(), p.GetY()})
+		projected[i] = metricPoint{x: x, y: y, p: p}
+	}
+	opts.Meters = false
+	projected = SimplifyWith(projected, opts)
+	simplified := make([]Point, len(projected))
+	for i, p := range projected {
+		simplified[i] = p.(metricPoint).p
+	}
+	return simplified
+}
+
+// simplifyModel simplifies points as simplifyMeters does with the distances
+// of the radial pre-filter and DouglasPeucker measured by opts.Model.
+func simplifyModel(points []Point, opts SimplifyOptions) []Point {
+	coords := make([][]float64, len(points))
+	for i, p := range points {
+		coords[i] = []float64{p.GetX(), p.GetY()}
+	}
+	indexes := make([]int, 0, len(points))
+	for i := range coords {
+		last := len(indexes) - 1
+		if opts.RadialTolerance > 0 && 0 < i && i < len(coords)-1 &&
+			opts.Model.Distance(coords[indexes[last]], coords[i]) <= opts.RadialTolerance {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+
+	if opts.Algorithm == VisvalingamWhyatt {
+		kept := make([]Point, len(indexes))
+		for i, index := range indexes {
+			kept[i] = points[index]
+		}
+		opts.Model, opts.RadialTolerance = nil, -1
+		return simplifyMeters(kept, opts)
+	}
+
+	keep := make([]bool, len(indexes))
+	keep[0], keep[len(keep)-1] = true, true
+	var step func(first, last int)
+	step = func(first, last int) {
+		a, b := coords[indexes[first]], coords[indexes[last]]
+		maxDist, index := opts.Tolerance, -1
+		for i := first + 1; i < last; i++ {
+			p := coords[indexes[i]]
+			t, _ := projectOntoSegment(p, a, b)
+			if d := opts.Model.Distance(p, interpolate(a, b, t)); d > maxDist {
+				maxDist, index = d, i
+			}
+		}
+		if index >= 0 {
+			keep[index] = true
+			step(first, index)
+			step(index, last)
+		}
+	}
+	step(0, len(indexes)-1)
+
+	simplified := make([]Point, 0, len(indexes))
+	for i, index := range indexes {
+		if keep[i] {
+			simplified = append(simplified, points[index])
+		}
+	}
+	return simplified
+}
+
+// metricPoint3 is metricPoint for Point3s.
+type metricPoint3 struct {
+	x, y, z float64
+	p       Point3
+}
+
+func (p metricPoint3) GetX() float64 {
+	return p.x
+}
+
+func (p metricPoint3) GetY() float64 {
+	return p.y
+}
+
+func (p metricPoint3) GetZ() float64 {
+	return p.z
+}
+
+// simplifyMeters3 simplifies points as Simplify3 does with tolerances in
+// meters. See SimplifyOptions.Meters.
+func simplifyMeters3(points []Point3, opts SimplifyOptions) []Point3 {
+	xy := make([]Point, len(points))
+	for i, p := range points {
+		xy[i] = p
+	}
+	proj := pointsProjection(xy)
+	projected := make([]Point3, len(points))
+	for i, p := range points {
+		x, y := proj.Project([]float64{p.GetX(), p.GetY()})
+		projected[i] = metricPoint3{x: x, y: y, z: p.GetZ(), p: p}
+	}
+	opts.Meters = false
+	projected = Simplify3(projected, opts)
+	simplified := make([]Point3, len(projected))
+	for i, p := range projected {
+		simplified[i] = p.(metricPoint3).p
+	}
+	return simplified
+}
@@ -0,0 +1,69 @@
+package polyline_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalProjection(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{51.50, -0.13}, {51.51, -0.12}, {51.52, -0.10}, {51.50, -0.08}}
+	p := polyline.NewLocalProjection(coords)
+	assert.InDeltaSlice(t, []float64{51.5075, -0.1075}, p.Origin(), 1e-9)
+	assert.InDelta(t, polyline.Length(coords, polyline.Haversine), polyline.Length(coords, p), 1)
+	for i := 1; i < len(coords); i++ {
+		assert.InDelta(t, polyline.Haversine.Distance(coords[i-1], coords[i]), p.Distance(coords[i-1], coords[i]), 1)
+	}
+
+	x, y := p.Project(p.Origin())
+	assert.Equal(t, 0.0, x)
+	assert.Equal(t, 0.0, y)
+	x, y = p.Project([]float64{51.5075, -0.0975})
+	assert.InDelta(t, 692.09, x, 0.01)
+	assert.Equal(t, 0.0, y)
+}
+
+func TestLocalProjectionAntimeridian(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 179.99}, {0, -179.99}}
+	p := polyline.NewLocalProjection(coords)
+	assert.InDelta(t, 0, p.Origin()[0], 1e-9)
+	assert.InDelta(t, 180, math.Abs(p.Origin()[1]), 1e-9)
+	assert.InDelta(t, 2224, polyline.Length(coords, p), 1)
+
+	assert.Equal(t, []float64{0, 0}, polyline.NewLocalProjection(nil).Origin())
+	assert.Equal(t, []float64{10, 20}, polyline.LocalProjectionAt([]float64{10, 20, 30}).Origin())
+}
+
+func TestLocalProjectionSimplify(t *testing.T) {
+	t.Parallel()
+	// A straight street with a 5m kink and a 50m detour.
+	coords := [][]float64{{0, 0}, {0.00005, 0.001}, {0, 0.002}, {0.00045, 0.003}, {0, 0.004}, {0, 0.005, 7}}
+	p := polyline.NewLocalProjection(coords)
+	for _, tc := range []struct {
+		name      string
+		tolerance float64
+		expected  [][]float64
+	}{
+		{name: "fine", tolerance: 1, expected: coords},
+		{name: "medium", tolerance: 10, expected: [][]float64{coords[0], coords[2], coords[3], coords[4], coords[5]}},
+		{name: "coarse", tolerance: 100, expected: [][]float64{coords[0], coords[5]}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, p.Simplify(coords, polyline.SimplifyOptions{Tolerance: tc.tolerance, RadialTolerance: -1}))
+		})
+	}
+	assert.Equal(t, coords[:2], p.Simplify(coords[:2], polyline.SimplifyOptions{Tolerance: 100}))
+}
+
+func BenchmarkLocalProjection(b *testing.B) {
+	coords := benchmarkCoords(1024)
+	for i := 0; i < b.N; i++ {
+		polyline.Length(coords, polyline.NewLocalProjection(coords))
+	}
+}
@@ -0,0 +1,101 @@
+package polyline
+
+import (
+	"math"
+	"sort"
+)
+
+// A Level is an encoding of a polyline simplified at a tolerance in degrees.
+type Level struct {
+	Tolerance float64
+	Encoded   []byte
+}
+
+// SimplifyLevels returns encodings of coords simplified at each of
+// tolerances, in degrees, ordered from the finest to the coarsest. A
+// tolerance of zero keeps every coordinate.
+func (c Codec) SimplifyLevels(coords [][]float64, tolerances []float64) []Level {
+	levels := make([]Level, len(tolerances))
+	for i, tolerance := range tolerances {
+		levels[i] = Level{
+			Tolerance: tolerance,
+			Encoded:   c.EncodeCoords(nil, simplifyCoords(coords, tolerance)),
+		}
+	}
+	sort.SliceStable(levels, func(i, j int) bool {
+		return levels[i].Tolerance < levels[j].Tolerance
+	})
+	return levels
+}
+
+// LevelForView selects the coarsest of levels, as returned by SimplifyLevels,
+// whose tolerance is below one pixel at zoom on a 256 pixel Web Mercator
+// tile, and returns its runs of segments that overlap the viewport from min
+// to max. Each run is encoded separately and includes the coordinates just
+// outside the viewport so that lines continue to its edges. It returns
+// ErrEmpty if levels is empty.
+func (c Codec) LevelForView(levels []Level, zoom float64, min, max []float64) ([][]byte, error) {
+	if len(levels) == 0 {
+		return nil, ErrEmpty
+	}
+	c = c.untransformed()
+	pixel := 360 / (256 * math.Exp2(zoom))
+	level := levels[0]
+	for _, l := range levels[1:] {
+		if l.Tolerance <= pixel {
+			level = l
+		}
+	}
+
+	coords, _, err := c.DecodeCoords(level.Encoded)
+	if err != nil {
+		return nil, err
+	}
+	var runs [][]byte
+	first := -1
+	flush := func(last int) {
+		if first >= 0 {
+			runs = append(runs, c.EncodeCoords(nil, coords[first:last+1]))
+			first = -1
+		}
+	}
+	for i := 1; i < len(coords); i++ {
+		if segmentOverlaps(coords[i-1], coords[i], min, max) {
+			if first < 0 {
+				first = i - 1
+			}
+			continue
+		}
+		flush(i - 1)
+	}
+	flush(len(coords) - 1)
+	if len(coords) == 1 && segmentOverlaps(coords[0], coords[0], min, max) {
+		runs = append(runs, level.Encoded)
+	}
+	return runs, nil
+}
+
+// SimplifyLevels returns levels of coords using the default codec.
+func SimplifyLevels(coords [][]float64, tolerances []float64) []Level {
+	return defaultCodec.SimplifyLevels(coords, tolerances)
+}
+
+// LevelForView returns the runs of levels for a view using the default codec.
+func LevelForView(levels []Level, zoom float64, min, max []float64) ([][]byte, error) {
+	return defaultCodec.LevelForView(levels, zoom, min, max)
+}
+
+// segmentOverlaps returns whether the bounding box of the segment from a to b
+// overlaps the box from min to max in the first two dimensions.
+func segmentOverlaps(a, b, min, max []float64) bool {
+	for j := 0; j < 2; j++ {
+		lo, hi := a[j], b[j]
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if hi < min[j] || lo > max[j] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimplifyLevels(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {0.001, 1}, {0, 2}, {1, 3}, {0, 4}}
+	levels := polyline.SimplifyLevels(coords, []float64{0.1, 0, 2})
+	if assert.Len(t, levels, 3) {
+		assert.Equal(t, 0.0, levels[0].Tolerance)
+		assert.Equal(t, polyline.EncodeCoords(coords), levels[0].Encoded)
+		assert.Equal(t, polyline.EncodeCoords([][]float64{{0, 0}, {0, 2}, {1, 3}, {0, 4}}), levels[1].Encoded)
+		assert.Equal(t, polyline.EncodeCoords([][]float64{{0, 0}, {0, 4}}), levels[2].Encoded)
+	}
+}
+
+func TestLevelForView(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {0.001, 1}, {0, 2}, {1, 3}, {0, 4}}
+	levels := polyline.SimplifyLevels(coords, []float64{0, 0.1, 2})
+	for _, tc := range []struct {
+		name     string
+		zoom     float64
+		min, max []float64
+		expected [][][]float64
+	}{
+		{
+			name:     "world",
+			zoom:     0,
+			min:      []float64{-90, -180},
+			max:      []float64{90, 180},
+			expected: [][][]float64{{{0, 0}, {0, 2}, {1, 3}, {0, 4}}},
+		},
+		{
+			name:     "medium",
+			zoom:     5,
+			min:      []float64{-1, 2.5},
+			max:      []float64{2, 3.5},
+			expected: [][][]float64{{{0, 2}, {1, 3}, {0, 4}}},
+		},
+		{
+			name:     "street",
+			zoom:     15,
+			min:      []float64{0, 0.5},
+			max:      []float64{0.0005, 1.5},
+			expected: [][][]float64{{{0, 0}, {0.001, 1}, {0, 2}}},
+		},
+		{
+			name: "outside",
+			zoom: 5,
+			min:  []float64{10, 10},
+			max:  []float64{11, 11},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			runs, err := polyline.LevelForView(levels, tc.zoom, tc.min, tc.max)
+			assert.NoError(t, err)
+			var got [][][]float64
+			for _, run := range runs {
+				coords, _, err := polyline.DecodeCoords(run)
+				assert.NoError(t, err)
+				got = append(got, coords)
+			}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	u := [][]float64{{0, 0}, {0, 1}, {5, 1}, {5, 2}, {0, 2}, {0, 3}}
+	runs, err := polyline.LevelForView(polyline.SimplifyLevels(u, []float64{0}), 0, []float64{-1, -1}, []float64{1, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{
+		polyline.EncodeCoords(u[:3]),
+		polyline.EncodeCoords(u[3:]),
+	}, runs)
+
+	_, err = polyline.LevelForView(nil, 0, nil, nil)
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+}
@@ -0,0 +1,67 @@
+package polyline
+
+import "math"
+
+// A RoutePosition is the position of a fix matched to a route.
+type RoutePosition struct {
+	Coord    []float64 // Matched point on the route
+	Segment  int       // Index of the route segment containing Coord
+	Along    float64   // Distance along the route to Coord in meters
+	Distance float64   // Distance from the fix to Coord in meters
+}
+
+// MatchOptions configures MatchProgress. Zero fields take default values.
+type MatchOptions struct {
+	// MaxAdvance is the maximum distance along the route, in meters, between
+	// the positions of consecutive fixes, default 1000. It prevents fixes from
+	// matching a later pass of a loop or a distant parallel segment.
+	MaxAdvance float64
+	// Model measures distances, default Haversine.
+	Model DistanceModel
+}
+
+// MatchProgress matches each of fixes, which must be in time order, to a
+// position on route while enforcing that progress along route never
+// decreases. It is a lightweight alternative to map matching for vehicles
+// that are known to follow route. Each fix is matched to the closest point on
+// the segments between the previous position and MaxAdvance meters further
+// along; fixes that would move backwards are held at the previous position.
+// It returns nil if route has fewer than two points.
+func MatchProgress(route, fixes [][]float64, opts MatchOptions) []RoutePosition {
+	if len(route) < 2 {
+		return nil
+	}
+	if opts.MaxAdvance == 0 {
+		opts.MaxAdvance = 1000
+	}
+	if opts.Model == nil {
+		opts.Model = Haversine
+	}
+	cum := cumulativeDistancesWith(route, opts.Model)
+	positions := make([]RoutePosition, 0, len(fixes))
+	var segment int
+	var along float64
+	for _, fix := range fixes {
+		last := segment + 1
+		for last < len(route)-1 && cum[last] <= along+opts.MaxAdvance {
+			last++
+		}
+		p := projectOntoPath(route, fix, segment, last, opts.Model)
+		pAlong := cum[p.segment] + p.t*(cum[p.segment+1]-cum[p.segment])
+		if pAlong < along {
+			// Hold the position rather than moving backwards.
+			p.coord, _ = pointAtDistance(route, cum, along)
+			p.segment = segment
+			p.distance = opts.Model.Distance(fix, p.coord)
+			pAlong = along
+		}
+		segment, along = p.segment, pAlong
+		positions = append(positions, RoutePosition{
+			Coord:    p.coord,
+			Segment:  p.segment,
+			Along:    math.Min(pAlong, cum[len(cum)-1]),
+			Distance: p.distance,
+		})
+	}
+	return positions
+}
@@ -0,0 +1,48 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchProgress(t *testing.T) {
+	t.Parallel()
+	// An out-and-back route: north for 1112m, then back south on a parallel
+	// track 22m to the east.
+	route := [][]float64{{0, 0}, {0.01, 0}, {0.01, 0.0002}, {0, 0.0002}}
+	fixes := [][]float64{
+		{0.001, 0.00015}, // Closer to the return leg, but progress is at the start.
+		{0.005, 0.0001},
+		{0.004, 0},       // Moves backwards.
+		{0.0099, 0.0001}, // Near the turn.
+		{0.005, 0.0002},
+		{0.001, 0.00005}, // Closer to the outbound leg, but it is behind.
+	}
+	positions := polyline.MatchProgress(route, fixes, polyline.MatchOptions{})
+	assert.Len(t, positions, len(fixes))
+	segments := make([]int, len(positions))
+	for i, p := range positions {
+		segments[i] = p.Segment
+		if i > 0 {
+			assert.GreaterOrEqual(t, p.Along, positions[i-1].Along)
+		}
+	}
+	assert.Equal(t, []int{0, 0, 0, 0, 2, 2}, segments)
+	assert.InDelta(t, 111.2, positions[0].Along, 0.1)
+	assert.InDelta(t, 556.0, positions[1].Along, 0.1)
+	assert.Equal(t, positions[1].Along, positions[2].Along)
+	assert.InDelta(t, 111.2, positions[2].Distance, 0.1)
+	assert.InDelta(t, 1112.0+22.2+556.0, positions[4].Along, 0.1)
+	assert.InDelta(t, 1112.0+22.2+1000.8, positions[5].Along, 0.1)
+
+	doubled := polyline.MatchProgress(route, fixes, polyline.MatchOptions{Model: doubledModel{}})
+	for i, p := range doubled {
+		assert.Equal(t, positions[i].Segment, p.Segment)
+		assert.InDelta(t, 2*positions[i].Along, p.Along, 1e-6)
+		assert.InDelta(t, 2*positions[i].Distance, p.Distance, 1e-6)
+	}
+
+	assert.Nil(t, polyline.MatchProgress(route[:1], fixes, polyline.MatchOptions{}))
+}
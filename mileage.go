@@ -0,0 +1,112 @@
+package polyline
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// A Trip is an encoded polyline with the time of each of its coordinates.
+type Trip struct {
+	ID       string
+	Polyline []byte
+	Times    []time.Time
+}
+
+// MileageOptions configures Mileage. Zero fields take default values.
+type MileageOptions struct {
+	Codec     Codec         // Codec of the trips' polylines, default Dim 2 and Scale 1e5
+	Model     DistanceModel // Distance model, default Haversine
+	IdleSpeed float64       // Speed in meters per second below which a vehicle is idle, default 0.5
+}
+
+// A TripMileage is the mileage of a single trip.
+type TripMileage struct {
+	ID       string
+	Distance float64       // Distance in meters
+	Duration time.Duration // Time from the first to the last coordinate
+	IdleTime time.Duration // Time spent moving slower than the idle speed
+}
+
+// A MileageReport is the mileage of a set of trips.
+type MileageReport struct {
+	Trips    []TripMileage
+	Distance float64       // Total distance in meters
+	Duration time.Duration // Total duration
+	IdleTime time.Duration // Total idle time
+}
+
+// Mileage decodes trips and returns their individual and aggregate mileage,
+// for example for fleet reimbursement. It returns an error if a trip cannot
+// be decoded, or ErrDimensionalMismatch if a trip does not have one time per
+// coordinate.
+func Mileage(trips []Trip, opts MileageOptions) (MileageReport, error) {
+	if opts.Codec.Dim == 0 {
+		opts.Codec = defaultCodec
+	}
+	if opts.Model == nil {
+		opts.Model = Haversine
+	}
+	if opts.IdleSpeed == 0 {
+		opts.IdleSpeed = 0.5
+	}
+
+	report := MileageReport{
+		Trips: make([]TripMileage, 0, len(trips)),
+	}
+	for _, trip := range trips {
+		coords, _, err := opts.Codec.untransformed().DecodeCoords(trip.Polyline)
+		if err != nil {
+			return MileageReport{}, err
+		}
+		if len(coords) != len(trip.Times) {
+			return MileageReport{}, ErrDimensionalMismatch
+		}
+		m := TripMileage{ID: trip.ID}
+		for i := 1; i < len(coords); i++ {
+			d := opts.Model.Distance(coords[i-1], coords[i])
+			dt := trip.Times[i].Sub(trip.Times[i-1])
+			m.Distance += d
+			if dt > 0 && d/dt.Seconds() < opts.IdleSpeed {
+				m.IdleTime += dt
+			}
+		}
+		if len(coords) > 0 {
+			m.Duration = trip.Times[len(coords)-1].Sub(trip.Times[0])
+		}
+		report.Trips = append(report.Trips, m)
+		report.Distance += m.Distance
+		report.Duration += m.Duration
+		report.IdleTime += m.IdleTime
+	}
+	return report, nil
+}
+
+// WriteCSV writes r to w as CSV with a header row, one row per trip, and a
+// final row with the totals. Distances are in kilometers and times in
+// seconds.
+func (r MileageReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	row := func(id string, distance float64, duration, idle time.Duration) []string {
+		return []string{
+			id,
+			strconv.FormatFloat(distance/1000, 'f', 3, 64),
+			strconv.FormatFloat(duration.Seconds(), 'f', 0, 64),
+			strconv.FormatFloat(idle.Seconds(), 'f', 0, 64),
+		}
+	}
+	if err := cw.Write([]string{"trip", "km", "duration_s", "idle_s"}); err != nil {
+		return err
+	}
+	for _, m := range r.Trips {
+		if err := cw.Write(row(m.ID, m.Distance, m.Duration, m.IdleTime)); err != nil {
+			return err
+		}
+	}
+	if err := cw.Write(row("total", r.Distance, r.Duration, r.IdleTime)); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
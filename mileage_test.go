@@ -0,0 +1,64 @@
+package polyline_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+type manhattanModel struct{}
+
+func (manhattanModel) Distance(a, b []float64) float64 {
+	d := func(x float64) float64 {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	return 1e5 * (d(a[0]-b[0]) + d(a[1]-b[1]))
+}
+
+func TestMileage(t *testing.T) {
+	t.Parallel()
+	t0 := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+	trips := []polyline.Trip{
+		{
+			ID:       "a",
+			Polyline: polyline.EncodeCoords([][]float64{{0, 0}, {0.01, 0}, {0.01, 0}, {0.01, 0.01}}),
+			Times:    []time.Time{t0, t0.Add(time.Minute), t0.Add(3 * time.Minute), t0.Add(4 * time.Minute)},
+		},
+		{
+			ID:       "b",
+			Polyline: polyline.EncodeCoords([][]float64{{0, 0}, {0.01, 0}}),
+			Times:    []time.Time{t0, t0.Add(time.Minute)},
+		},
+	}
+	report, err := polyline.Mileage(trips, polyline.MileageOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, report.Trips, 2) {
+		assert.Equal(t, "a", report.Trips[0].ID)
+		assert.InDelta(t, 2223.9, report.Trips[0].Distance, 0.1)
+		assert.Equal(t, 4*time.Minute, report.Trips[0].Duration)
+		assert.Equal(t, 2*time.Minute, report.Trips[0].IdleTime)
+		assert.InDelta(t, 1112.0, report.Trips[1].Distance, 0.1)
+	}
+	assert.InDelta(t, 3335.9, report.Distance, 0.1)
+	assert.Equal(t, 5*time.Minute, report.Duration)
+	assert.Equal(t, 2*time.Minute, report.IdleTime)
+
+	var sb strings.Builder
+	assert.NoError(t, report.WriteCSV(&sb))
+	assert.Equal(t, "trip,km,duration_s,idle_s\na,2.224,240,120\nb,1.112,60,0\ntotal,3.336,300,120\n", sb.String())
+
+	report, err = polyline.Mileage(trips, polyline.MileageOptions{Model: manhattanModel{}})
+	assert.NoError(t, err)
+	assert.InDelta(t, 3000, report.Distance, 1e-6)
+
+	_, err = polyline.Mileage([]polyline.Trip{{Polyline: []byte("??")}}, polyline.MileageOptions{})
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+	_, err = polyline.Mileage([]polyline.Trip{{Polyline: []byte("?")}}, polyline.MileageOptions{})
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+}
@@ -0,0 +1,145 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrInvalidRoute is returned by NewRouteMonitor when a route has fewer than
+// two points or its thresholds are inconsistent.
+var ErrInvalidRoute = errors.New("invalid route")
+
+// A RouteState is whether a vehicle is following a route.
+type RouteState int
+
+// Route states.
+const (
+	OnRoute RouteState = iota
+	OffRoute
+)
+
+func (s RouteState) String() string {
+	switch s {
+	case OnRoute:
+		return "on route"
+	case OffRoute:
+		return "off route"
+	default:
+		return "unknown"
+	}
+}
+
+// RouteMonitorOptions configures a RouteMonitor. Zero fields take default
+// values.
+type RouteMonitorOptions struct {
+	OffRouteMeters float64       // Distance beyond which a fix is off route, default 50
+	OnRouteMeters  float64       // Distance within which a fix is on route, default half of OffRouteMeters
+	Consecutive    int           // Number of consecutive fixes required to change state, default 3
+	Model          DistanceModel // Distance model, default Haversine
+}
+
+// withDefaults returns o with zero fields replaced by their defaults.
+func (o RouteMonitorOptions) withDefaults() RouteMonitorOptions {
+	if o.OffRouteMeters == 0 {
+		o.OffRouteMeters = 50
+	}
+	if o.OnRouteMeters == 0 {
+		o.OnRouteMeters = o.OffRouteMeters / 2
+	}
+	if o.Consecutive == 0 {
+		o.Consecutive = 3
+	}
+	if o.Model == nil {
+		o.Model = Haversine
+	}
+	return o
+}
+
+// A RouteMonitor tracks whether successive fixes follow a route. To avoid
+// flapping on noisy fixes, it only changes state after several consecutive
+// fixes agree, and uses a smaller distance threshold to return to the route
+// than to leave it. A RouteMonitor is not safe for concurrent use.
+type RouteMonitor struct {
+	route [][]float64
+	opts  RouteMonitorOptions
+	state RouteState
+	count int
+}
+
+// NewRouteMonitor returns a new RouteMonitor for route in the OnRoute state.
+// It returns ErrInvalidRoute if route has fewer than two points or
+// OnRouteMeters is greater than OffRouteMeters.
+func NewRouteMonitor(route [][]float64, opts RouteMonitorOptions) (*RouteMonitor, error) {
+	if len(route) < 2 {
+		return nil, fmt.Errorf("%w: %d points", ErrInvalidRoute, len(route))
+	}
+	opts = opts.withDefaults()
+	if opts.OnRouteMeters > opts.OffRouteMeters {
+		return nil, fmt.Errorf("%w: on route threshold %gm exceeds off route threshold %gm", ErrInvalidRoute, opts.OnRouteMeters, opts.OffRouteMeters)
+	}
+	return &RouteMonitor{
+		route: route,
+		opts:  opts,
+	}, nil
+}
+
+// State returns the current state of m.
+func (m *RouteMonitor) State() RouteState {
+	return m.state
+}
+
+// Update updates m with the next fix. It returns the new state, whether the
+// state changed, and the distance from fix to the route in meters.
+func (m *RouteMonitor) Update(fix []float64) (state RouteState, changed bool, offRouteMeters float64) {
+	offRouteMeters = projectOntoPath(m.route, fix, 0, len(m.route)-1, m.opts.Model).distance
+	var violation bool
+	switch m.state {
+	case OnRoute:
+		violation = offRouteMeters > m.opts.OffRouteMeters
+	case OffRoute:
+		violation = offRouteMeters <= m.opts.OnRouteMeters
+	}
+	if !violation {
+		m.count = 0
+		return m.state, false, offRouteMeters
+	}
+	m.count++
+	if m.count < m.opts.Consecutive {
+		return m.state, false, offRouteMeters
+	}
+	m.count = 0
+	m.state = 1 - m.state
+	return m.state, true, offRouteMeters
+}
+
+// A SharedRouteMonitor is a RouteMonitor that is safe for concurrent use, for
+// example when fixes for one vehicle arrive on several connections.
+type SharedRouteMonitor struct {
+	mu sync.Mutex
+	m  *RouteMonitor
+}
+
+// NewSharedRouteMonitor returns a new SharedRouteMonitor, as NewRouteMonitor
+// does.
+func NewSharedRouteMonitor(route [][]float64, opts RouteMonitorOptions) (*SharedRouteMonitor, error) {
+	m, err := NewRouteMonitor(route, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &SharedRouteMonitor{m: m}, nil
+}
+
+// State returns the current state of m.
+func (m *SharedRouteMonitor) State() RouteState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.m.State()
+}
+
+// Update updates m with the next fix, as RouteMonitor.Update does.
+func (m *SharedRouteMonitor) Update(fix []float64) (state RouteState, changed bool, offRouteMeters float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.m.Update(fix)
+}
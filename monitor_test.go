@@ -0,0 +1,82 @@
+package polyline_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/polytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteMonitor(t *testing.T) {
+	t.Parallel()
+	m, err := polyline.NewRouteMonitor(meridian(11), polyline.RouteMonitorOptions{
+		OffRouteMeters: 100,
+		Consecutive:    2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.OnRoute, m.State())
+	for i, tc := range []struct {
+		lng     float64
+		state   polyline.RouteState
+		changed bool
+	}{
+		{lng: 0, state: polyline.OnRoute},
+		{lng: 0.002, state: polyline.OnRoute},
+		{lng: 0, state: polyline.OnRoute},
+		{lng: 0.002, state: polyline.OnRoute},
+		{lng: 0.002, state: polyline.OffRoute, changed: true},
+		{lng: 0.002, state: polyline.OffRoute},
+		{lng: 0.0006, state: polyline.OffRoute},
+		{lng: 0.0004, state: polyline.OffRoute},
+		{lng: 0.0004, state: polyline.OnRoute, changed: true},
+	} {
+		state, changed, off := m.Update([]float64{0.005, tc.lng})
+		assert.Equal(t, tc.state, state, "fix %d", i)
+		assert.Equal(t, tc.changed, changed, "fix %d", i)
+		assert.InDelta(t, tc.lng*111195, off, 1, "fix %d", i)
+	}
+
+	// With distances doubled, a fix 67m from the route is off it.
+	m, err = polyline.NewRouteMonitor(meridian(11), polyline.RouteMonitorOptions{
+		OffRouteMeters: 100,
+		Consecutive:    1,
+		Model:          doubledModel{},
+	})
+	assert.NoError(t, err)
+	state, changed, off := m.Update([]float64{0.005, 0.0006})
+	assert.Equal(t, polyline.OffRoute, state)
+	assert.True(t, changed)
+	assert.InDelta(t, 2*0.0006*111195, off, 1)
+
+	assert.Equal(t, "on route", polyline.OnRoute.String())
+	assert.Equal(t, "off route", polyline.OffRoute.String())
+
+	_, err = polyline.NewRouteMonitor(meridian(1), polyline.RouteMonitorOptions{})
+	assert.ErrorIs(t, err, polyline.ErrInvalidRoute)
+	_, err = polyline.NewRouteMonitor(meridian(11), polyline.RouteMonitorOptions{
+		OffRouteMeters: 50,
+		OnRouteMeters:  100,
+	})
+	assert.ErrorIs(t, err, polyline.ErrInvalidRoute)
+}
+
+func TestSharedRouteMonitor(t *testing.T) {
+	t.Parallel()
+	m, err := polyline.NewSharedRouteMonitor(meridian(11), polyline.RouteMonitorOptions{
+		OffRouteMeters: 100,
+		Consecutive:    8,
+	})
+	assert.NoError(t, err)
+	var changes int32
+	polytest.RequireConcurrent(t, 8, func(int) error {
+		if _, changed, _ := m.Update([]float64{0.005, 0.002}); changed {
+			atomic.AddInt32(&changes, 1)
+		}
+		m.State()
+		return nil
+	})
+	assert.Equal(t, polyline.OffRoute, m.State())
+	assert.Equal(t, int32(1), changes)
+}
@@ -0,0 +1,84 @@
+package polyline
+
+import (
+	"bytes"
+	"math/rand"
+)
+
+// A Mutation is a kind of corruption applied by Mutate.
+type Mutation int
+
+// Mutations.
+const (
+	// MutateTruncate removes a random suffix.
+	MutateTruncate Mutation = iota
+	// MutateFlip replaces a random byte with a different byte that is still
+	// in the valid range.
+	MutateFlip
+	// MutateContinuation toggles the continuation bit of a random byte,
+	// merging or splitting encoded values.
+	MutateContinuation
+	// MutateEscape damages escaping, as done by systems that treat a polyline
+	// as a string: it doubles a backslash, drops a backslash, or escapes a
+	// random byte with a backslash.
+	MutateEscape
+	// MutateInvalid replaces a random byte with a byte outside the valid
+	// range.
+	MutateInvalid
+)
+
+// allMutations is the set of mutations used by Mutate when none are given.
+var allMutations = []Mutation{
+	MutateTruncate,
+	MutateFlip,
+	MutateContinuation,
+	MutateEscape,
+	MutateInvalid,
+}
+
+// Mutate returns a copy of buf with one corruption, chosen at random from
+// kinds, applied to it, for testing the robustness of services that ingest
+// polylines. If kinds is empty then all kinds of mutation are considered. buf
+// itself is not modified. An empty buf is returned unchanged.
+func Mutate(buf []byte, r *rand.Rand, kinds ...Mutation) []byte {
+	result := append([]byte(nil), buf...)
+	if len(result) == 0 {
+		return result
+	}
+	if len(kinds) == 0 {
+		kinds = allMutations
+	}
+
+	i := r.Intn(len(result))
+	switch kinds[r.Intn(len(kinds))] {
+	case MutateTruncate:
+		result = result[:i]
+	case MutateFlip:
+		v := (int(result[i]) - MinByte + 1 + r.Intn(alphabetSize-1)) % alphabetSize
+		if v < 0 {
+			v += alphabetSize
+		}
+		result[i] = byte(MinByte + v)
+	case MutateContinuation:
+		if IsValidPolylineByte(result[i]) {
+			result[i] = MinByte + ((result[i] - MinByte) ^ ContinuationBit)
+		}
+	case MutateEscape:
+		switch j := bytes.IndexByte(result, '\\'); {
+		case j >= 0 && r.Intn(2) == 0:
+			result = append(result[:j], result[j+1:]...)
+		case j >= 0:
+			result = append(result[:j+1], result[j:]...)
+		default:
+			result = append(result[:i+1], result[i:]...)
+			result[i] = '\\'
+		}
+	case MutateInvalid:
+		b := byte(r.Intn(256 - alphabetSize))
+		if b >= MinByte {
+			b += alphabetSize
+		}
+		result[i] = b
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package polyline_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMutate(t *testing.T) {
+	t.Parallel()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	orig := append([]byte(nil), buf...)
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		assert.NotEqual(t, buf, polyline.Mutate(buf, r, polyline.MutateFlip))
+
+		got := polyline.Mutate(buf, r, polyline.MutateTruncate)
+		assert.Less(t, len(got), len(buf))
+		assert.True(t, bytes.HasPrefix(buf, got))
+
+		got = polyline.Mutate(buf, r, polyline.MutateContinuation)
+		assert.Len(t, got, len(buf))
+		assert.NotEqual(t, buf, got)
+
+		got = polyline.Mutate(buf, r, polyline.MutateInvalid)
+		_, _, err := polyline.DecodeCoords(got)
+		assert.ErrorIs(t, err, polyline.ErrInvalidByte)
+
+		got = polyline.Mutate(buf, r, polyline.MutateEscape)
+		assert.NotEqual(t, buf, got)
+
+		got = polyline.Mutate(buf, r)
+		_, _, _ = polyline.DecodeCoords(got)
+	}
+	assert.Equal(t, orig, buf)
+	assert.Empty(t, polyline.Mutate(nil, r))
+}
@@ -0,0 +1,127 @@
+package polyline
+
+import "math"
+
+// MVT geometry command IDs.
+const (
+	mvtMoveTo = 1
+	mvtLineTo = 2
+)
+
+// MVTOptions configures MVTGeometry. Zero fields take default values.
+type MVTOptions struct {
+	Extent int // Tile extent in tile-local units, default 4096
+	Buffer int // Distance beyond the tile edges to keep, in tile-local units, default 0
+}
+
+// MVTGeometry returns coords as the geometry of a Mapbox Vector Tile
+// LineString feature in tile: a sequence of MoveTo and LineTo commands with
+// zigzag-encoded integer parameters relative to the tile's north-west corner.
+// Coordinates are projected to Web Mercator, snapped to the tile-local grid,
+// and clipped to the tile extended by opts.Buffer on each side. Clipping may
+// split coords into several lines, each of which starts with a MoveTo. It
+// returns nil if no part of coords is in the tile.
+//
+// See https://github.com/mapbox/vector-tile-spec/tree/master/2.1#43-geometry-encoding.
+func MVTGeometry(coords [][]float64, tile Tile, opts MVTOptions) []uint32 {
+	if opts.Extent == 0 {
+		opts.Extent = 4096
+	}
+	extent := float64(opts.Extent)
+	n := math.Exp2(float64(tile.Z))
+	project := func(coord []float64) [2]float64 {
+		return [2]float64{
+			((coord[1]+180)/360*n - float64(tile.X)) * extent,
+			(tileY(coord[0], n) - float64(tile.Y)) * extent,
+		}
+	}
+	lo, hi := -float64(opts.Buffer), extent+float64(opts.Buffer)
+
+	// Clip each segment to the box, collecting lines of grid points.
+	var lines [][][2]int
+	var line [][2]int
+	snap := func(p [2]float64) [2]int {
+		return [2]int{int(math.Round(p[0])), int(math.Round(p[1]))}
+	}
+	flush := func() {
+		if len(line) >= 2 {
+			lines = append(lines, line)
+		}
+		line = nil
+	}
+	for i := 0; i+1 < len(coords); i++ {
+		a, b := project(coords[i]), project(coords[i+1])
+		t0, t1, ok := clipSegment(a, b, lo, hi)
+		if !ok {
+			flush()
+			continue
+		}
+		p := snap([2]float64{a[0] + t0*(b[0]-a[0]), a[1] + t0*(b[1]-a[1])})
+		q := snap([2]float64{a[0] + t1*(b[0]-a[0]), a[1] + t1*(b[1]-a[1])})
+		if len(line) == 0 || line[len(line)-1] != p {
+			flush()
+			line = append(line, p)
+		}
+		if q != line[len(line)-1] {
+			line = append(line, q)
+		}
+		if t1 < 1 {
+			flush()
+		}
+	}
+	flush()
+
+	var geometry []uint32
+	var cursor [2]int
+	param := func(p [2]int) {
+		geometry = append(geometry, uint32(zigzag64(int64(p[0]-cursor[0]))), uint32(zigzag64(int64(p[1]-cursor[1]))))
+		cursor = p
+	}
+	for _, line := range lines {
+		geometry = append(geometry, mvtMoveTo|1<<3)
+		param(line[0])
+		geometry = append(geometry, uint32(mvtLineTo|(len(line)-1)<<3))
+		for _, p := range line[1:] {
+			param(p)
+		}
+	}
+	return geometry
+}
+
+// clipSegment clips the segment from a to b to the square [lo, hi] in both
+// axes using the Liang-Barsky algorithm. It returns the parameters of the
+// ends of the clipped segment along ab, and false if no part of it is in the
+// square.
+func clipSegment(a, b [2]float64, lo, hi float64) (t0, t1 float64, ok bool) {
+	t0, t1 = 0, 1
+	for axis := 0; axis < 2; axis++ {
+		d := b[axis] - a[axis]
+		for _, edge := range [2]struct{ p, q float64 }{
+			{-d, a[axis] - lo},
+			{d, hi - a[axis]},
+		} {
+			switch r := edge.q / edge.p; {
+			case edge.p == 0:
+				if edge.q < 0 {
+					return 0, 0, false
+				}
+			case edge.p < 0:
+				t0 = math.Max(t0, r)
+			default:
+				t1 = math.Min(t1, r)
+			}
+		}
+	}
+	return t0, t1, t0 <= t1
+}
+
+// MVTGeometry decodes buf and returns it as the geometry of a Mapbox Vector
+// Tile LineString feature in tile. See MVTGeometry. Tiles are addressed in
+// WGS84, so the codec's Transformer is not applied.
+func (c Codec) MVTGeometry(buf []byte, tile Tile, opts MVTOptions) ([]uint32, error) {
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	return MVTGeometry(coords, tile, opts), nil
+}
@@ -0,0 +1,104 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+// mvtLines decodes MVT LineString geometry commands into absolute lines.
+func mvtLines(t *testing.T, geometry []uint32) [][][2]int {
+	t.Helper()
+	var lines [][][2]int
+	var x, y int
+	unzigzag := func(u uint32) int {
+		return int(int32(u>>1) ^ -int32(u&1))
+	}
+	for i := 0; i < len(geometry); {
+		id, count := geometry[i]&7, int(geometry[i]>>3)
+		i++
+		if id == 1 {
+			lines = append(lines, nil)
+		}
+		for j := 0; j < count; j++ {
+			x += unzigzag(geometry[i])
+			y += unzigzag(geometry[i+1])
+			i += 2
+			lines[len(lines)-1] = append(lines[len(lines)-1], [2]int{x, y})
+		}
+	}
+	return lines
+}
+
+func TestMVTGeometry(t *testing.T) {
+	t.Parallel()
+	// At zoom 1, latitudes 10 and 20 are at these tile-local Y coordinates
+	// in the northern tiles.
+	const y10, y20 = 3867, 3631
+	for _, tc := range []struct {
+		name     string
+		coords   [][]float64
+		tile     polyline.Tile
+		opts     polyline.MVTOptions
+		expected [][][2]int
+	}{
+		{
+			name:     "inside",
+			coords:   [][]float64{{0, 0}, {0, 90}, {0, 90.001}},
+			tile:     polyline.Tile{Z: 0, X: 0, Y: 0},
+			expected: [][][2]int{{{2048, 2048}, {3072, 2048}}},
+		},
+		{
+			name:     "clipped",
+			coords:   [][]float64{{10, -90}, {10, 90}},
+			tile:     polyline.Tile{Z: 1, X: 1, Y: 0},
+			expected: [][][2]int{{{0, y10}, {2048, y10}}},
+		},
+		{
+			name:     "reentry",
+			coords:   [][]float64{{10, -90}, {10, 90}, {20, 90}, {20, -90}},
+			tile:     polyline.Tile{Z: 1, X: 0, Y: 0},
+			expected: [][][2]int{{{2048, y10}, {4096, y10}}, {{4096, y20}, {2048, y20}}},
+		},
+		{
+			name:     "buffer",
+			coords:   [][]float64{{10, -90}, {10, 90}},
+			tile:     polyline.Tile{Z: 1, X: 0, Y: 0},
+			opts:     polyline.MVTOptions{Extent: 512, Buffer: 8},
+			expected: [][][2]int{{{256, 483}, {520, 483}}},
+		},
+		{
+			name:   "outside",
+			coords: [][]float64{{-10, -90}, {-10, 90}},
+			tile:   polyline.Tile{Z: 1, X: 0, Y: 0},
+		},
+		{
+			name:   "single",
+			coords: [][]float64{{0, 0}},
+			tile:   polyline.Tile{Z: 0, X: 0, Y: 0},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, mvtLines(t, polyline.MVTGeometry(tc.coords, tc.tile, tc.opts)))
+		})
+	}
+}
+
+func TestCodecMVTGeometry(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	geometry, err := codec.MVTGeometry(codec.EncodeCoords(nil, [][]float64{{0, 0}, {0, 90}}), polyline.Tile{}, polyline.MVTOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{9, 4096, 4096, 10, 2048, 0}, geometry)
+
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	geometry, err = mercator.MVTGeometry(codec.EncodeCoords(nil, [][]float64{{0, 0}, {0, 90}}), polyline.Tile{}, polyline.MVTOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{9, 4096, 4096, 10, 2048, 0}, geometry)
+
+	_, err = codec.MVTGeometry([]byte("_"), polyline.Tile{}, polyline.MVTOptions{})
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
@@ -0,0 +1,22 @@
+package polyline
+
+import "math"
+
+// NearestPoint projects p onto coords and returns the closest point on
+// coords, the index of the segment containing it, and the distance from p to
+// it in meters, for example to snap a vehicle's icon to its route. Values
+// beyond latitude and longitude, such as elevation, are interpolated along
+// the segment. If several segments are equally close then the first is
+// returned. If coords has a single point then it is returned with segment
+// index 0, and if coords is empty then NearestPoint returns nil, -1, and
+// +Inf.
+func NearestPoint(coords [][]float64, p []float64) (snapped []float64, segmentIndex int, distMeters float64) {
+	switch len(coords) {
+	case 0:
+		return nil, -1, math.Inf(1)
+	case 1:
+		return cloneCoord(coords[0]), 0, haversine(coords[0], p)
+	}
+	proj := projectOntoPath(coords, p, 0, len(coords)-1, nil)
+	return proj.coord, proj.segment, proj.distance
+}
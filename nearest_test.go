@@ -0,0 +1,42 @@
+package polyline_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestPoint(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0, 10}, {0, 0.01, 20}, {0.01, 0.01, 30}, {0, 0.01, 40}}
+	for _, tc := range []struct {
+		name     string
+		coords   [][]float64
+		p        []float64
+		snapped  []float64
+		segment  int
+		distance float64
+	}{
+		{name: "interior", coords: coords, p: []float64{0.001, 0.005}, snapped: []float64{0, 0.005, 15}, segment: 0, distance: 111.2},
+		{name: "vertex", coords: coords, p: []float64{0.02, 0.01}, snapped: []float64{0.01, 0.01, 30}, segment: 1, distance: 1112},
+		{name: "before", coords: coords, p: []float64{0, -0.01}, snapped: []float64{0, 0, 10}, segment: 0, distance: 1112},
+		{name: "tie", coords: coords, p: []float64{0.005, 0.011}, snapped: []float64{0.005, 0.01, 25}, segment: 1, distance: 111.2},
+		{name: "single", coords: coords[:1], p: []float64{0, 0.001}, snapped: []float64{0, 0, 10}, segment: 0, distance: 111.2},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			snapped, segment, distance := polyline.NearestPoint(tc.coords, tc.p)
+			assert.True(t, float64ArrayWithin(tc.snapped, snapped, 1e-9), "want %v, got %v", tc.snapped, snapped)
+			assert.Equal(t, tc.segment, segment)
+			assert.InDelta(t, tc.distance, distance, 1)
+		})
+	}
+
+	snapped, segment, distance := polyline.NearestPoint(nil, []float64{0, 0})
+	assert.Nil(t, snapped)
+	assert.Equal(t, -1, segment)
+	assert.True(t, math.IsInf(distance, 1))
+}
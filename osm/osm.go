@@ -0,0 +1,115 @@
+// Package osm converts OpenStreetMap ways, from OSM XML files or Overpass API
+// JSON responses, to encoded polylines.
+//
+// See https://wiki.openstreetmap.org/wiki/OSM_XML and
+// https://wiki.openstreetmap.org/wiki/Overpass_API/Overpass_QL#Output_format_(out:).
+package osm
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// ErrMissingNode is returned when a way refers to a node whose location is
+// not known.
+var ErrMissingNode = errors.New("missing node")
+
+// A Way is an OpenStreetMap way.
+type Way struct {
+	ID       int64
+	Tags     map[string]string
+	Polyline []byte
+}
+
+// EncodeWay encodes the nodes refs, looking up their [lat, lng] locations in
+// nodes, with codec, which must be two-dimensional.
+func EncodeWay(refs []int64, nodes map[int64][]float64, codec polyline.Codec) ([]byte, error) {
+	coords := make([][]float64, len(refs))
+	for i, ref := range refs {
+		coord, ok := nodes[ref]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrMissingNode, ref)
+		}
+		coords[i] = coord
+	}
+	return codec.EncodeCoords(nil, coords), nil
+}
+
+// xmlNode and xmlWay are the OSM XML elements read by ReadWays.
+type xmlNode struct {
+	ID  int64   `xml:"id,attr"`
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+type xmlWay struct {
+	ID  int64 `xml:"id,attr"`
+	Nds []struct {
+		Ref int64 `xml:"ref,attr"`
+	} `xml:"nd"`
+	Tags []struct {
+		K string `xml:"k,attr"`
+		V string `xml:"v,attr"`
+	} `xml:"tag"`
+}
+
+// ReadWays reads an OSM XML file from r and returns its ways, in file order,
+// encoded with codec, which must be two-dimensional. Nodes may appear before
+// or after the ways that refer to them. Relations and other elements are
+// ignored.
+func ReadWays(r io.Reader, codec polyline.Codec) ([]Way, error) {
+	d := xml.NewDecoder(r)
+	nodes := make(map[int64][]float64)
+	var xmlWays []xmlWay
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "node":
+			var n xmlNode
+			if err := d.DecodeElement(&n, &start); err != nil {
+				return nil, err
+			}
+			nodes[n.ID] = []float64{n.Lat, n.Lon}
+		case "way":
+			var w xmlWay
+			if err := d.DecodeElement(&w, &start); err != nil {
+				return nil, err
+			}
+			xmlWays = append(xmlWays, w)
+		}
+	}
+
+	ways := make([]Way, len(xmlWays))
+	for i, w := range xmlWays {
+		refs := make([]int64, len(w.Nds))
+		for j, nd := range w.Nds {
+			refs[j] = nd.Ref
+		}
+		buf, err := EncodeWay(refs, nodes, codec)
+		if err != nil {
+			return nil, fmt.Errorf("way %d: %w", w.ID, err)
+		}
+		ways[i] = Way{ID: w.ID, Polyline: buf}
+		if len(w.Tags) > 0 {
+			ways[i].Tags = make(map[string]string, len(w.Tags))
+			for _, tag := range w.Tags {
+				ways[i].Tags[tag.K] = tag.V
+			}
+		}
+	}
+	return ways, nil
+}
@@ -0,0 +1,87 @@
+package osm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/osm"
+	"github.com/stretchr/testify/assert"
+)
+
+const osmXML = `<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6" generator="test">
+  <bounds minlat="38" minlon="-127" maxlat="44" maxlon="-120"/>
+  <node id="1" lat="38.5" lon="-120.2"/>
+  <node id="2" lat="40.7" lon="-120.95">
+    <tag k="highway" v="traffic_signals"/>
+  </node>
+  <way id="10">
+    <nd ref="1"/>
+    <nd ref="2"/>
+    <nd ref="3"/>
+    <tag k="highway" v="primary"/>
+    <tag k="name" v="Main Street"/>
+  </way>
+  <way id="11">
+    <nd ref="3"/>
+  </way>
+  <node id="3" lat="43.252" lon="-126.453"/>
+  <relation id="100">
+    <member type="way" ref="10" role=""/>
+  </relation>
+</osm>`
+
+func TestReadWays(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	ways, err := osm.ReadWays(strings.NewReader(osmXML), codec)
+	assert.NoError(t, err)
+	assert.Equal(t, []osm.Way{
+		{
+			ID:       10,
+			Tags:     map[string]string{"highway": "primary", "name": "Main Street"},
+			Polyline: []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"),
+		},
+		{
+			ID:       11,
+			Polyline: codec.EncodeCoords(nil, [][]float64{{43.252, -126.453}}),
+		},
+	}, ways)
+}
+
+func TestReadWaysErrors(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		s    string
+		err  error
+	}{
+		{name: "missing_node", s: `<osm><way id="1"><nd ref="2"/></way></osm>`, err: osm.ErrMissingNode},
+		{name: "invalid_lat", s: `<osm><node id="1" lat="north" lon="0"/></osm>`},
+		{name: "invalid_ref", s: `<osm><way id="1"><nd ref="x"/></way></osm>`},
+		{name: "truncated", s: `<osm><node id="1"`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := osm.ReadWays(strings.NewReader(tc.s), polyline.DefaultCodec())
+			assert.Error(t, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestEncodeWay(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	nodes := map[int64][]float64{1: {38.5, -120.2}, 2: {40.7, -120.95}}
+	buf, err := osm.EncodeWay([]int64{1, 2}, nodes, codec)
+	assert.NoError(t, err)
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC", string(buf))
+
+	_, err = osm.EncodeWay([]int64{1, 3}, nodes, codec)
+	assert.ErrorIs(t, err, osm.ErrMissingNode)
+}
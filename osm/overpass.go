@@ -0,0 +1,70 @@
+package osm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// overpassElement is an element of an Overpass API JSON response.
+type overpassElement struct {
+	Type     string            `json:"type"`
+	ID       int64             `json:"id"`
+	Lat      float64           `json:"lat"`
+	Lon      float64           `json:"lon"`
+	Tags     map[string]string `json:"tags"`
+	Nodes    []int64           `json:"nodes"`
+	Geometry []*struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"geometry"`
+}
+
+// ReadOverpass reads an Overpass API JSON response from r and returns its
+// ways, in response order, encoded with codec, which must be
+// two-dimensional. A way's path is taken from its geometry array, as
+// produced by "out geom", skipping the null entries of nodes outside a
+// clipping bounding box. Ways without geometry are resolved from the node
+// elements of the response, as produced by "out; >; out skel;". Other
+// elements are ignored.
+func ReadOverpass(r io.Reader, codec polyline.Codec) ([]Way, error) {
+	var response struct {
+		Elements []overpassElement `json:"elements"`
+	}
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
+		return nil, err
+	}
+	nodes := make(map[int64][]float64)
+	for _, e := range response.Elements {
+		if e.Type == "node" {
+			nodes[e.ID] = []float64{e.Lat, e.Lon}
+		}
+	}
+
+	var ways []Way
+	for _, e := range response.Elements {
+		if e.Type != "way" {
+			continue
+		}
+		w := Way{ID: e.ID, Tags: e.Tags}
+		if e.Geometry != nil {
+			coords := make([][]float64, 0, len(e.Geometry))
+			for _, p := range e.Geometry {
+				if p != nil {
+					coords = append(coords, []float64{p.Lat, p.Lon})
+				}
+			}
+			w.Polyline = codec.EncodeCoords(nil, coords)
+		} else {
+			buf, err := EncodeWay(e.Nodes, nodes, codec)
+			if err != nil {
+				return nil, fmt.Errorf("way %d: %w", e.ID, err)
+			}
+			w.Polyline = buf
+		}
+		ways = append(ways, w)
+	}
+	return ways, nil
+}
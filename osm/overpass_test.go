@@ -0,0 +1,65 @@
+package osm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/osm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOverpass(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	for _, tc := range []struct {
+		name     string
+		json     string
+		expected []osm.Way
+		err      error
+	}{
+		{
+			name: "geometry",
+			json: `{"version":0.6,"elements":[
+				{"type":"way","id":10,"bounds":{},"nodes":[1,2,3],
+				 "geometry":[{"lat":38.5,"lon":-120.2},null,{"lat":40.7,"lon":-120.95}],
+				 "tags":{"highway":"primary"}},
+				{"type":"relation","id":100,"members":[]}
+			]}`,
+			expected: []osm.Way{
+				{ID: 10, Tags: map[string]string{"highway": "primary"}, Polyline: []byte("_p~iF~ps|U_ulLnnqC")},
+			},
+		},
+		{
+			name: "nodes",
+			json: `{"elements":[
+				{"type":"way","id":10,"nodes":[1,2]},
+				{"type":"node","id":1,"lat":38.5,"lon":-120.2},
+				{"type":"node","id":2,"lat":40.7,"lon":-120.95}
+			]}`,
+			expected: []osm.Way{
+				{ID: 10, Polyline: []byte("_p~iF~ps|U_ulLnnqC")},
+			},
+		},
+		{
+			name: "missing_node",
+			json: `{"elements":[{"type":"way","id":10,"nodes":[1]}]}`,
+			err:  osm.ErrMissingNode,
+		},
+		{
+			name: "empty",
+			json: `{"elements":[]}`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ways, err := osm.ReadOverpass(strings.NewReader(tc.json), codec)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, ways)
+		})
+	}
+
+	_, err := osm.ReadOverpass(strings.NewReader(`{"elements":`), codec)
+	assert.Error(t, err)
+}
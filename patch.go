@@ -0,0 +1,136 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPatch is returned by Codec.ApplyPatch when a patch is malformed
+// or does not fit the polyline it is applied to.
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// ComputePatch returns a patch that transforms the polyline old into the
+// polyline new, for pushing route updates to clients without resending the
+// whole geometry. The patch replaces the smallest range of old's coordinates
+// that differs from new, keeping their common prefix and suffix.
+//
+// A patch is itself text in the polyline alphabet: the start and end
+// indexes of the replaced range of old, followed by the replacement
+// coordinates encoded as deltas from the coordinate before the range.
+// Coordinates are compared as encoded, so neither the Transformer nor
+// CoalesceQuantumDuplicates are applied.
+func (c Codec) ComputePatch(old, new []byte) ([]byte, error) {
+	a, err := c.decodeQuantized(old)
+	if err != nil {
+		return nil, fmt.Errorf("old: %w", err)
+	}
+	b, err := c.decodeQuantized(new)
+	if err != nil {
+		return nil, fmt.Errorf("new: %w", err)
+	}
+	var prefix int
+	for prefix < len(a) && prefix < len(b) && equalInts(a[prefix], b[prefix]) {
+		prefix++
+	}
+	var suffix int
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && equalInts(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+
+	patch := encodeUint(nil, uint(prefix))
+	patch = encodeUint(patch, uint(len(a)-suffix))
+	return c.appendQuantized(patch, anchor(a, prefix, c.Dim), b[prefix:len(b)-suffix]), nil
+}
+
+// ApplyPatch applies a patch computed by ComputePatch to the polyline old
+// and returns the new polyline. It returns ErrInvalidPatch if patch is
+// malformed or its range is not within old.
+func (c Codec) ApplyPatch(old, patch []byte) ([]byte, error) {
+	a, err := c.decodeQuantized(old)
+	if err != nil {
+		return nil, err
+	}
+	start, patch, err := decodeUint(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start: %v", ErrInvalidPatch, err)
+	}
+	end, patch, err := decodeUint(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end: %v", ErrInvalidPatch, err)
+	}
+	if start > end || end > uint(len(a)) {
+		return nil, fmt.Errorf("%w: range [%d, %d) of %d coordinates", ErrInvalidPatch, start, end, len(a))
+	}
+
+	last := anchor(a, int(start), c.Dim)
+	var replacement [][]int
+	for len(patch) > 0 {
+		coord := make([]int, c.Dim)
+		for i := range coord {
+			var delta int
+			if delta, patch, err = decodeInt(patch); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+			}
+			coord[i] = last[i] + delta
+		}
+		replacement = append(replacement, coord)
+		last = coord
+	}
+
+	b := make([][]int, 0, len(a)-int(end-start)+len(replacement))
+	b = append(b, a[:start]...)
+	b = append(b, replacement...)
+	b = append(b, a[end:]...)
+	return c.appendQuantized(nil, make([]int, c.Dim), b), nil
+}
+
+// decodeQuantized decodes buf into quantized coordinates.
+func (c Codec) decodeQuantized(buf []byte) ([][]int, error) {
+	var coords [][]int
+	last := make([]int, c.Dim)
+	for len(buf) > 0 {
+		coord := make([]int, c.Dim)
+		for i := range coord {
+			var delta int
+			var err error
+			if delta, buf, err = decodeInt(buf); err != nil {
+				return nil, err
+			}
+			coord[i] = last[i] + delta
+		}
+		coords = append(coords, coord)
+		last = coord
+	}
+	return coords, nil
+}
+
+// appendQuantized appends the encoding of quantized coordinates as deltas
+// from last to buf.
+func (c Codec) appendQuantized(buf []byte, last []int, coords [][]int) []byte {
+	for _, coord := range coords {
+		for i, x := range coord {
+			buf = encodeInt(buf, x-last[i])
+		}
+		last = coord
+	}
+	return buf
+}
+
+// anchor returns the coordinate before index i of coords, or the origin if
+// there is none.
+func anchor(coords [][]int, i, dim int) []int {
+	if i == 0 {
+		return make([]int, dim)
+	}
+	return coords[i-1]
+}
+
+// equalInts returns whether a and b are equal.
+func equalInts(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
@@ -0,0 +1,80 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatch(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	route := benchmarkCoords(100)
+	detour := append(append(append([][]float64{}, route[:40]...), []float64{1, 1}, []float64{1.1, 1.1}), route[50:]...)
+	for _, tc := range []struct {
+		name     string
+		old, new [][]float64
+		patch    string
+	}{
+		{name: "detour", old: route, new: detour},
+		{name: "identical", old: route, new: route, patch: "cBcB"},
+		{name: "append", old: route[:50], new: route},
+		{name: "truncate", old: route, new: route[:50], patch: "q@cB"},
+		{name: "prepend", old: route[50:], new: route},
+		{name: "from_empty", old: nil, new: route[:3]},
+		{name: "to_empty", old: route[:3], new: nil, patch: "?B"},
+		{name: "both_empty", patch: "??"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			old := codec.EncodeCoords(nil, tc.old)
+			new := codec.EncodeCoords(nil, tc.new)
+			patch, err := codec.ComputePatch(old, new)
+			assert.NoError(t, err)
+			if tc.patch != "" {
+				assert.Equal(t, tc.patch, string(patch))
+			}
+			got, err := codec.ApplyPatch(old, patch)
+			assert.NoError(t, err)
+			assert.Equal(t, string(new), string(got))
+		})
+	}
+
+	old, new := codec.EncodeCoords(nil, route), codec.EncodeCoords(nil, detour)
+	patch, err := codec.ComputePatch(old, new)
+	assert.NoError(t, err)
+	assert.Less(t, len(patch), len(new)/4)
+}
+
+func TestPatchErrors(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	old := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	for _, tc := range []struct {
+		name  string
+		patch string
+		err   error
+	}{
+		{name: "empty", patch: "", err: polyline.ErrInvalidPatch},
+		{name: "no_end", patch: "?", err: polyline.ErrInvalidPatch},
+		{name: "reversed", patch: "A?", err: polyline.ErrInvalidPatch},
+		{name: "beyond", patch: "?I", err: polyline.ErrInvalidPatch},
+		{name: "partial", patch: "??_p~iF", err: polyline.ErrInvalidPatch},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := codec.ApplyPatch(old, []byte(tc.patch))
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	_, err := codec.ApplyPatch([]byte("_"), []byte("??"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	_, err = codec.ComputePatch([]byte("_"), old)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	_, err = codec.ComputePatch(old, []byte("_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
@@ -0,0 +1,158 @@
+package polyline
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// A Polygon is an outer ring followed by zero or more holes. Each ring is a
+// closed sequence of coordinates.
+type Polygon [][][]float64
+
+// Delimiters of the multi-geometry container written by EncodePolygons.
+// Neither is a valid polyline byte.
+const (
+	RingDelimiter    = ','
+	PolygonDelimiter = ';'
+)
+
+// EncodePolygons appends the encoding of polygons to buf and returns the new
+// buf. Each ring is encoded as a separate polyline. Rings are separated by
+// RingDelimiter and polygons by PolygonDelimiter.
+func (c Codec) EncodePolygons(buf []byte, polygons []Polygon) []byte {
+	for i, polygon := range polygons {
+		if i > 0 {
+			buf = append(buf, PolygonDelimiter)
+		}
+		for j, ring := range polygon {
+			if j > 0 {
+				buf = append(buf, RingDelimiter)
+			}
+			buf = c.EncodeCoords(buf, ring)
+		}
+	}
+	return buf
+}
+
+// DecodePolygons decodes polygons encoded by EncodePolygons.
+func (c Codec) DecodePolygons(buf []byte) ([]Polygon, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	var polygons []Polygon
+	for i, p := range bytes.Split(buf, []byte{PolygonDelimiter}) {
+		var polygon Polygon
+		for j, r := range bytes.Split(p, []byte{RingDelimiter}) {
+			ring, _, err := c.DecodeCoords(r)
+			if err != nil {
+				return nil, fmt.Errorf("polygon %d ring %d: %w", i, j, err)
+			}
+			if len(ring) == 0 {
+				return nil, fmt.Errorf("polygon %d ring %d: %w", i, j, ErrEmpty)
+			}
+			polygon = append(polygon, ring)
+		}
+		polygons = append(polygons, polygon)
+	}
+	return polygons, nil
+}
+
+// ringArea returns the absolute planar area of ring in square degrees.
+func ringArea(ring [][]float64) float64 {
+	var a float64
+	for i := range ring {
+		p, q := ring[i], ring[(i+1)%len(ring)]
+		a += p[1]*q[0] - q[1]*p[0]
+	}
+	return math.Abs(a) / 2
+}
+
+// ringContains returns whether p is inside ring, treating latitudes and
+// longitudes as planar coordinates.
+func ringContains(ring [][]float64, p []float64) bool {
+	var inside bool
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a[0] > p[0]) != (b[0] > p[0]) && p[1] < (b[1]-a[1])*(p[0]-a[0])/(b[0]-a[0])+a[1] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// AssembleRings groups rings, such as the rings of an isochrone returned by a
+// routing engine in no particular order, into polygons. A ring nested inside
+// an odd number of other rings is a hole of the smallest ring containing it;
+// any other ring is the outer ring of a polygon. Polygons are ordered by
+// decreasing area.
+func AssembleRings(rings [][][]float64) []Polygon {
+	order := make([]int, len(rings))
+	areas := make([]float64, len(rings))
+	for i, ring := range rings {
+		order[i] = i
+		areas[i] = ringArea(ring)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return areas[order[i]] > areas[order[j]]
+	})
+
+	var polygons []Polygon
+	outer := make(map[int]int) // Index of ring to index of its polygon
+	for k, i := range order {
+		ring := rings[i]
+		depth, parent := 0, -1
+		// Rings that contain ring are larger, so they precede it in order.
+		for _, j := range order[:k] {
+			if len(ring) > 0 && ringContains(rings[j], ring[0]) {
+				depth++
+				parent = j
+			}
+		}
+		if depth%2 == 1 {
+			if p, ok := outer[parent]; ok {
+				polygons[p] = append(polygons[p], ring)
+				continue
+			}
+		}
+		outer[i] = len(polygons)
+		polygons = append(polygons, Polygon{ring})
+	}
+	return polygons
+}
+
+// An Isochrone is the area reachable within a contour, for example a travel
+// time in seconds, as returned by a routing engine.
+type Isochrone struct {
+	Contour  float64
+	Polygons []Polygon
+}
+
+// EncodeIsochrone returns the text form of a Record with iso's contour in its
+// "contour" key and iso's polygons encoded by EncodePolygons.
+func (c Codec) EncodeIsochrone(iso Isochrone) []byte {
+	text, _ := Record{
+		Meta:     map[string]string{"contour": strconv.FormatFloat(iso.Contour, 'g', -1, 64)},
+		Polyline: c.EncodePolygons(nil, iso.Polygons),
+	}.MarshalText()
+	return text
+}
+
+// DecodeIsochrone decodes an isochrone encoded by EncodeIsochrone.
+func (c Codec) DecodeIsochrone(text []byte) (Isochrone, error) {
+	var r Record
+	if err := r.UnmarshalText(text); err != nil {
+		return Isochrone{}, err
+	}
+	contour, err := strconv.ParseFloat(r.Meta["contour"], 64)
+	if err != nil {
+		return Isochrone{}, fmt.Errorf("%w: contour %q", ErrInvalidRecord, r.Meta["contour"])
+	}
+	polygons, err := c.DecodePolygons(r.Polyline)
+	if err != nil {
+		return Isochrone{}, err
+	}
+	return Isochrone{Contour: contour, Polygons: polygons}, nil
+}
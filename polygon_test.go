@@ -0,0 +1,80 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func square(lat, lng, size float64) [][]float64 {
+	return [][]float64{{lat, lng}, {lat, lng + size}, {lat + size, lng + size}, {lat + size, lng}, {lat, lng}}
+}
+
+func TestEncodePolygons(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	polygons := []polyline.Polygon{
+		{square(0, 0, 1), square(0.25, 0.25, 0.5)},
+		{square(5, 5, 1)},
+	}
+	buf := codec.EncodePolygons(nil, polygons)
+	assert.Equal(t, 2, countByte(buf, polyline.PolygonDelimiter)+1)
+	assert.Equal(t, 1, countByte(buf, polyline.RingDelimiter))
+	got, err := codec.DecodePolygons(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, polygons, got)
+
+	got, err = codec.DecodePolygons(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	for _, s := range []string{",??", "??;", "??,_"} {
+		_, err = codec.DecodePolygons([]byte(s))
+		assert.Error(t, err, s)
+	}
+}
+
+func countByte(buf []byte, b byte) int {
+	var n int
+	for _, c := range buf {
+		if c == b {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAssembleRings(t *testing.T) {
+	t.Parallel()
+	outer := square(0, 0, 10)
+	hole := square(2, 2, 6)
+	island := square(4, 4, 2)
+	other := square(20, 20, 1)
+	got := polyline.AssembleRings([][][]float64{island, other, hole, outer})
+	assert.Equal(t, []polyline.Polygon{
+		{outer, hole},
+		{island},
+		{other},
+	}, got)
+	assert.Empty(t, polyline.AssembleRings(nil))
+}
+
+func TestEncodeIsochrone(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	iso := polyline.Isochrone{
+		Contour:  600,
+		Polygons: []polyline.Polygon{{square(0, 0, 1), square(0.25, 0.25, 0.5)}},
+	}
+	text := codec.EncodeIsochrone(iso)
+	assert.Equal(t, "contour=600;|", string(text[:13]))
+	got, err := codec.DecodeIsochrone(text)
+	assert.NoError(t, err)
+	assert.Equal(t, iso, got)
+
+	_, err = codec.DecodeIsochrone([]byte("|??"))
+	assert.ErrorIs(t, err, polyline.ErrInvalidRecord)
+	_, err = codec.DecodeIsochrone([]byte("contour=1;|_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
@@ -3,15 +3,36 @@
 // https://developers.google.com/maps/documentation/utilities/polylinealgorithm.
 //
 // The default codec encodes and decodes two-dimensional coordinates scaled by
-// 1e5. For other dimensionalities and scales create a custom Codec.
+// 1e5. Codec6 and Codec7 are predefined for the scales 1e6 and 1e7, and
+// Encode6 and Decode6 use Codec6. For other dimensionalities and scales
+// create a custom Codec, for example with DefaultCodec().WithDim(3).
 //
 // The package operates on byte slices. Encoding functions take an existing byte
 // slice as input (which can be nil) and return a new byte slice with the
 // encoded value appended to it, similarly to how Go's append function works. To
 // increase performance, you can pre-allocate byte slices, for example by
-// passing make([]byte, 0, 128) as the input byte slice. Similarly, decoding
+// passing make([]byte, 0, 128) as the input byte slice or by calling
+// Codec.Grow. Otherwise, encoders estimate the size of their output from the
+// first few coordinates and grow the byte slice once. Similarly, decoding
 // functions take a byte slice as input and return the remaining unconsumed
 // bytes as output.
+//
+// Codec is a value type whose methods do not modify it, so a Codec and the
+// package-level functions are safe for concurrent use by multiple goroutines.
+// Types whose methods accumulate state, such as CoordBuffer, Decoder,
+// Encoder, and RouteMonitor, are not, and must either be confined to one
+// goroutine or wrapped, for example with SharedRouteMonitor. CachingEncoder
+// locks internally and is safe for concurrent use.
+//
+// Polylines always encode WGS84 latitudes and longitudes. A Codec's
+// Transformer applies only to the coordinates taken and returned by its
+// encoding and decoding methods, such as EncodeCoords, DecodeCoords, and
+// Decoder, and by conversions to formats without a fixed reference system,
+// such as WKB and WKT. Methods that measure or analyze polylines, such as
+// Length, Summarize, and SnapStops, the shapes of EncodeBounds,
+// EncodeCircle, and DecodeShape, and conversions to formats defined in
+// WGS84, such as GeoJSON, KML, geobuf, and vector tiles, work in WGS84
+// whatever the codec's CRS. So do the package-level geometry functions.
 package polyline
 
 import (
@@ -36,46 +57,151 @@ func round(x float64) int {
 	return int(math.Floor(x + 0.5))
 }
 
+// roundJS rounds x to the nearest integer, rounding halves towards positive
+// infinity, as JavaScript's Math.round does.
+func roundJS(x float64) int {
+	r := math.Floor(x)
+	if x-r >= 0.5 {
+		r++
+	}
+	return int(r)
+}
+
+// A Rounding is a method of rounding scaled coordinates to integers.
+type Rounding int
+
+// Roundings.
+const (
+	// RoundHalfAwayFromZero rounds halves away from zero, so -0.5 quanta
+	// round to -1. It is the default.
+	RoundHalfAwayFromZero Rounding = iota
+	// RoundJS rounds halves towards positive infinity, so -0.5 quanta round
+	// to zero, and -0 encodes as zero. These are the semantics of
+	// JavaScript's Math.round, for interoperating with JavaScript encoders
+	// that round with it. Such encoders typically also use 32-bit bitwise
+	// operators, which agree with this package as long as scaled deltas fit
+	// in 30 bits, as they always do for latitudes and longitudes at scales
+	// up to 1e6.
+	RoundJS
+)
+
 // A Codec represents an encoder.
 type Codec struct {
-	Dim   int     // Dimensionality, normally 2
-	Scale float64 // Scale, normally 1e5
+	Dim      int      // Dimensionality, normally 2
+	Scale    float64  // Scale, normally 1e5
+	Rounding Rounding // Rounding, normally RoundHalfAwayFromZero
+
+	// Scales, if set, holds the scale of each dimension and overrides Scale,
+	// for dimensions with mixed units, for example 1e5 for latitude and
+	// longitude, 1e2 for elevation in meters, and 1 for timestamps in
+	// seconds. It must have length Dim.
+	Scales []float64
+
+	// CoalesceQuantumDuplicates makes encoders treat moves of less than half
+	// a quantum from the raw value that was last quantized as no move at all,
+	// and drop coordinates that would encode as zero deltas in every
+	// dimension. This removes the spurious short deltas produced by
+	// stationary but noisy sources whose values straddle a rounding boundary.
+	// As the quantized value itself may be up to half a quantum from that raw
+	// value, an encoded value may be up to, but less than, one quantum from
+	// the coordinate it encodes.
+	CoalesceQuantumDuplicates bool
+
+	// CRS names the coordinate reference system of the coordinates passed to
+	// and returned by the codec, for example "EPSG:27700". Empty means WGS84.
+	// Polylines themselves always encode WGS84 latitudes and longitudes, so a
+	// codec with another CRS needs a Transformer; see Validate.
+	CRS string
+	// Transformer, if set, reprojects coordinates from CRS to WGS84 before
+	// encoding and back after decoding. Geometry methods ignore it; see the
+	// package documentation.
+	Transformer Transformer
 }
 
 var defaultCodec = Codec{Dim: 2, Scale: 1e5}
 
+// Two-dimensional codecs with five, six, and seven decimal places of
+// precision. Codec5 is the precision of Google's polylines, Codec6 that of
+// OSRM and Valhalla, and Codec7 that of some high-precision surveying
+// sources.
+var (
+	Codec5 = Codec{Dim: 2, Scale: 1e5}
+	Codec6 = Codec{Dim: 2, Scale: 1e6}
+	Codec7 = Codec{Dim: 2, Scale: 1e7}
+)
+
+// DefaultCodec returns the codec used by the package-level functions.
+func DefaultCodec() Codec {
+	return defaultCodec
+}
+
+// WithScale returns a copy of c with scale s.
+func (c Codec) WithScale(s float64) Codec {
+	c.Scale = s
+	return c
+}
+
+// WithScales returns a copy of c with per-dimension scales s.
+func (c Codec) WithScales(s ...float64) Codec {
+	c.Scales = s
+	return c
+}
+
+// WithDim returns a copy of c with dimensionality d.
+func (c Codec) WithDim(d int) Codec {
+	c.Dim = d
+	return c
+}
+
+// scale returns the scale of dimension i.
+func (c Codec) scale(i int) float64 {
+	if c.Scales != nil {
+		return c.Scales[i]
+	}
+	return c.Scale
+}
+
+// quantize returns x, a value of dimension i, scaled and rounded to an
+// integer.
+func (c Codec) quantize(i int, x float64) int {
+	if c.Rounding == RoundJS {
+		return roundJS(c.scale(i) * x)
+	}
+	return round(c.scale(i) * x)
+}
+
 // decodeUint decodes a single unsigned integer from buf. It returns the decoded
 // uint, the remaining unconsumed bytes of buf, and any error.
 func decodeUint(buf []byte) (uint, []byte, error) {
 	if len(buf) == 0 {
 		return 0, nil, ErrEmpty
 	}
-	n := strconv.IntSize / 5
+	n := strconv.IntSize / ValueBits
 	if n > len(buf) {
 		n = len(buf)
 	}
 	var u, shift uint
 	for i := 0; i < n; i++ {
 		switch b := buf[i]; {
-		case 95 <= b && b < 127:
-			u += (uint(b) - 95) << shift
-			shift += 5
-		case 63 <= b && b < 95:
-			u += (uint(b) - 63) << shift
+		case ContinuationByte <= b && b <= MaxByte:
+			u += (uint(b) - ContinuationByte) << shift
+			shift += ValueBits
+		case IsTerminalPolylineByte(b):
+			u += (uint(b) - MinByte) << shift
 			return u, buf[i+1:], nil
 		default:
 			return 0, nil, ErrInvalidByte
 		}
 	}
-	if len(buf) <= strconv.IntSize/5 {
+	if len(buf) <= strconv.IntSize/ValueBits {
 		return 0, nil, ErrUnterminatedSequence
 	}
-	max := byte(1<<(strconv.IntSize-5*(strconv.IntSize/5)) - 1)
+	max := byte(1<<(strconv.IntSize-ValueBits*(strconv.IntSize/ValueBits)) - 1)
 	switch b := buf[n]; {
-	case 63 <= b && b <= 63+max:
-		u += (uint(b) - 63) << shift
+	case MinByte <= b && b <= MinByte+max:
+		u += (uint(b) - MinByte) << shift
 		return u, buf[n+1:], nil
-	case b < 127:
+	case b <= MaxByte:
 		return 0, nil, ErrOverflow
 	default:
 		return 0, nil, ErrInvalidByte
@@ -100,11 +226,11 @@ func decodeInt(buf []byte) (int, []byte, error) {
 // encodeUint appends the encoding of a single unsigned integer u to buf and
 // returns the new buf.
 func encodeUint(buf []byte, u uint) []byte {
-	for u >= 32 {
-		buf = append(buf, byte((u&31)+95))
-		u >>= 5
+	for u >= ContinuationBit {
+		buf = append(buf, byte((u&(ContinuationBit-1))+ContinuationByte))
+		u >>= ValueBits
 	}
-	buf = append(buf, byte(u+63))
+	buf = append(buf, byte(u+MinByte))
 	return buf
 }
 
@@ -131,15 +257,15 @@ func (c Codec) decodeCoord(buf []byte) ([]float64, []byte, error) {
 		if err != nil {
 			return nil, nil, err
 		}
-		coord[i] = float64(j) / c.Scale
+		coord[i] = float64(j) / c.scale(i)
 	}
 	return coord, buf, nil
 }
 
 // encodeCoord encodes a single coordinate to buf and returns the new buf.
 func (c Codec) encodeCoord(buf []byte, coord []float64) []byte {
-	for _, x := range coord {
-		buf = encodeInt(buf, round(c.Scale*x))
+	for i, x := range coord {
+		buf = encodeInt(buf, c.quantize(i, x))
 	}
 	return buf
 }
@@ -153,45 +279,327 @@ func decodeCoord(buf []byte) ([]float64, []byte, error) {
 // EncodePoints simplifies and generate an encoded polyline from the given points
 // Tolerance is a float from 0.1->5.0 (higher signifies more lossy compression)
 // UseHighQuality excludes distance-based preprocessing step which leads to highest quality simplification but runs ~10-20 times slower.
+// For a tolerance in meters use EncodePointsWith with SimplifyOptions.Meters.
 func (c Codec) EncodePoints(points []Point, tolerance float64, useHighQuality bool) []byte {
-	simplifiedPoints := Simplify(&points, tolerance, useHighQuality)
-	buf := make([]byte, 0)
-	last := make([]int, c.Dim)
-	for _, point := range simplifiedPoints {
-		ex := round(c.Scale * point.GetX())
-		buf = encodeInt(buf, ex-last[0])
-		last[0] = ex
+	opts := SimplifyOptions{Tolerance: tolerance}
+	if useHighQuality {
+		opts.RadialTolerance = -1
+	}
+	return c.EncodePointsWith(points, opts)
+}
 
-		ex = round(c.Scale * point.GetY())
-		buf = encodeInt(buf, ex-last[1])
-		last[1] = ex
+// EncodePointsWith simplifies points as configured by opts and encodes the
+// result. Each point's X and Y are its first two dimensions. The third
+// dimension is the Z of points that implement Point3, and any other
+// dimensions of c are zero. Simplification is two-dimensional; use
+// EncodePoints3DWith to take Z into account.
+func (c Codec) EncodePointsWith(points []Point, opts SimplifyOptions) []byte {
+	return c.encodePoints(SimplifyWith(points, opts))
+}
+
+// encodePoints returns the encoding of points. Points that implement Point3
+// contribute their Z values to the third dimension.
+func (c Codec) encodePoints(points []Point) []byte {
+	flat := make([]float64, len(points)*c.Dim)
+	coords := make([][]float64, len(points))
+	for i, point := range points {
+		coord := flat[i*c.Dim : (i+1)*c.Dim : (i+1)*c.Dim]
+		switch {
+		case c.Dim >= 3:
+			if p3, ok := point.(Point3); ok {
+				coord[2] = p3.GetZ()
+			}
+			fallthrough
+		case c.Dim == 2:
+			coord[1] = point.GetY()
+			fallthrough
+		case c.Dim == 1:
+			coord[0] = point.GetX()
+		}
+		coords[i] = coord
 	}
-	return buf
+	return c.EncodeCoords(nil, coords)
 }
 
 // DecodePolyLine decodes an array of coordinates from buf. It returns the
 // coordinates, the remaining unconsumed bytes of buf, and any error.
 func (c Codec) DecodePolyLine(str string) ([][]float64, []byte, error) {
-	buf := []byte(str)
+	return c.DecodeCoords([]byte(str))
+}
+
+// DecodeCoords decodes an array of coordinates from buf. It returns the
+// coordinates, the remaining unconsumed bytes of buf, and any error.
+func (c Codec) DecodeCoords(buf []byte) ([][]float64, []byte, error) {
 	if len(buf) == 0 {
 		return nil, buf, nil
 	}
-	var coord []float64
-	var err error
-	coord, buf, err = c.decodeCoord(buf)
+	b := &CoordBuffer{dim: c.Dim}
+	rest, err := c.DecodeInto(b, buf)
 	if err != nil {
 		return nil, nil, err
 	}
-	coords := [][]float64{coord}
-	for i := 1; len(buf) > 0; i++ {
-		coord, buf, err = c.decodeCoord(buf)
+	return b.Coords(), rest, nil
+}
+
+// DecodeFlatCoords decodes coordinates from buf, appending them to a
+// one-dimensional array. It returns the coordinates, the remaining unconsumed
+// bytes of buf, and any error.
+func (c Codec) DecodeFlatCoords(fcs []float64, buf []byte) ([]float64, []byte, error) {
+	if len(fcs)%c.Dim != 0 {
+		return nil, nil, ErrDimensionalMismatch
+	}
+	if c.Transformer != nil {
+		n := len(fcs)
+		fcs, rest, err := c.untransformed().DecodeFlatCoords(fcs, buf)
 		if err != nil {
 			return nil, nil, err
 		}
-		for j := range coord {
-			coord[j] += coords[i-1][j]
+		c.fromWGS84Flat(fcs[n:])
+		return fcs, rest, nil
+	}
+	if c.Dim == 2 {
+		return c.decodeFlatCoords2(fcs, buf)
+	}
+	last := make([]int, c.Dim)
+	for len(buf) > 0 {
+		for j := 0; j < c.Dim; j++ {
+			var err error
+			var k int
+			k, buf, err = decodeInt(buf)
+			if err != nil {
+				return nil, nil, err
+			}
+			last[j] += k
+			fcs = append(fcs, float64(last[j])/c.scale(j))
 		}
-		coords = append(coords, coord)
 	}
-	return coords, nil, nil
+	return fcs, nil, nil
+}
+
+// EncodeCoords appends the encoding of an array of coordinates coords to buf
+// and returns the new buf.
+func (c Codec) EncodeCoords(buf []byte, coords [][]float64) []byte {
+	switch {
+	case c.Transformer != nil:
+		return c.untransformed().EncodeCoords(buf, c.toWGS84(coords))
+	case c.CoalesceQuantumDuplicates:
+		return c.encodeCoordsCoalesced(buf, coords)
+	case c.Dim == 2:
+		return c.encodeCoords2(buf, coords)
+	}
+	return c.encodeCoordsGeneric(buf, coords)
+}
+
+// encodeCoordsGeneric is the implementation of EncodeCoords for any number
+// of dimensions.
+func (c Codec) encodeCoordsGeneric(buf []byte, coords [][]float64) []byte {
+	var start int
+	last := make([]int, c.Dim)
+	for k, coord := range coords {
+		switch k {
+		case 1:
+			start = len(buf)
+		case growSample:
+			buf = growRemaining(buf, start, k-1, len(coords)-1)
+		}
+		for i, x := range coord {
+			ex := c.quantize(i, x)
+			buf = encodeInt(buf, ex-last[i])
+			last[i] = ex
+		}
+	}
+	return buf
+}
+
+// encodeCoords2 is the two-dimensional specialization of EncodeCoords. It
+// falls back to encodeCoordsGeneric if a coordinate does not have two values.
+func (c Codec) encodeCoords2(buf []byte, coords [][]float64) []byte {
+	n := len(buf)
+	var start int
+	var lastX, lastY int
+	for i, coord := range coords {
+		if len(coord) != 2 {
+			return c.encodeCoordsGeneric(buf[:n], coords)
+		}
+		switch i {
+		case 1:
+			start = len(buf)
+		case growSample:
+			buf = growRemaining(buf, start, i-1, len(coords)-1)
+		}
+		x, y := c.quantize(0, coord[0]), c.quantize(1, coord[1])
+		buf = encodeInt(buf, x-lastX)
+		buf = encodeInt(buf, y-lastY)
+		lastX, lastY = x, y
+	}
+	return buf
+}
+
+// encodeCoordsCoalesced is the implementation of EncodeCoords when
+// CoalesceQuantumDuplicates is set.
+func (c Codec) encodeCoordsCoalesced(buf []byte, coords [][]float64) []byte {
+	c.coalescedDeltas(coords, func(delta int) {
+		buf = encodeInt(buf, delta)
+	})
+	return buf
+}
+
+// coalescedDeltas calls f with each delta that encodes coords when
+// CoalesceQuantumDuplicates is set.
+func (c Codec) coalescedDeltas(coords [][]float64, f func(int)) {
+	s := c.newDeltaState()
+	for _, coord := range coords {
+		s.deltas(coord, f)
+	}
+}
+
+// A deltaState holds the state carried between coordinates by an encoder.
+type deltaState struct {
+	c     Codec
+	first bool
+	last  []int
+	ex    []int
+	// anchor holds, for each dimension, the value that was last quantized.
+	// Comparing against it rather than the previous coordinate prevents slow
+	// drift from accumulating through a series of small moves.
+	anchor []float64
+}
+
+// newDeltaState returns the state of an encoder before the first coordinate.
+func (c Codec) newDeltaState() *deltaState {
+	return &deltaState{
+		c:      c,
+		first:  true,
+		last:   make([]int, c.Dim),
+		ex:     make([]int, c.Dim),
+		anchor: make([]float64, c.Dim),
+	}
+}
+
+// deltas calls f with each delta that encodes coord, the next coordinate,
+// and updates s. If CoalesceQuantumDuplicates is set and coord does not move
+// then f is not called.
+func (s *deltaState) deltas(coord []float64, f func(int)) {
+	c := s.c
+	moved := s.first || !c.CoalesceQuantumDuplicates
+	for i, x := range coord {
+		if !s.first && c.CoalesceQuantumDuplicates && math.Abs(c.scale(i)*(x-s.anchor[i])) < 0.5 {
+			s.ex[i] = s.last[i]
+			continue
+		}
+		s.ex[i] = c.quantize(i, x)
+		s.anchor[i] = x
+		if s.ex[i] != s.last[i] {
+			moved = true
+		}
+	}
+	s.first = false
+	if !moved {
+		return
+	}
+	for i := range coord {
+		f(s.ex[i] - s.last[i])
+		s.last[i] = s.ex[i]
+	}
+}
+
+// EncodeFlatCoords encodes a one-dimensional array of coordinates to buf. It
+// returns the new buf and any error.
+func (c Codec) EncodeFlatCoords(buf []byte, fcs []float64) ([]byte, error) {
+	if len(fcs)%c.Dim != 0 {
+		return nil, ErrDimensionalMismatch
+	}
+	switch {
+	case c.Transformer != nil:
+		return c.untransformed().EncodeFlatCoords(buf, c.toWGS84Flat(fcs))
+	case c.CoalesceQuantumDuplicates:
+		coords := make([][]float64, len(fcs)/c.Dim)
+		for i := range coords {
+			coords[i] = fcs[i*c.Dim : (i+1)*c.Dim]
+		}
+		return c.encodeCoordsCoalesced(buf, coords), nil
+	case c.Dim == 2:
+		return c.encodeFlatCoords2(buf, fcs), nil
+	}
+	var start int
+	last := make([]int, c.Dim)
+	for i, x := range fcs {
+		switch i {
+		case c.Dim:
+			start = len(buf)
+		case growSample * c.Dim:
+			buf = growRemaining(buf, start, growSample-1, len(fcs)/c.Dim-1)
+		}
+		j := i % c.Dim
+		ex := c.quantize(j, x)
+		buf = encodeInt(buf, ex-last[j])
+		last[j] = ex
+	}
+	return buf, nil
+}
+
+// decodeFlatCoords2 is the two-dimensional specialization of
+// DecodeFlatCoords.
+func (c Codec) decodeFlatCoords2(fcs []float64, buf []byte) ([]float64, []byte, error) {
+	var x, y int
+	for len(buf) > 0 {
+		dx, rest, err := decodeInt(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		dy, rest, err := decodeInt(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		buf = rest
+		x += dx
+		y += dy
+		fcs = append(fcs, float64(x)/c.scale(0), float64(y)/c.scale(1))
+	}
+	return fcs, nil, nil
+}
+
+// encodeFlatCoords2 is the two-dimensional specialization of
+// EncodeFlatCoords. len(fcs) must be even.
+func (c Codec) encodeFlatCoords2(buf []byte, fcs []float64) []byte {
+	var start int
+	var lastX, lastY int
+	for i := 0; i < len(fcs); i += 2 {
+		switch i {
+		case 2:
+			start = len(buf)
+		case 2 * growSample:
+			buf = growRemaining(buf, start, growSample-1, len(fcs)/2-1)
+		}
+		x, y := c.quantize(0, fcs[i]), c.quantize(1, fcs[i+1])
+		buf = encodeInt(buf, x-lastX)
+		buf = encodeInt(buf, y-lastY)
+		lastX, lastY = x, y
+	}
+	return buf
+}
+
+// DecodeCoords decodes an array of coordinates from buf using the default
+// codec. It returns the coordinates, the remaining unconsumed bytes of buf,
+// and any error.
+func DecodeCoords(buf []byte) ([][]float64, []byte, error) {
+	return defaultCodec.DecodeCoords(buf)
+}
+
+// EncodeCoords returns the encoding of an array of coordinates using the
+// default codec.
+func EncodeCoords(coords [][]float64) []byte {
+	return defaultCodec.EncodeCoords(nil, coords)
+}
+
+// Decode6 decodes an array of coordinates from buf using Codec6, the
+// precision of OSRM and Valhalla. It returns the coordinates, the remaining
+// unconsumed bytes of buf, and any error.
+func Decode6(buf []byte) ([][]float64, []byte, error) {
+	return Codec6.DecodeCoords(buf)
+}
+
+// Encode6 returns the encoding of an array of coordinates using Codec6.
+func Encode6(coords [][]float64) []byte {
+	return Codec6.EncodeCoords(nil, coords)
 }
@@ -1,6 +1,7 @@
 package polyline_test
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 	"reflect"
@@ -8,6 +9,7 @@ import (
 	"testing/quick"
 
 	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/polytest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -334,3 +336,248 @@ func TestFlatCoordsQuick(t *testing.T) {
 	}
 	assert.NoError(t, quick.Check(f, nil))
 }
+
+func TestCodecDimensionalSpecialization(t *testing.T) {
+	t.Parallel()
+	// A three-dimensional codec with a zero third dimension uses the generic
+	// path, so its output must match the two-dimensional fast path.
+	generic := polyline.Codec{Dim: 3, Scale: 1e5}
+	fast := polyline.Codec{Dim: 2, Scale: 1e5}
+	f := func(qc QuickCoords) bool {
+		cs3 := withZeroDim(qc)
+		buf2 := fast.EncodeCoords(nil, qc)
+		buf3 := generic.EncodeCoords(nil, cs3)
+		got2, _, err := fast.DecodeCoords(buf2)
+		if err != nil {
+			return false
+		}
+		got3, _, err := generic.DecodeCoords(buf3)
+		if err != nil || len(got2) != len(got3) {
+			return false
+		}
+		for i := range got2 {
+			if !float64ArrayWithin(got2[i], got3[i][:2], 1e-9) {
+				return false
+			}
+		}
+		return len(buf3) == len(buf2)+len(qc)
+	}
+	assert.NoError(t, quick.Check(f, nil))
+}
+
+func TestEncodePointsDim(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	points := make([]polyline.Point, len(coords))
+	for i, c := range coords {
+		points[i] = polyline.ChartPoint{X: c[0], Y: c[1]}
+	}
+	for _, codec := range []polyline.Codec{
+		{Dim: 2, Scale: 1e5},
+		{Dim: 3, Scale: 1e5},
+		{Dim: 3, Scale: 1e6, CoalesceQuantumDuplicates: true},
+	} {
+		want := coords
+		if codec.Dim == 3 {
+			want = withZeroDim(coords)
+		}
+		assert.Equal(t, codec.EncodeCoords(nil, want), codec.EncodePoints(points, 1e-9, true))
+	}
+}
+
+func TestEncodePoints3D(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{38.5, -120.2, 100}, {40.7, -120.95, 2000}, {43.252, -126.453, 50}}
+	points := make([]polyline.Point3, len(coords))
+	generic := make([]polyline.Point, len(coords))
+	for i, c := range coords {
+		points[i] = polyline.ChartPoint3{X: c[0], Y: c[1], Z: c[2]}
+		generic[i] = points[i]
+	}
+	codec := polyline.Codec{Dim: 3, Scale: 1e5}
+	assert.Equal(t, codec.EncodeCoords(nil, coords), codec.EncodePoints3D(points, 1e-9, true))
+	// Two-dimensional encoding keeps the Z values of Point3s.
+	assert.Equal(t, codec.EncodeCoords(nil, coords), codec.EncodePoints(generic, 1e-9, true))
+	assert.Equal(t, polyline.EncodeCoords([][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}), polyline.DefaultCodec().EncodePoints3D(points, 1e-9, true))
+	assert.Equal(t, polyline.Codec{Dim: 4, Scale: 1e5}.EncodeCoords(nil, [][]float64{{38.5, -120.2, 100, 0}}), polyline.Codec{Dim: 4, Scale: 1e5}.EncodePoints3D(points[:1], 1, false))
+
+	// A climb on a straight road survives 3D simplification only.
+	climb := []polyline.Point3{
+		polyline.ChartPoint3{X: 0, Y: 0, Z: 0},
+		polyline.ChartPoint3{X: 0, Y: 1, Z: 500},
+		polyline.ChartPoint3{X: 0, Y: 2, Z: 0},
+	}
+	assert.Len(t, polyline.Simplify3(climb, polyline.SimplifyOptions{Tolerance: 0.5, ZScale: 1.0 / 100}), 3)
+	buf := codec.EncodePoints3DWith(climb, polyline.SimplifyOptions{Tolerance: 0.5, ZScale: 1.0 / 1000})
+	assert.Equal(t, codec.EncodeCoords(nil, [][]float64{{0, 0, 0}, {0, 2, 0}}), buf)
+}
+
+func TestCodecWith(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	assert.Equal(t, polyline.Codec{Dim: 2, Scale: 1e5}, codec)
+	assert.Equal(t, polyline.Codec{Dim: 3, Scale: 1e6}, codec.WithScale(1e6).WithDim(3))
+	assert.Equal(t, polyline.Codec{Dim: 2, Scale: 1e5}, codec)
+
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	points := make([]polyline.Point, len(coords))
+	for i, c := range coords {
+		points[i] = polyline.ChartPoint{X: c[0], Y: c[1]}
+	}
+	e6 := codec.WithScale(1e6)
+	buf := e6.EncodePoints(points, 1e-9, true)
+	assert.Equal(t, e6.EncodeCoords(nil, coords), buf)
+	got, _, err := e6.DecodePolyLine(string(buf))
+	assert.NoError(t, err)
+	assert.Equal(t, coords, got)
+	assert.Equal(t, polyline.EncodeCoords(coords), codec.EncodeCoords(nil, coords))
+}
+
+func TestCodecPresets(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, polyline.DefaultCodec(), polyline.Codec5)
+	assert.Equal(t, polyline.DefaultCodec().WithScale(1e6), polyline.Codec6)
+	assert.Equal(t, polyline.DefaultCodec().WithScale(1e7), polyline.Codec7)
+
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	buf := polyline.Encode6(coords)
+	assert.Equal(t, "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI", string(buf))
+	got, rest, err := polyline.Decode6(buf)
+	assert.NoError(t, err)
+	assert.Empty(t, rest)
+	assert.Equal(t, coords, got)
+	got, _, err = polyline.Codec7.DecodeCoords(polyline.Codec7.EncodeCoords(nil, coords))
+	assert.NoError(t, err)
+	assert.Equal(t, coords, got)
+}
+
+func TestCodecScales(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 3, Scale: 1e5}.WithScales(1e5, 1e5, 1e2)
+	assert.NoError(t, codec.Validate())
+	coords := [][]float64{{38.5, -120.2, 123.45}, {40.7, -120.95, 130}, {43.252, -126.453, -2.5}}
+	buf := codec.EncodeCoords(nil, coords)
+	got, rest, err := codec.DecodeCoords(buf)
+	assert.NoError(t, err)
+	assert.Empty(t, rest)
+	assert.Equal(t, coords, got)
+
+	// Scaling elevation by 1e2 is the same as storing it in kilometers at 1e5.
+	km := make([][]float64, len(coords))
+	for i, c := range coords {
+		km[i] = []float64{c[0], c[1], c[2] / 1e3}
+	}
+	assert.Equal(t, polyline.Codec{Dim: 3, Scale: 1e5}.EncodeCoords(nil, km), buf)
+
+	flat, _, err := codec.DecodeFlatCoords(nil, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{38.5, -120.2, 123.45, 40.7, -120.95, 130, 43.252, -126.453, -2.5}, flat)
+	flatBuf, err := codec.EncodeFlatCoords(nil, flat)
+	assert.NoError(t, err)
+	assert.Equal(t, buf, flatBuf)
+
+	mixed := polyline.Codec{Dim: 2}.WithScales(1e5, 1e6)
+	buf = mixed.EncodeCoords(nil, [][]float64{{38.5, -120.2}, {40.7, -120.95}})
+	assert.Equal(t, "_p~iF~rlgdF_ulL~ywl@", string(buf))
+	got, _, err = mixed.DecodeCoords(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{38.5, -120.2}, {40.7, -120.95}}, got)
+}
+
+func TestCodecConcurrent(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5, CoalesceQuantumDuplicates: true}
+	coords := benchmarkCoords(100)
+	want := codec.EncodeCoords(nil, coords)
+	polytest.RequireConcurrent(t, 8, func(int) error {
+		buf := codec.EncodeCoords(nil, coords)
+		if !reflect.DeepEqual(want, buf) {
+			return fmt.Errorf("got %q, want %q", buf, want)
+		}
+		_, _, err := codec.DecodeCoords(buf)
+		return err
+	})
+}
+
+func withZeroDim(coords [][]float64) [][]float64 {
+	result := make([][]float64, len(coords))
+	for i, c := range coords {
+		result[i] = []float64{c[0], c[1], 0}
+	}
+	return result
+}
+
+func benchmarkCoords(n int) [][]float64 {
+	r := rand.New(rand.NewSource(0))
+	coords := make([][]float64, n)
+	lat, lng := 45.0, 7.0
+	for i := range coords {
+		lat += 1e-3 * (r.Float64() - 0.5)
+		lng += 1e-3 * (r.Float64() - 0.5)
+		coords[i] = []float64{lat, lng}
+	}
+	return coords
+}
+
+func TestEncodeCoordsShort(t *testing.T) {
+	t.Parallel()
+	// A short coordinate must not make the two-dimensional fast path panic.
+	coords := [][]float64{{38.5, -120.2}, {40.7}}
+	assert.Equal(t, []byte("_p~iF~ps|U_ulL"), polyline.EncodeCoords(coords))
+	buf := []byte("prefix")
+	assert.Equal(t, []byte("prefix_p~iF~ps|U_ulL"), polyline.DefaultCodec().EncodeCoords(buf, coords))
+}
+
+func BenchmarkEncodeCoords(b *testing.B) {
+	coords := benchmarkCoords(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = polyline.EncodeCoords(coords)
+	}
+}
+
+func BenchmarkEncodeCoordsGeneric(b *testing.B) {
+	cs3 := withZeroDim(benchmarkCoords(1024))
+	codec := polyline.Codec{Dim: 3, Scale: 1e5}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = codec.EncodeCoords(nil, cs3)
+	}
+}
+
+func BenchmarkDecodeCoords(b *testing.B) {
+	buf := polyline.EncodeCoords(benchmarkCoords(1024))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := polyline.DecodeCoords(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeCoordsGeneric(b *testing.B) {
+	codec := polyline.Codec{Dim: 3, Scale: 1e5}
+	buf := codec.EncodeCoords(nil, withZeroDim(benchmarkCoords(1024)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := codec.DecodeCoords(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeFlatCoords(b *testing.B) {
+	buf := polyline.EncodeCoords(benchmarkCoords(1024))
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := codec.DecodeFlatCoords(nil, buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package polytest provides helpers for testing code that uses package
+// polyline.
+package polytest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// Update controls whether RequireGolden writes golden files instead of
+// comparing against them. Tests typically set it from a command line flag.
+var Update bool
+
+// A TB is the subset of testing.TB used by this package.
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+}
+
+// Coords is a slice of coordinates that implements quick.Generator using
+// polyline.Generate.
+type Coords [][]float64
+
+// Generate implements quick.Generator.
+func (Coords) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Coords(polyline.Generate(r, polyline.GenOptions{Points: size + 1})))
+}
+
+// RequireEqualWithin fails t unless want and got have the same shape and all
+// their components are within tolerance of each other.
+func RequireEqualWithin(t TB, want, got [][]float64, tolerance float64) {
+	t.Helper()
+	requireEqualWithin(t, want, got, func(int) float64 { return tolerance })
+}
+
+// requireEqualWithin fails t unless want and got have the same shape and the
+// components of each dimension j are within tolerance(j) of each other.
+func requireEqualWithin(t TB, want, got [][]float64, tolerance func(j int) float64) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("got %d coordinates, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if len(want[i]) != len(got[i]) {
+			t.Fatalf("coordinate %d: got dimension %d, want %d", i, len(got[i]), len(want[i]))
+		}
+		for j := range want[i] {
+			if d := math.Abs(want[i][j] - got[i][j]); d > tolerance(j) || math.IsNaN(d) {
+				t.Fatalf("coordinate %d: got %v, want %v within %g", i, got[i], want[i], tolerance(j))
+			}
+		}
+	}
+}
+
+// RequireRoundTrip fails t unless coords survive encoding and decoding with
+// codec to within half a quantum of each dimension's scale.
+func RequireRoundTrip(t TB, codec polyline.Codec, coords [][]float64) {
+	t.Helper()
+	buf := codec.EncodeCoords(nil, coords)
+	got, rest, err := codec.DecodeCoords(buf)
+	if err != nil {
+		t.Fatalf("decoding %q: %v", buf, err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("decoding %q: %d unconsumed bytes", buf, len(rest))
+	}
+	// Allow a little more than half a quantum for floating point error.
+	requireEqualWithin(t, coords, got, func(j int) float64 {
+		if codec.Scales != nil {
+			return 0.5000001 / codec.Scales[j]
+		}
+		return 0.5000001 / codec.Scale
+	})
+}
+
+// RequireConcurrent calls f from goroutines goroutines that start together
+// and fails t if any call returns an error. It is intended to be run with the
+// race detector enabled, go test -race, which then reports any data race
+// between the calls.
+func RequireConcurrent(t TB, goroutines int, f func(goroutine int) error) {
+	t.Helper()
+	start := make(chan struct{})
+	errs := make([]error, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = f(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("goroutine %d: %v", i, err)
+		}
+	}
+}
+
+// RequireGolden fails t unless coords match, to within tolerance, the
+// coordinates in the golden file at path. If Update is set then the golden
+// file is written instead. Golden files contain one coordinate per line with
+// components separated by commas.
+func RequireGolden(t TB, path string, coords [][]float64, tolerance float64) {
+	t.Helper()
+	if Update {
+		if err := WriteGolden(path, coords); err != nil {
+			t.Fatalf("%v", err)
+		}
+		return
+	}
+	want, err := ReadGolden(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	RequireEqualWithin(t, want, coords, tolerance)
+}
+
+// ReadGolden reads coordinates from the golden file at path.
+func ReadGolden(path string) ([][]float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var coords [][]float64
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		coord := make([]float64, len(fields))
+		for i, field := range fields {
+			coord[i], err = strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+		}
+		coords = append(coords, coord)
+	}
+	return coords, scanner.Err()
+}
+
+// WriteGolden writes coords to the golden file at path.
+func WriteGolden(path string, coords [][]float64) error {
+	var b strings.Builder
+	for _, coord := range coords {
+		for i, x := range coord {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+		}
+		b.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o666)
+}
@@ -0,0 +1,122 @@
+package polytest_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/quick"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/polytest"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a polytest.TB that records the first failure.
+type recorder struct {
+	failure string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failure = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// failure runs f in a new goroutine, as Fatalf must stop the calling
+// goroutine, and returns the failure message, if any.
+func failure(f func(polytest.TB)) string {
+	r := &recorder{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(r)
+	}()
+	<-done
+	return r.failure
+}
+
+func TestRequireEqualWithin(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		want [][]float64
+		got  [][]float64
+		fail bool
+	}{
+		{name: "empty"},
+		{name: "equal", want: [][]float64{{1, 2}}, got: [][]float64{{1, 2.05}}},
+		{name: "length", want: [][]float64{{1, 2}}, fail: true},
+		{name: "dimension", want: [][]float64{{1, 2}}, got: [][]float64{{1}}, fail: true},
+		{name: "value", want: [][]float64{{1, 2}}, got: [][]float64{{1, 2.2}}, fail: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			msg := failure(func(tb polytest.TB) {
+				polytest.RequireEqualWithin(tb, tc.want, tc.got, 0.1)
+			})
+			assert.Equal(t, tc.fail, msg != "", msg)
+		})
+	}
+}
+
+func TestRequireRoundTrip(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	f := func(coords polytest.Coords) bool {
+		return failure(func(tb polytest.TB) {
+			polytest.RequireRoundTrip(tb, codec, coords)
+		}) == ""
+	}
+	assert.NoError(t, quick.Check(f, nil))
+
+	// Each dimension is compared at its own scale.
+	scales := polyline.Codec{Dim: 2, Scales: []float64{1e5, 10}}
+	assert.Empty(t, failure(func(tb polytest.TB) {
+		polytest.RequireRoundTrip(tb, scales, [][]float64{{38.5, -120.24}})
+	}))
+	assert.NotEmpty(t, failure(func(tb polytest.TB) {
+		polytest.RequireRoundTrip(tb, scales, [][]float64{{1e300, 0}})
+	}))
+}
+
+func TestRequireConcurrent(t *testing.T) {
+	t.Parallel()
+	msg := failure(func(tb polytest.TB) {
+		polytest.RequireConcurrent(tb, 8, func(int) error {
+			return nil
+		})
+	})
+	assert.Equal(t, "", msg)
+
+	msg = failure(func(tb polytest.TB) {
+		polytest.RequireConcurrent(tb, 8, func(i int) error {
+			if i == 5 {
+				return polyline.ErrEmpty
+			}
+			return nil
+		})
+	})
+	assert.Equal(t, "goroutine 5: empty", msg)
+}
+
+//nolint:paralleltest // Update is global.
+func TestRequireGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coords.golden")
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+
+	golden := func(coords [][]float64) string {
+		return failure(func(tb polytest.TB) {
+			polytest.RequireGolden(tb, path, coords, 0)
+		})
+	}
+
+	assert.NotEmpty(t, golden(coords))
+	polytest.Update = true
+	assert.Empty(t, golden(coords))
+	polytest.Update = false
+	assert.Empty(t, golden(coords))
+	assert.NotEmpty(t, golden(coords[:2]))
+}
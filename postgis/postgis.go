@@ -0,0 +1,114 @@
+// Package postgis builds PostGIS SQL expressions from encoded polylines and
+// converts the hex EWKB that PostGIS returns for geometry columns back to
+// encoded polylines, so that routes can round trip through a database
+// without an intermediate geometry library.
+//
+// See https://postgis.net/docs/ST_AsEncodedPolyline.html and
+// https://postgis.net/docs/ST_LineFromEncodedPolyline.html.
+package postgis
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// ErrPrecision is returned when a codec cannot be expressed as a PostGIS
+// encoded polyline precision, which is a number of decimal places of a
+// two-dimensional polyline.
+var ErrPrecision = errors.New("codec precision not supported by PostGIS")
+
+// precision returns the number of decimal places of codec.
+func precision(codec polyline.Codec) (int, error) {
+	if codec.Dim != 2 {
+		return 0, fmt.Errorf("%w: %d dimensions", ErrPrecision, codec.Dim)
+	}
+	scale := codec.Scale
+	if len(codec.Scales) > 0 {
+		scale = codec.Scales[0]
+		for _, s := range codec.Scales {
+			if s != scale {
+				return 0, fmt.Errorf("%w: scales differ between dimensions", ErrPrecision)
+			}
+		}
+	}
+	p := math.Log10(scale)
+	if p != math.Trunc(p) || p < 0 {
+		return 0, fmt.Errorf("%w: scale %g is not a power of ten", ErrPrecision, scale)
+	}
+	return int(p), nil
+}
+
+// quoteLiteral returns s as an SQL string literal, using an escape string if
+// s contains backslashes, which encoded polylines often do, so that it is
+// read correctly whatever the setting of standard_conforming_strings.
+func quoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if strings.Contains(s, `\`) {
+		return `E'` + strings.ReplaceAll(s, `\`, `\\`) + `'`
+	}
+	return `'` + s + `'`
+}
+
+// GeomFromText decodes buf with codec and returns an ST_GeomFromText
+// expression for it as a WKT LineString with srid, normally 4326. If srid is
+// zero then it is omitted.
+func GeomFromText(buf []byte, codec polyline.Codec, srid int) (string, error) {
+	wkt, err := codec.DecodeWKT(buf, -1)
+	if err != nil {
+		return "", err
+	}
+	if srid == 0 {
+		return fmt.Sprintf("ST_GeomFromText(%s)", quoteLiteral(string(wkt))), nil
+	}
+	return fmt.Sprintf("ST_GeomFromText(%s, %d)", quoteLiteral(string(wkt)), srid), nil
+}
+
+// LineFromEncodedPolyline returns an ST_LineFromEncodedPolyline expression
+// that decodes buf, encoded with codec, in the database. It returns
+// ErrPrecision if PostGIS cannot decode codec's polylines.
+func LineFromEncodedPolyline(buf []byte, codec polyline.Codec) (string, error) {
+	p, err := precision(codec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ST_LineFromEncodedPolyline(%s, %d)", quoteLiteral(string(buf)), p), nil
+}
+
+// AsEncodedPolyline returns an ST_AsEncodedPolyline expression that encodes
+// the geometry expression geom, for example a column name, as codec would.
+// geom is inserted verbatim and must not come from untrusted input. It
+// returns ErrPrecision if PostGIS cannot encode codec's polylines.
+func AsEncodedPolyline(geom string, codec polyline.Codec) (string, error) {
+	p, err := precision(codec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ST_AsEncodedPolyline(%s, %d)", geom, p), nil
+}
+
+// FromHexEWKB encodes with codec the LineString in hexEWKB, the hex EWKB
+// text form in which PostGIS returns geometry values. An SRID, if present,
+// must be the EPSG code of codec's CRS, 4326 for WGS84. It returns polyline.ErrWKB if hexEWKB is not a hex EWKB
+// LineString.
+func FromHexEWKB(hexEWKB string, codec polyline.Codec) ([]byte, error) {
+	wkb, err := hex.DecodeString(hexEWKB)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", polyline.ErrWKB, err)
+	}
+	return codec.EncodeWKB(nil, wkb)
+}
+
+// ToHexEWKB decodes buf with codec and returns it as hex EWKB with srid, in
+// the upper case form PostGIS uses, for binding to geometry parameters.
+func ToHexEWKB(buf []byte, codec polyline.Codec, srid uint32) (string, error) {
+	wkb, err := codec.DecodeWKB(buf, srid)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(wkb)), nil
+}
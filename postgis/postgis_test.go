@@ -0,0 +1,73 @@
+package postgis_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/postgis"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	line = "_p~iF~ps|U_ulLnnqC"
+	// hexLine is line as PostGIS returns it for
+	// ST_SetSRID(ST_LineFromEncodedPolyline('_p~iF~ps|U_ulLnnqC'), 4326).
+	hexLine = "0102000020E610000002000000CDCCCCCCCC0C5EC00000000000404340CDCCCCCCCC3C5EC09A99999999594440"
+)
+
+func TestGeomFromText(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	sql, err := postgis.GeomFromText([]byte(line), codec, 4326)
+	assert.NoError(t, err)
+	assert.Equal(t, "ST_GeomFromText('LINESTRING(-120.2 38.5,-120.95 40.7)', 4326)", sql)
+	sql, err = postgis.GeomFromText(nil, codec, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "ST_GeomFromText('LINESTRING EMPTY')", sql)
+	_, err = postgis.GeomFromText([]byte("_"), codec, 4326)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
+
+func TestEncodedPolyline(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	sql, err := postgis.LineFromEncodedPolyline([]byte(line), codec)
+	assert.NoError(t, err)
+	assert.Equal(t, "ST_LineFromEncodedPolyline('_p~iF~ps|U_ulLnnqC', 5)", sql)
+	// Backslashes are escaped.
+	sql, err = postgis.LineFromEncodedPolyline(codec.EncodeCoords(nil, [][]float64{{-0.00015, 0}}), codec)
+	assert.NoError(t, err)
+	assert.Equal(t, `ST_LineFromEncodedPolyline(E'\\?', 5)`, sql)
+
+	sql, err = postgis.AsEncodedPolyline("route.geom", polyline.Codec6)
+	assert.NoError(t, err)
+	assert.Equal(t, "ST_AsEncodedPolyline(route.geom, 6)", sql)
+
+	for _, codec := range []polyline.Codec{
+		codec.WithDim(3),
+		codec.WithScale(3),
+		codec.WithScales(1e5, 1e6),
+	} {
+		_, err = postgis.LineFromEncodedPolyline([]byte(line), codec)
+		assert.ErrorIs(t, err, postgis.ErrPrecision)
+		_, err = postgis.AsEncodedPolyline("geom", codec)
+		assert.ErrorIs(t, err, postgis.ErrPrecision)
+	}
+}
+
+func TestHexEWKB(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	h, err := postgis.ToHexEWKB([]byte(line), codec, 4326)
+	assert.NoError(t, err)
+	assert.Equal(t, hexLine, h)
+
+	buf, err := postgis.FromHexEWKB(hexLine, codec)
+	assert.NoError(t, err)
+	assert.Equal(t, line, string(buf))
+
+	_, err = postgis.FromHexEWKB("0x", codec)
+	assert.ErrorIs(t, err, polyline.ErrWKB)
+	_, err = postgis.FromHexEWKB(hexLine[:20], codec)
+	assert.ErrorIs(t, err, polyline.ErrWKB)
+}
@@ -0,0 +1,99 @@
+package polyline
+
+import (
+	"math"
+	"math/rand"
+)
+
+// reverse returns a reversed copy of coords.
+func reverse(coords [][]float64) [][]float64 {
+	result := make([][]float64, len(coords))
+	for i, coord := range coords {
+		result[len(coords)-1-i] = coord
+	}
+	return result
+}
+
+// trimStart returns coords with the first meters of their length removed. It
+// returns nil if coords are not longer than meters.
+func trimStart(coords [][]float64, meters float64) [][]float64 {
+	var distance float64
+	for i := 1; i < len(coords); i++ {
+		d := haversine(coords[i-1], coords[i])
+		if distance+d > meters {
+			start := interpolate(coords[i-1], coords[i], (meters-distance)/d)
+			return append([][]float64{start}, coords[i:]...)
+		}
+		distance += d
+	}
+	return nil
+}
+
+// TrimEnds returns a copy of coords with the first and last meters of their
+// length removed, new endpoints being interpolated along the removed
+// segments. It is intended for publishing tracks without revealing exactly
+// where they started and ended. It returns nil if coords are not longer than
+// twice meters.
+func TrimEnds(coords [][]float64, meters float64) [][]float64 {
+	if meters <= 0 {
+		return append([][]float64(nil), coords...)
+	}
+	trimmed := trimStart(coords, meters)
+	trimmed = trimStart(reverse(trimmed), meters)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	return reverse(trimmed)
+}
+
+// trimZone returns coords with their leading points inside the circle of
+// radius meters around center removed, and replaced by the point where coords
+// leave the circle. It returns nil if all coords are inside the circle.
+func trimZone(coords [][]float64, center []float64, radius float64) [][]float64 {
+	for i, coord := range coords {
+		if haversine(coord, center) <= radius {
+			continue
+		}
+		if i == 0 {
+			return coords
+		}
+		// Bisect for the point where the segment crosses the circle.
+		lo, hi := 0.0, 1.0
+		for j := 0; j < 50; j++ {
+			mid := (lo + hi) / 2
+			if haversine(interpolate(coords[i-1], coord, mid), center) <= radius {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return append([][]float64{interpolate(coords[i-1], coord, hi)}, coords[i:]...)
+	}
+	return nil
+}
+
+// FuzzEndpoints returns a copy of coords with the portions near their start
+// and end hidden, for publishing tracks without revealing sensitive locations
+// such as homes and workplaces. Each endpoint is hidden inside a circle of
+// radius meters whose center is offset from the endpoint in a random direction
+// by a random distance, so the endpoint cannot be recovered as the center of
+// the circle. The leading and trailing points inside the circles are removed
+// and replaced by the points where the track leaves the circles. It returns
+// nil if the whole track is hidden.
+func FuzzEndpoints(coords [][]float64, radius float64, r *rand.Rand) [][]float64 {
+	if len(coords) == 0 || radius <= 0 {
+		return append([][]float64(nil), coords...)
+	}
+	zone := func(endpoint []float64) []float64 {
+		// Scale the offset by the square root of a uniform variable so that
+		// the endpoint is uniformly distributed within the circle.
+		return destination(endpoint, 360*r.Float64(), radius*math.Sqrt(r.Float64()))
+	}
+	startZone, endZone := zone(coords[0]), zone(coords[len(coords)-1])
+	fuzzed := trimZone(coords, startZone, radius)
+	fuzzed = trimZone(reverse(fuzzed), endZone, radius)
+	if len(fuzzed) == 0 {
+		return nil
+	}
+	return reverse(fuzzed)
+}
@@ -0,0 +1,108 @@
+// Package privacy coarsens sets of polylines for publication and reports how
+// well the coarsened traces hide among each other.
+//
+// Traces are snapped to a grid, so that similar routes coarsen to identical
+// geometries, and grouped by their coarsened geometry. The size of each
+// group is the number of traces that share it, the k in k-anonymity. Groups
+// smaller than a threshold can be suppressed, and the published geometry of
+// each group is jittered so that grid cell boundaries are not revealed.
+package privacy
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// DefaultGridDegrees is the size of grid cells, about 1.1km of latitude,
+// used when none is given.
+const DefaultGridDegrees = 0.01
+
+// Options configures Anonymize.
+type Options struct {
+	GridDegrees   float64    // Size of grid cells, in degrees, default DefaultGridDegrees
+	JitterDegrees float64    // Maximum jitter added to published vertices, in degrees
+	K             int        // Minimum group size; smaller groups are suppressed
+	Rand          *rand.Rand // Source of jitter, required if JitterDegrees is non-zero
+}
+
+// A Group is a set of traces that share the same coarsened geometry.
+type Group struct {
+	Coords  [][]float64 // Published geometry
+	Encoded []byte      // Encoding of Coords
+	Traces  []int       // Indexes of the traces in the group
+}
+
+// A Report is the result of Anonymize.
+type Report struct {
+	Groups     []Group // Published groups, largest first
+	Suppressed []int   // Indexes of traces in groups smaller than K
+	MinK       int     // Size of the smallest published group
+}
+
+// Coarsen snaps coords to the centers of a grid of cells of size gridDegrees
+// and removes consecutive duplicates. If gridDegrees is not positive then
+// DefaultGridDegrees is used.
+func Coarsen(coords [][]float64, gridDegrees float64) [][]float64 {
+	if !(gridDegrees > 0) {
+		gridDegrees = DefaultGridDegrees
+	}
+	snap := func(x float64) float64 {
+		return (math.Floor(x/gridDegrees) + 0.5) * gridDegrees
+	}
+	var result [][]float64
+	for _, coord := range coords {
+		snapped := []float64{snap(coord[0]), snap(coord[1])}
+		if n := len(result); n > 0 && result[n-1][0] == snapped[0] && result[n-1][1] == snapped[1] {
+			continue
+		}
+		result = append(result, snapped)
+	}
+	return result
+}
+
+// Anonymize coarsens traces, groups those that share a coarsened geometry,
+// suppresses groups with fewer than opts.K traces, and jitters the geometry
+// of the remaining groups.
+func Anonymize(traces [][][]float64, opts Options) Report {
+	groupsByKey := make(map[string]*Group)
+	var keys []string
+	for i, trace := range traces {
+		coarsened := Coarsen(trace, opts.GridDegrees)
+		key := string(polyline.EncodeCoords(coarsened))
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &Group{Coords: coarsened}
+			groupsByKey[key] = group
+			keys = append(keys, key)
+		}
+		group.Traces = append(group.Traces, i)
+	}
+
+	var report Report
+	for _, key := range keys {
+		group := groupsByKey[key]
+		if len(group.Traces) < opts.K {
+			report.Suppressed = append(report.Suppressed, group.Traces...)
+			continue
+		}
+		if opts.JitterDegrees != 0 {
+			for _, coord := range group.Coords {
+				coord[0] += opts.JitterDegrees * (2*opts.Rand.Float64() - 1)
+				coord[1] += opts.JitterDegrees * (2*opts.Rand.Float64() - 1)
+			}
+		}
+		group.Encoded = polyline.EncodeCoords(group.Coords)
+		report.Groups = append(report.Groups, *group)
+		if report.MinK == 0 || len(group.Traces) < report.MinK {
+			report.MinK = len(group.Traces)
+		}
+	}
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		return len(report.Groups[i].Traces) > len(report.Groups[j].Traces)
+	})
+	sort.Ints(report.Suppressed)
+	return report
+}
@@ -0,0 +1,61 @@
+package privacy_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/privacy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoarsen(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0.01, 0.01}, {0.02, 0.09}, {0.11, 0.12}, {-0.01, -0.01}}
+	assert.Equal(t, [][]float64{{0.05, 0.05}, {0.15000000000000002, 0.15000000000000002}, {-0.05, -0.05}}, privacy.Coarsen(coords, 0.1))
+	assert.Equal(t, privacy.Coarsen(coords, privacy.DefaultGridDegrees), privacy.Coarsen(coords, 0))
+	assert.Equal(t, privacy.Coarsen(coords, privacy.DefaultGridDegrees), privacy.Coarsen(coords, -1))
+}
+
+func TestAnonymize(t *testing.T) {
+	t.Parallel()
+	traces := [][][]float64{
+		{{0.01, 0.01}, {0.11, 0.11}},
+		{{0.02, 0.03}, {0.12, 0.15}},
+		{{0.03, 0.02}, {0.15, 0.12}},
+		{{0.51, 0.51}, {0.61, 0.61}},
+		{{0.52, 0.52}, {0.62, 0.62}},
+		{{0.91, 0.91}, {0.81, 0.81}},
+	}
+
+	report := privacy.Anonymize(traces, privacy.Options{GridDegrees: 0.1})
+	assert.Len(t, report.Groups, 3)
+	assert.Equal(t, []int{0, 1, 2}, report.Groups[0].Traces)
+	assert.Equal(t, []int{3, 4}, report.Groups[1].Traces)
+	assert.Equal(t, []int{5}, report.Groups[2].Traces)
+	assert.Equal(t, 1, report.MinK)
+	assert.Empty(t, report.Suppressed)
+
+	// The zero Options use DefaultGridDegrees.
+	report = privacy.Anonymize(traces, privacy.Options{})
+	assert.Len(t, report.Groups, 6)
+	for _, group := range report.Groups {
+		assert.Equal(t, string(polyline.EncodeCoords(privacy.Coarsen(traces[group.Traces[0]], privacy.DefaultGridDegrees))), string(group.Encoded))
+	}
+
+	report = privacy.Anonymize(traces, privacy.Options{
+		GridDegrees:   0.1,
+		JitterDegrees: 0.01,
+		K:             2,
+		Rand:          rand.New(rand.NewSource(0)),
+	})
+	assert.Len(t, report.Groups, 2)
+	assert.Equal(t, 2, report.MinK)
+	assert.Equal(t, []int{5}, report.Suppressed)
+	for _, group := range report.Groups {
+		for _, coord := range group.Coords {
+			assert.InDelta(t, coord[0], coord[1], 0.02)
+		}
+		assert.NotEmpty(t, group.Encoded)
+	}
+}
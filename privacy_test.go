@@ -0,0 +1,58 @@
+package polyline_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+// meridian returns n coordinates spaced 0.001° of latitude, approximately
+// 111m, apart along the prime meridian.
+func meridian(n int) [][]float64 {
+	coords := make([][]float64, n)
+	for i := range coords {
+		coords[i] = []float64{float64(i) / 1000, 0}
+	}
+	return coords
+}
+
+func TestTrimEnds(t *testing.T) {
+	t.Parallel()
+	coords := meridian(11)
+	got := polyline.TrimEnds(coords, 150)
+	assert.Len(t, got, 9)
+	assertCoordsWithin(t, [][]float64{{0.001349, 0}}, got[:1], 1e-6)
+	assertCoordsWithin(t, meridian(11)[2:9], got[1:8], 1e-9)
+	assertCoordsWithin(t, [][]float64{{0.008651, 0}}, got[8:], 1e-6)
+
+	got = polyline.TrimEnds(coords, 55.59754)
+	assert.Len(t, got, 11)
+	assertCoordsWithin(t, [][]float64{{0.0005, 0}}, got[:1], 1e-9)
+	assertCoordsWithin(t, [][]float64{{0.0095, 0}}, got[10:], 1e-9)
+
+	assert.Nil(t, polyline.TrimEnds(coords, 600))
+	assert.Equal(t, coords, polyline.TrimEnds(coords, 0))
+	assert.Equal(t, meridian(11), coords)
+}
+
+func TestFuzzEndpoints(t *testing.T) {
+	t.Parallel()
+	coords := meridian(101)
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 100; i++ {
+		got := polyline.FuzzEndpoints(coords, 500, r)
+		if !assert.NotEmpty(t, got) {
+			continue
+		}
+		start, end := got[0][0], got[len(got)-1][0]
+		// The hidden zones have a radius of 500m, approximately 0.0045°, and
+		// contain the endpoints.
+		assert.Greater(t, start, 0.0)
+		assert.LessOrEqual(t, start, 0.0091)
+		assert.Less(t, end, 0.1)
+		assert.GreaterOrEqual(t, end, 0.1-0.0091)
+	}
+	assert.Nil(t, polyline.FuzzEndpoints(meridian(3), 500, r))
+}
@@ -0,0 +1,33 @@
+package polyline
+
+// ProgressAlong projects fix onto route and returns the fraction of the
+// length of route before the projected point, the remaining distance along
+// route in meters, and the distance from fix to route in meters. It is the
+// core of features such as "the driver is 3.2km away". A route with fewer
+// than two points has zero length; its progress is one and the distance off
+// route is measured to its only point, if any.
+func ProgressAlong(route [][]float64, fix []float64) (fraction, remainingMeters, offRouteMeters float64) {
+	return ProgressAlongWith(route, fix, Haversine)
+}
+
+// ProgressAlongWith projects fix onto route as ProgressAlong does, with
+// distances measured with model. A nil model means Haversine.
+func ProgressAlongWith(route [][]float64, fix []float64, model DistanceModel) (fraction, remainingMeters, offRouteMeters float64) {
+	if model == nil {
+		model = Haversine
+	}
+	switch len(route) {
+	case 0:
+		return 1, 0, 0
+	case 1:
+		return 1, 0, model.Distance(route[0], fix)
+	}
+	cum := cumulativeDistancesWith(route, model)
+	total := cum[len(cum)-1]
+	p := projectOntoPath(route, fix, 0, len(route)-1, model)
+	along := cum[p.segment] + p.t*(cum[p.segment+1]-cum[p.segment])
+	if total == 0 {
+		return 1, 0, p.distance
+	}
+	return along / total, total - along, p.distance
+}
@@ -0,0 +1,47 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressAlong(t *testing.T) {
+	t.Parallel()
+	// North for 1112m, then east for 1112m.
+	route := [][]float64{{0, 0}, {0.01, 0}, {0.01, 0.01}}
+	for _, tc := range []struct {
+		name      string
+		fix       []float64
+		fraction  float64
+		remaining float64
+		off       float64
+	}{
+		{name: "start", fix: []float64{0, 0}, fraction: 0, remaining: 2223.9, off: 0},
+		{name: "quarter_off", fix: []float64{0.005, 0.001}, fraction: 0.25, remaining: 1667.9, off: 111.2},
+		{name: "corner", fix: []float64{0.011, -0.001}, fraction: 0.5, remaining: 1112.0, off: 157.3},
+		{name: "three_quarters", fix: []float64{0.01, 0.005}, fraction: 0.75, remaining: 556.0, off: 0},
+		{name: "beyond_end", fix: []float64{0.01, 0.02}, fraction: 1, remaining: 0, off: 1112.0},
+		{name: "before_start", fix: []float64{-0.01, 0}, fraction: 0, remaining: 2223.9, off: 1112.0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fraction, remaining, off := polyline.ProgressAlong(route, tc.fix)
+			assert.InDelta(t, tc.fraction, fraction, 1e-4)
+			assert.InDelta(t, tc.remaining, remaining, 0.1)
+			assert.InDelta(t, tc.off, off, 0.1)
+		})
+	}
+
+	fraction, remaining, off := polyline.ProgressAlong([][]float64{{0, 0}}, []float64{0.01, 0})
+	assert.Equal(t, 1.0, fraction)
+	assert.Zero(t, remaining)
+	assert.InDelta(t, 1112.0, off, 0.1)
+
+	fraction, remaining, off = polyline.ProgressAlongWith(route, []float64{0.005, 0.001}, doubledModel{})
+	assert.InDelta(t, 0.25, fraction, 1e-4)
+	assert.InDelta(t, 2*1667.9, remaining, 0.2)
+	assert.InDelta(t, 2*111.2, off, 0.2)
+}
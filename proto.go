@@ -0,0 +1,107 @@
+package polyline
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// errProto is returned by readProto for malformed protocol buffers. Callers
+// wrap it in their own format's error.
+var errProto = errors.New("malformed protocol buffer")
+
+// Protocol buffer wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// appendUvarint appends the varint encoding of u to dst.
+func appendUvarint(dst []byte, u uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(dst, b[:binary.PutUvarint(b[:], u)]...)
+}
+
+// appendProtoTag appends the tag of field num with wire type typ to dst.
+func appendProtoTag(dst []byte, num, typ int) []byte {
+	return appendUvarint(dst, uint64(num<<3|typ))
+}
+
+// appendProtoBytes appends field num containing b to dst.
+func appendProtoBytes(dst []byte, num int, b []byte) []byte {
+	dst = appendProtoTag(dst, num, wireBytes)
+	dst = appendUvarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
+
+// appendProtoVarint appends field num containing u to dst.
+func appendProtoVarint(dst []byte, num int, u uint64) []byte {
+	dst = appendProtoTag(dst, num, wireVarint)
+	return appendUvarint(dst, u)
+}
+
+// appendProtoFixed64 appends field num containing u to dst.
+func appendProtoFixed64(dst []byte, num int, u uint64) []byte {
+	dst = appendProtoTag(dst, num, wireFixed64)
+	return appendUint64(dst, u)
+}
+
+// readProto calls f for each field of the protocol buffer message buf with
+// the field's number and wire type. Varint and fixed-width fields are passed
+// in u and length-delimited fields in b. It stops at the first error
+// returned by f.
+func readProto(buf []byte, f func(num, typ int, u uint64, b []byte) error) error {
+	for len(buf) > 0 {
+		tag, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return errProto
+		}
+		buf = buf[n:]
+		num, typ := int(tag>>3), int(tag&7)
+		var u uint64
+		var b []byte
+		switch typ {
+		case wireVarint:
+			if u, n = binary.Uvarint(buf); n <= 0 {
+				return errProto
+			}
+			buf = buf[n:]
+		case wireFixed64:
+			if len(buf) < 8 {
+				return errProto
+			}
+			u, buf = binary.LittleEndian.Uint64(buf), buf[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(buf)
+			if n <= 0 || length > uint64(len(buf)-n) {
+				return errProto
+			}
+			b, buf = buf[n:n+int(length)], buf[n+int(length):]
+		case wireFixed32:
+			if len(buf) < 4 {
+				return errProto
+			}
+			u, buf = uint64(binary.LittleEndian.Uint32(buf)), buf[4:]
+		default:
+			return errProto
+		}
+		if err := f(num, typ, u, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPackedVarints calls f for each varint of the packed repeated field b.
+func readPackedVarints(b []byte, f func(u uint64)) error {
+	for len(b) > 0 {
+		u, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errProto
+		}
+		f(u)
+		b = b[n:]
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package polyline
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrProtoMessage is returned by Codec.FromProto when the input is not a
+// Polyline message.
+var ErrProtoMessage = errors.New("not a Polyline protocol buffer message")
+
+// Fields of the Polyline message defined in polyline.proto.
+const (
+	protoPolylineDeltas = 1
+	protoPolylineScale  = 2
+	protoPolylineDim    = 3
+	protoPolylineScales = 4
+)
+
+// ToProto decodes buf and returns it as a Polyline message, defined in
+// polyline.proto, with the codec's dimensionality and scales. Unlike the
+// string encoding, the message can be embedded in other messages as a bytes
+// field and carries its own precision.
+func (c Codec) ToProto(buf []byte) ([]byte, error) {
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var packed []byte
+	last := make([]int, c.Dim)
+	for _, coord := range coords {
+		for i, x := range coord {
+			ex := c.quantize(i, x)
+			packed = appendUvarint(packed, zigzag64(int64(ex-last[i])))
+			last[i] = ex
+		}
+	}
+
+	// An empty deltas field is omitted, as proto3 encoders do.
+	var msg []byte
+	if len(packed) > 0 {
+		msg = appendProtoBytes(msg, protoPolylineDeltas, packed)
+	}
+	msg = appendProtoFixed64(msg, protoPolylineScale, math.Float64bits(c.Scale))
+	msg = appendProtoVarint(msg, protoPolylineDim, uint64(c.Dim))
+	if len(c.Scales) > 0 {
+		scales := make([]byte, 0, 8*len(c.Scales))
+		for _, s := range c.Scales {
+			scales = appendUint64(scales, math.Float64bits(s))
+		}
+		msg = appendProtoBytes(msg, protoPolylineScales, scales)
+	}
+	return msg, nil
+}
+
+// FromProto encodes a Polyline message, defined in polyline.proto.
+// Coordinates are rescaled from the message's scales to the codec's. It
+// returns ErrProtoMessage if msg is malformed or has no valid scale, and
+// ErrDimensionalMismatch if its dimensionality is not the codec's.
+func (c Codec) FromProto(msg []byte) ([]byte, error) {
+	var deltas []int64
+	var scale float64
+	var dim uint64
+	var scales []float64
+	err := readProto(msg, func(num, typ int, u uint64, b []byte) error {
+		switch {
+		case num == protoPolylineDeltas && typ == wireBytes:
+			return readPackedVarints(b, func(u uint64) {
+				deltas = append(deltas, unzigzag64(u))
+			})
+		case num == protoPolylineDeltas && typ == wireVarint:
+			deltas = append(deltas, unzigzag64(u))
+		case num == protoPolylineScale && typ == wireFixed64:
+			scale = math.Float64frombits(u)
+		case num == protoPolylineDim && typ == wireVarint:
+			dim = u
+		case num == protoPolylineScales && typ == wireBytes:
+			if len(b)%8 != 0 {
+				return errProto
+			}
+			for ; len(b) > 0; b = b[8:] {
+				scales = append(scales, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+			}
+		case num == protoPolylineScales && typ == wireFixed64:
+			scales = append(scales, math.Float64frombits(u))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProtoMessage, err)
+	}
+	if dim != uint64(c.Dim) {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrDimensionalMismatch, dim)
+	}
+	if len(scales) == 0 {
+		scales = make([]float64, dim)
+		for i := range scales {
+			scales[i] = scale
+		}
+	} else if uint64(len(scales)) != dim {
+		return nil, fmt.Errorf("%w: %d scales for %d dimensions", ErrProtoMessage, len(scales), dim)
+	}
+	for _, s := range scales {
+		if !(s > 0) || math.IsInf(s, 0) {
+			return nil, fmt.Errorf("%w: scale %g", ErrProtoMessage, s)
+		}
+	}
+	if len(deltas)%c.Dim != 0 {
+		return nil, fmt.Errorf("%w: %d values", ErrDimensionalMismatch, len(deltas))
+	}
+
+	coords := make([][]float64, len(deltas)/c.Dim)
+	last := make([]int64, c.Dim)
+	for i := range coords {
+		coords[i] = make([]float64, c.Dim)
+		for j := range coords[i] {
+			last[j] += deltas[i*c.Dim+j]
+			coords[i][j] = float64(last[j]) / scales[j]
+		}
+	}
+	return c.untransformed().EncodeCoords(nil, coords), nil
+}
@@ -0,0 +1,68 @@
+package polyline_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToProto(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	msg, err := codec.ToProto([]byte("_p~iF~ps|U_ulLnnqC"))
+	assert.NoError(t, err)
+	// deltas [3850000, -12020000, 220000, -75000], scale 1e5, dim 2.
+	assert.Equal(t, "0a0ea0fcd503bfa4bb0bc0ed1aef9309"+"1100000000006af840"+"1802", hex.EncodeToString(msg))
+
+	msg, err = codec.ToProto(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1100000000006af840"+"1802", hex.EncodeToString(msg))
+
+	_, err = codec.ToProto([]byte("_"))
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
+
+func TestFromProto(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	codec3 := codec.WithScales(1e5, 1e5, 1e2).WithDim(3)
+	coords3 := [][]float64{{1, 2, 30.25}, {1.5, 2.5, 40}}
+	msg3, err := codec3.ToProto(codec3.EncodeCoords(nil, coords3))
+	assert.NoError(t, err)
+	for _, tc := range []struct {
+		name     string
+		codec    polyline.Codec
+		hex      string
+		expected string
+		err      error
+	}{
+		{name: "packed", codec: codec, hex: "0a0ea0fcd503bfa4bb0bc0ed1aef9309" + "1100000000006af840" + "1802", expected: "_p~iF~ps|U_ulLnnqC"},
+		{name: "unpacked", codec: codec, hex: "08a0fcd503" + "08bfa4bb0b" + "08c0ed1a" + "08ef9309" + "1100000000006af840" + "1802", expected: "_p~iF~ps|U_ulLnnqC"},
+		{name: "rescaled", codec: codec, hex: "0a09943ce7bb01b8039501" + "110000000000005940" + "1802", expected: "_p~iF~ps|U_ulLnnqC"},
+		{name: "scales", codec: codec, hex: "0a09943ce7bb01b8039501" + "1802" + "221000000000000059400000000000005940", expected: "_p~iF~ps|U_ulLnnqC"},
+		{name: "3d", codec: codec3, hex: hex.EncodeToString(msg3), expected: string(codec3.EncodeCoords(nil, coords3))},
+		{name: "empty", codec: codec, hex: "1100000000006af840" + "1802"},
+		{name: "no_scale", codec: codec, hex: "1802", err: polyline.ErrProtoMessage},
+		{name: "negative_scale", codec: codec, hex: "1100000000006af8c0" + "1802", err: polyline.ErrProtoMessage},
+		{name: "too_few_scales", codec: codec, hex: "1802" + "22080000000000005940", err: polyline.ErrProtoMessage},
+		{name: "truncated", codec: codec, hex: "0a0ea0fcd503bfa4bb0bc0ed1aef93", err: polyline.ErrProtoMessage},
+		{name: "dimensions", codec: codec3, hex: "1100000000006af840" + "1802", err: polyline.ErrDimensionalMismatch},
+		{name: "odd_values", codec: codec, hex: "0a0102" + "1100000000006af840" + "1802", err: polyline.ErrDimensionalMismatch},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			msg, err := hex.DecodeString(tc.hex)
+			assert.NoError(t, err)
+			buf, err := tc.codec.FromProto(msg)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(buf))
+		})
+	}
+}
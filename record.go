@@ -0,0 +1,145 @@
+package polyline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRecord is returned when parsing a malformed Record.
+var ErrInvalidRecord = errors.New("invalid record")
+
+// A Record is a polyline annotated with metadata, such as its precision,
+// SRID, or vehicle ID, so that both can travel together through systems that
+// only pass strings. Its text form is
+//
+//	k1=v1;k2=v2;|polyline
+//
+// with keys in sorted order. The bytes %, =, ; and | in keys and values are
+// percent-encoded.
+type Record struct {
+	Meta     map[string]string
+	Polyline []byte
+}
+
+// recordEscapes is the set of bytes percent-encoded in keys and values.
+const recordEscapes = "%=;|"
+
+// escapeRecord appends s to buf with the bytes in recordEscapes
+// percent-encoded.
+func escapeRecord(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; strings.IndexByte(recordEscapes, c) >= 0 {
+			buf = append(buf, fmt.Sprintf("%%%02X", c)...)
+		} else {
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
+
+// unescapeRecord returns s with percent-encoded bytes decoded.
+func unescapeRecord(s []byte) (string, error) {
+	buf := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			buf = append(buf, s[i])
+			continue
+		}
+		if i+2 >= len(s) {
+			return "", fmt.Errorf("%w: truncated escape", ErrInvalidRecord)
+		}
+		b, err := strconv.ParseUint(string(s[i+1:i+3]), 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid escape %q", ErrInvalidRecord, s[i:i+3])
+		}
+		buf = append(buf, byte(b))
+		i += 2
+	}
+	return string(buf), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Record) MarshalText() ([]byte, error) {
+	keys := make([]string, 0, len(r.Meta))
+	for k := range r.Meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf []byte
+	for _, k := range keys {
+		buf = escapeRecord(buf, k)
+		buf = append(buf, '=')
+		buf = escapeRecord(buf, r.Meta[k])
+		buf = append(buf, ';')
+	}
+	buf = append(buf, '|')
+	return append(buf, r.Polyline...), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Record) UnmarshalText(text []byte) error {
+	i := bytes.IndexByte(text, '|')
+	if i < 0 {
+		return fmt.Errorf("%w: missing |", ErrInvalidRecord)
+	}
+	header := text[:i]
+	meta := make(map[string]string)
+	for len(header) > 0 {
+		end := bytes.IndexByte(header, ';')
+		if end < 0 {
+			return fmt.Errorf("%w: unterminated entry %q", ErrInvalidRecord, header)
+		}
+		entry := header[:end]
+		header = header[end+1:]
+		eq := bytes.IndexByte(entry, '=')
+		if eq < 0 {
+			return fmt.Errorf("%w: entry %q has no =", ErrInvalidRecord, entry)
+		}
+		k, err := unescapeRecord(entry[:eq])
+		if err != nil {
+			return err
+		}
+		v, err := unescapeRecord(entry[eq+1:])
+		if err != nil {
+			return err
+		}
+		meta[k] = v
+	}
+	r.Meta = meta
+	r.Polyline = append([]byte(nil), text[i+1:]...)
+	return nil
+}
+
+// Codec returns base with its Scale, Dim, and CRS replaced by those given by
+// the "precision", in decimal digits, "dim", and "srid" keys of r's metadata,
+// if present. A precision applies to every dimension, replacing any Scales.
+// An SRID sets the CRS to the corresponding EPSG code.
+func (r Record) Codec(base Codec) (Codec, error) {
+	if s, ok := r.Meta["precision"]; ok {
+		precision, err := strconv.Atoi(s)
+		if err != nil || precision < 0 || precision > 15 {
+			return Codec{}, fmt.Errorf("%w: precision %q", ErrInvalidRecord, s)
+		}
+		base.Scale = math.Pow10(precision)
+		base.Scales = nil
+	}
+	if s, ok := r.Meta["dim"]; ok {
+		dim, err := strconv.Atoi(s)
+		if err != nil || dim < 1 {
+			return Codec{}, fmt.Errorf("%w: dim %q", ErrInvalidRecord, s)
+		}
+		base.Dim = dim
+	}
+	if s, ok := r.Meta["srid"]; ok {
+		if _, err := strconv.Atoi(s); err != nil {
+			return Codec{}, fmt.Errorf("%w: srid %q", ErrInvalidRecord, s)
+		}
+		base.CRS = "EPSG:" + s
+	}
+	return base, nil
+}
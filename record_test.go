@@ -0,0 +1,114 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		record polyline.Record
+		text   string
+	}{
+		{
+			name:   "empty",
+			record: polyline.Record{Meta: map[string]string{}},
+			text:   "|",
+		},
+		{
+			name: "meta",
+			record: polyline.Record{
+				Meta:     map[string]string{"vehicle": "v42", "precision": "6", "srid": "4326"},
+				Polyline: []byte("_p~iF~ps|U"),
+			},
+			text: "precision=6;srid=4326;vehicle=v42;|_p~iF~ps|U",
+		},
+		{
+			name: "escapes",
+			record: polyline.Record{
+				Meta:     map[string]string{"a=b": "c;d|e%f"},
+				Polyline: []byte("??"),
+			},
+			text: "a%3Db=c%3Bd%7Ce%25f;|??",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			text, err := tc.record.MarshalText()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.text, string(text))
+			var got polyline.Record
+			assert.NoError(t, got.UnmarshalText(text))
+			assert.Equal(t, tc.record, got)
+		})
+	}
+}
+
+func TestRecordErrors(t *testing.T) {
+	t.Parallel()
+	for _, text := range []string{
+		"",
+		"a=b",
+		"a=b|??",
+		"ab;|??",
+		"a=%4;|??",
+		"a=%zz;|??",
+	} {
+		var r polyline.Record
+		assert.ErrorIs(t, r.UnmarshalText([]byte(text)), polyline.ErrInvalidRecord, text)
+	}
+}
+
+func TestRecordCodec(t *testing.T) {
+	t.Parallel()
+	base := polyline.DefaultCodec()
+	for _, tc := range []struct {
+		name     string
+		meta     map[string]string
+		expected polyline.Codec
+		err      error
+	}{
+		{
+			name:     "none",
+			expected: base,
+		},
+		{
+			name:     "precision",
+			meta:     map[string]string{"precision": "6", "dim": "3", "srid": "27700"},
+			expected: polyline.Codec{Dim: 3, Scale: 1e6, CRS: "EPSG:27700"},
+		},
+		{
+			name: "invalid_precision",
+			meta: map[string]string{"precision": "x"},
+			err:  polyline.ErrInvalidRecord,
+		},
+		{
+			name: "invalid_srid",
+			meta: map[string]string{"srid": "WGS84"},
+			err:  polyline.ErrInvalidRecord,
+		},
+		{
+			name: "invalid_dim",
+			meta: map[string]string{"dim": "0"},
+			err:  polyline.ErrInvalidRecord,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			codec, err := polyline.Record{Meta: tc.meta}.Codec(base)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, codec)
+		})
+	}
+
+	// A precision replaces per-dimension scales.
+	codec, err := polyline.Record{Meta: map[string]string{"precision": "6"}}.Codec(polyline.Codec{Dim: 2, Scales: []float64{1e5, 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.Codec{Dim: 2, Scale: 1e6}, codec)
+}
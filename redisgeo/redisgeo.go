@@ -0,0 +1,231 @@
+// Package redisgeo converts between encoded polylines and the members of
+// Redis geo sets, for live-tracking stores that keep the vertices of each
+// route in a sorted set.
+//
+// Each vertex becomes a member named by a caller-chosen prefix followed by
+// its index, so that several routes can share a key and each route's
+// vertices can be put back in order. Prefixes should end with a delimiter,
+// such as "route42:", so that no route's prefix followed by digits is
+// another route's prefix. Scores are the 52-bit geohashes that Redis
+// computes for GEOADD, so members can equally be written with ZADD. Redis
+// geo sets hold WGS84 latitudes and longitudes, so codecs must have neither
+// a CRS nor a Transformer. See https://redis.io/commands/geoadd/.
+package redisgeo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+var (
+	// ErrOutOfRange is returned when a coordinate cannot be stored in a
+	// Redis geo set, which only holds latitudes between -85.05112878 and
+	// 85.05112878, or when a score is not a 52-bit geohash.
+	ErrOutOfRange = errors.New("coordinate out of Redis geo range")
+	// ErrMemberName is returned by Decode when a member's index is too large
+	// or two members have the same index.
+	ErrMemberName = errors.New("invalid member name")
+	// ErrCodec is returned when a codec has a CRS or Transformer, since Redis
+	// geo sets hold only WGS84 coordinates.
+	ErrCodec = errors.New("codec is not WGS84")
+)
+
+// Limits of Redis geo sets, from geohash.h.
+const (
+	latMin = -85.05112878
+	latMax = 85.05112878
+	lngMin = -180
+	lngMax = 180
+	step   = 26
+)
+
+// A Member is a member of a Redis sorted set with its score, as passed to
+// ZADD and returned by ZRANGE WITHSCORES.
+type Member struct {
+	Name  string
+	Score float64
+}
+
+// Score returns the score that Redis GEOADD gives to the coordinate lat,
+// lng: the interleaved bits of the two values quantized to 26 bits each.
+// It returns ErrOutOfRange if lat or lng is outside the range of Redis geo
+// sets.
+func Score(lat, lng float64) (float64, error) {
+	if !(latMin <= lat && lat <= latMax) || !(lngMin <= lng && lng <= lngMax) {
+		return 0, fmt.Errorf("%w: %g, %g", ErrOutOfRange, lat, lng)
+	}
+	ilat := uint64((lat - latMin) / (latMax - latMin) * (1 << step))
+	ilng := uint64((lng - lngMin) / (lngMax - lngMin) * (1 << step))
+	// A value at the maximum of its range falls in the last cell.
+	if ilat == 1<<step {
+		ilat--
+	}
+	if ilng == 1<<step {
+		ilng--
+	}
+	return float64(interleave(ilat) | interleave(ilng)<<1), nil
+}
+
+// Coord returns the latitude and longitude of the center of the geohash cell
+// score, as GEOPOS does. It returns ErrOutOfRange if score is not a 52-bit
+// geohash.
+func Coord(score float64) ([]float64, error) {
+	if !(0 <= score && score < 1<<(2*step)) || score != math.Trunc(score) {
+		return nil, fmt.Errorf("%w: score %g", ErrOutOfRange, score)
+	}
+	hash := uint64(score)
+	ilat, ilng := deinterleave(hash), deinterleave(hash>>1)
+	return []float64{
+		latMin + (float64(ilat)+0.5)/(1<<step)*(latMax-latMin),
+		lngMin + (float64(ilng)+0.5)/(1<<step)*(lngMax-lngMin),
+	}, nil
+}
+
+// interleave spreads the low 32 bits of x to the even bits of the result.
+func interleave(x uint64) uint64 {
+	x &= 0xFFFFFFFF
+	x = (x | x<<16) & 0x0000FFFF0000FFFF
+	x = (x | x<<8) & 0x00FF00FF00FF00FF
+	x = (x | x<<4) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+// deinterleave gathers the even bits of x into the low 32 bits of the
+// result.
+func deinterleave(x uint64) uint64 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x>>4) & 0x00FF00FF00FF00FF
+	x = (x | x>>8) & 0x0000FFFF0000FFFF
+	x = (x | x>>16) & 0x00000000FFFFFFFF
+	return x
+}
+
+// checkCodec returns ErrCodec if codec has a CRS or Transformer.
+func checkCodec(codec polyline.Codec) error {
+	if codec.CRS != "" || codec.Transformer != nil {
+		return fmt.Errorf("%w: CRS %q", ErrCodec, codec.CRS)
+	}
+	return nil
+}
+
+// memberName returns the name of the vertex at index of the route prefix.
+// Indexes are zero padded so that members sort by name in route order.
+func memberName(prefix string, index int) string {
+	return fmt.Sprintf("%s%08d", prefix, index)
+}
+
+// Members decodes buf with codec, which must be at least two-dimensional,
+// and returns a member for each vertex named by prefix and the vertex's
+// index, for ZADD. Dimensions beyond latitude and longitude are dropped. It
+// returns ErrOutOfRange if a vertex cannot be stored in a geo set.
+func Members(buf []byte, codec polyline.Codec, prefix string) ([]Member, error) {
+	if err := checkCodec(codec); err != nil {
+		return nil, err
+	}
+	coords, _, err := codec.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]Member, len(coords))
+	for i, coord := range coords {
+		score, err := Score(coord[0], coord[1])
+		if err != nil {
+			return nil, fmt.Errorf("vertex %d: %w", i, err)
+		}
+		members[i] = Member{Name: memberName(prefix, i), Score: score}
+	}
+	return members, nil
+}
+
+// GeoAddArgs decodes buf with codec, which must be at least
+// two-dimensional, and returns the arguments of a GEOADD command adding its
+// vertices to key, after the command name: key followed by the longitude,
+// latitude, and member name of each vertex. Members are named as by Members.
+// For example, with go-redis:
+//
+//	args, err := redisgeo.GeoAddArgs("routes", buf, codec, "route42:")
+//	...
+//	err = rdb.Do(ctx, append([]interface{}{"GEOADD"}, args...)...).Err()
+//
+// It returns ErrOutOfRange if a vertex cannot be stored in a geo set.
+func GeoAddArgs(key string, buf []byte, codec polyline.Codec, prefix string) ([]interface{}, error) {
+	if err := checkCodec(codec); err != nil {
+		return nil, err
+	}
+	coords, _, err := codec.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]interface{}, 0, 1+3*len(coords))
+	args = append(args, key)
+	for i, coord := range coords {
+		if _, err := Score(coord[0], coord[1]); err != nil {
+			return nil, fmt.Errorf("vertex %d: %w", i, err)
+		}
+		args = append(args, coord[1], coord[0], memberName(prefix, i))
+	}
+	return args, nil
+}
+
+// Decode returns the encoding with codec, which must be two-dimensional, of
+// the route prefix from members, in any order, for example the result of
+// ZRANGE WITHSCORES on a key shared by several routes. Members whose names
+// are not prefix followed by a vertex index, which is only digits, are
+// ignored. Vertices are placed at the centers of their geohash cells, within
+// about 0.6m of the original coordinates. It returns ErrMemberName if an
+// index is too large or two members have the same index.
+func Decode(members []Member, codec polyline.Codec, prefix string) ([]byte, error) {
+	if err := checkCodec(codec); err != nil {
+		return nil, err
+	}
+	type vertex struct {
+		index int
+		coord []float64
+	}
+	var vertices []vertex
+	for _, m := range members {
+		if !strings.HasPrefix(m.Name, prefix) || !isDigits(m.Name[len(prefix):]) {
+			continue
+		}
+		index, err := strconv.Atoi(m.Name[len(prefix):])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrMemberName, m.Name)
+		}
+		coord, err := Coord(m.Score)
+		if err != nil {
+			return nil, fmt.Errorf("member %q: %w", m.Name, err)
+		}
+		vertices = append(vertices, vertex{index: index, coord: coord})
+	}
+	sort.Slice(vertices, func(i, j int) bool {
+		return vertices[i].index < vertices[j].index
+	})
+	coords := make([][]float64, len(vertices))
+	for i, v := range vertices {
+		if i > 0 && v.index == vertices[i-1].index {
+			return nil, fmt.Errorf("%w: duplicate index %d", ErrMemberName, v.index)
+		}
+		coords[i] = v.coord
+	}
+	return codec.EncodeCoords(nil, coords), nil
+}
+
+// isDigits returns whether s is one or more ASCII digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
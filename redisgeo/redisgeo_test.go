@@ -0,0 +1,117 @@
+package redisgeo_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/redisgeo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+	// Scores from the GEOADD documentation.
+	for _, tc := range []struct {
+		name     string
+		lat, lng float64
+		expected float64
+	}{
+		{name: "palermo", lat: 38.115556, lng: 13.361389, expected: 3479099956230698},
+		{name: "catania", lat: 37.502669, lng: 15.087269, expected: 3479447370796909},
+		{name: "min", lat: -85.05112878, lng: -180, expected: 0},
+		{name: "max", lat: 85.05112878, lng: 180, expected: 1<<52 - 1},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			score, err := redisgeo.Score(tc.lat, tc.lng)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, score)
+			coord, err := redisgeo.Coord(score)
+			assert.NoError(t, err)
+			assert.InDeltaSlice(t, []float64{tc.lat, tc.lng}, coord, 5e-6)
+		})
+	}
+
+	_, err := redisgeo.Score(86, 0)
+	assert.ErrorIs(t, err, redisgeo.ErrOutOfRange)
+	_, err = redisgeo.Score(0, 181)
+	assert.ErrorIs(t, err, redisgeo.ErrOutOfRange)
+	for _, score := range []float64{-1, 1 << 52, 0.5} {
+		_, err = redisgeo.Coord(score)
+		assert.ErrorIs(t, err, redisgeo.ErrOutOfRange)
+	}
+}
+
+func TestMembers(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	members, err := redisgeo.Members(buf, codec, "r1:")
+	assert.NoError(t, err)
+	assert.Len(t, members, 3)
+	assert.Equal(t, "r1:00000000", members[0].Name)
+	assert.Equal(t, "r1:00000002", members[2].Name)
+
+	args, err := redisgeo.GeoAddArgs("routes", buf, codec, "r1:")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"routes", -120.2, 38.5, "r1:00000000", -120.95, 40.7, "r1:00000001", -126.453, 43.252, "r1:00000002"}, args)
+
+	// Members of another route and in any order.
+	other, err := redisgeo.Members(codec.EncodeCoords(nil, [][]float64{{1, 2}}), codec, "r2:")
+	assert.NoError(t, err)
+	shuffled := []redisgeo.Member{members[2], other[0], members[0], members[1]}
+	got, err := redisgeo.Decode(shuffled, codec, "r1:")
+	assert.NoError(t, err)
+	assert.Equal(t, string(buf), string(got))
+
+	_, err = redisgeo.Members(codec.EncodeCoords(nil, [][]float64{{89, 0}}), codec, "")
+	assert.ErrorIs(t, err, redisgeo.ErrOutOfRange)
+	_, err = redisgeo.GeoAddArgs("routes", codec.EncodeCoords(nil, [][]float64{{89, 0}}), codec, "")
+	assert.ErrorIs(t, err, redisgeo.ErrOutOfRange)
+	_, err = redisgeo.Members([]byte("_"), codec, "")
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	for _, tc := range []struct {
+		name    string
+		members []redisgeo.Member
+		err     error
+	}{
+		{name: "index", members: []redisgeo.Member{{Name: "r1:99999999999999999999"}}, err: redisgeo.ErrMemberName},
+		{name: "duplicate", members: []redisgeo.Member{{Name: "r1:1"}, {Name: "r1:01"}}, err: redisgeo.ErrMemberName},
+		{name: "score", members: []redisgeo.Member{{Name: "r1:1", Score: -1}}, err: redisgeo.ErrOutOfRange},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := redisgeo.Decode(tc.members, codec, "r1:")
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+	buf, err := redisgeo.Decode(nil, codec, "r1:")
+	assert.NoError(t, err)
+	assert.Empty(t, buf)
+
+	// Members of other routes sharing the prefix, and names without an
+	// index, are ignored.
+	buf, err = redisgeo.Decode([]redisgeo.Member{
+		{Name: "route4"},
+		{Name: "route4x"},
+		{Name: "route4-1"},
+		{Name: "route42:00000001"},
+	}, codec, "route4")
+	assert.NoError(t, err)
+	assert.Empty(t, buf)
+
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	_, err = redisgeo.Decode(nil, mercator, "r1:")
+	assert.ErrorIs(t, err, redisgeo.ErrCodec)
+	_, err = redisgeo.Members([]byte("??"), mercator, "r1:")
+	assert.ErrorIs(t, err, redisgeo.ErrCodec)
+	_, err = redisgeo.GeoAddArgs("routes", []byte("??"), mercator, "r1:")
+	assert.ErrorIs(t, err, redisgeo.ErrCodec)
+}
@@ -0,0 +1,83 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+// jsConformanceVectors are derived from the semantics of JavaScript's
+// Math.round, which rounds halves towards positive infinity, rather than
+// taken from any encoder: js is the encoding of the coordinates scaled and
+// rounded that way, and halfAway of them rounded halves away from zero.
+var jsConformanceVectors = []struct {
+	name     string
+	coords   [][]float64
+	js       string
+	halfAway string
+}{
+	{
+		name:     "google",
+		coords:   [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}},
+		js:       "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+		halfAway: "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+	},
+	{
+		name:     "negative_zero",
+		coords:   [][]float64{{negativeZero(), negativeZero()}},
+		js:       "??",
+		halfAway: "??",
+	},
+	{
+		name:     "sub_half_quantum",
+		coords:   [][]float64{{-0.000004, 0.000004}},
+		js:       "??",
+		halfAway: "??",
+	},
+	{
+		name:     "half_quanta",
+		coords:   [][]float64{{-0.000025, 0.000025}},
+		js:       "BE",
+		halfAway: "DE",
+	},
+	{
+		name:     "negative_half_quanta",
+		coords:   [][]float64{{0, 0}, {-0.000015, -0.000005}},
+		js:       "??@?",
+		halfAway: "??B@",
+	},
+	{
+		name:     "large_half_quanta",
+		coords:   [][]float64{{-38.500005, -120.200005}},
+		js:       "~o~iF~ps|U",
+		halfAway: "`p~iF`qs|U",
+	},
+}
+
+func negativeZero() float64 {
+	zero := 0.0
+	return -zero
+}
+
+func TestRoundJS(t *testing.T) {
+	t.Parallel()
+	jsCodec := polyline.Codec{Dim: 2, Scale: 1e5, Rounding: polyline.RoundJS}
+	defaultCodec := polyline.Codec{Dim: 2, Scale: 1e5}
+	for _, tc := range jsConformanceVectors {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.js, string(jsCodec.EncodeCoords(nil, tc.coords)))
+			assert.Equal(t, tc.halfAway, string(defaultCodec.EncodeCoords(nil, tc.coords)))
+
+			flat := make([]float64, 0, 2*len(tc.coords))
+			for _, coord := range tc.coords {
+				flat = append(flat, coord...)
+			}
+			got, err := jsCodec.EncodeFlatCoords(nil, flat)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.js, string(got))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package polyline
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// A Scanner reads encoded polylines separated by a delimiter, such as a
+// comma, newline, or NUL, from a stream, as found in bulk export files that
+// pack many polylines per line. Each polyline is validated before it is
+// returned. Empty polylines are skipped. When the delimiter is a newline, a
+// trailing carriage return is removed from each polyline.
+type Scanner struct {
+	s     *bufio.Scanner
+	delim byte
+	frame []byte
+	n     int
+	err   error
+}
+
+// NewScanner returns a new Scanner that reads from r. It panics if delim is
+// a valid polyline byte.
+func NewScanner(r io.Reader, delim byte) *Scanner {
+	if IsValidPolylineByte(delim) {
+		panic(fmt.Sprintf("polyline: delimiter %q is a valid polyline byte", delim))
+	}
+	s := &Scanner{
+		s:     bufio.NewScanner(r),
+		delim: delim,
+	}
+	s.s.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, delim); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+	return s
+}
+
+// Buffer sets the initial buffer and the maximum size of a polyline, as
+// bufio.Scanner.Buffer does. By default the maximum size is
+// bufio.MaxScanTokenSize.
+func (s *Scanner) Buffer(buf []byte, max int) {
+	s.s.Buffer(buf, max)
+}
+
+// Scan advances s to the next polyline, which is then available from Bytes.
+// It returns false when the input is exhausted or an invalid polyline is
+// found, after which Err returns any error.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	for s.s.Scan() {
+		s.n++
+		frame := s.s.Bytes()
+		if s.delim == '\n' {
+			frame = bytes.TrimSuffix(frame, []byte{'\r'})
+		}
+		if len(frame) == 0 {
+			continue
+		}
+		if err := validFrame(frame); err != nil {
+			s.err = fmt.Errorf("polyline %d: %w", s.n, err)
+			return false
+		}
+		s.frame = frame
+		return true
+	}
+	s.err = s.s.Err()
+	return false
+}
+
+// Bytes returns the polyline found by the last call to Scan. The underlying
+// array may be overwritten by the next call to Scan.
+func (s *Scanner) Bytes() []byte {
+	return s.frame
+}
+
+// Err returns the first error encountered by s.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// validFrame returns an error if frame contains invalid bytes or ends in the
+// middle of a value.
+func validFrame(frame []byte) error {
+	for i, b := range frame {
+		if !IsValidPolylineByte(b) {
+			return fmt.Errorf("%w at offset %d", ErrInvalidByte, i)
+		}
+	}
+	if !IsTerminalPolylineByte(frame[len(frame)-1]) {
+		return ErrUnterminatedSequence
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package polyline_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanner(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		s        string
+		delim    byte
+		expected []string
+		err      error
+	}{
+		{
+			name:  "empty",
+			delim: '\n',
+		},
+		{
+			name:     "newline",
+			s:        "_p~iF~ps|U\r\n_ulLnnqC\n\n_mqNvxq`@",
+			delim:    '\n',
+			expected: []string{"_p~iF~ps|U", "_ulLnnqC", "_mqNvxq`@"},
+		},
+		{
+			name:     "comma",
+			s:        "_p~iF~ps|U,_ulLnnqC,",
+			delim:    ',',
+			expected: []string{"_p~iF~ps|U", "_ulLnnqC"},
+		},
+		{
+			name:     "nul",
+			s:        "_p~iF~ps|U\x00_ulLnnqC",
+			delim:    0,
+			expected: []string{"_p~iF~ps|U", "_ulLnnqC"},
+		},
+		{
+			name:     "invalid_byte",
+			s:        "_p~iF~ps|U,_ul LnnqC,_mqNvxq`@",
+			delim:    ',',
+			expected: []string{"_p~iF~ps|U"},
+			err:      polyline.ErrInvalidByte,
+		},
+		{
+			name:     "unterminated",
+			s:        "_p~iF~ps|U\n_ulLnnqC_",
+			delim:    '\n',
+			expected: []string{"_p~iF~ps|U"},
+			err:      polyline.ErrUnterminatedSequence,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := polyline.NewScanner(strings.NewReader(tc.s), tc.delim)
+			var got []string
+			for s.Scan() {
+				got = append(got, string(s.Bytes()))
+			}
+			assert.Equal(t, tc.expected, got)
+			assert.ErrorIs(t, s.Err(), tc.err)
+			assert.False(t, s.Scan())
+		})
+	}
+
+	assert.Panics(t, func() {
+		polyline.NewScanner(strings.NewReader(""), '?')
+	})
+}
+
+func TestScannerBuffer(t *testing.T) {
+	t.Parallel()
+	long := strings.Repeat("_p~iF~ps|U", 10)
+	s := polyline.NewScanner(strings.NewReader(long+"\n"+long), '\n')
+	s.Buffer(nil, 50)
+	assert.False(t, s.Scan())
+	assert.Error(t, s.Err())
+}
@@ -0,0 +1,23 @@
+package polyline
+
+// A Segment is a segment of a path between two consecutive coordinates.
+type Segment struct {
+	Index      int       // Index of the segment, and of Start in the path
+	Start, End []float64 // Coordinates at the ends of the segment
+	Along      float64   // Distance along the path to Start in meters
+	Length     float64   // Great-circle length in meters
+	Bearing    float64   // Initial bearing in degrees clockwise from north, in [0, 360)
+}
+
+// newSegment returns segment i of coords, which starts along meters along
+// the path.
+func newSegment(coords [][]float64, i int, along float64) Segment {
+	return Segment{
+		Index:   i,
+		Start:   coords[i],
+		End:     coords[i+1],
+		Along:   along,
+		Length:  haversine(coords[i], coords[i+1]),
+		Bearing: bearing(coords[i], coords[i+1]),
+	}
+}
@@ -0,0 +1,166 @@
+// Package serde marshals coordinates into message values for Kafka and
+// other stream processors, with the codec configuration carried in message
+// headers so that consumers decode every message correctly even when
+// producers use different precisions.
+//
+// Header is shaped like the record headers of the common Kafka clients, so
+// converting to and from them is a field-by-field copy.
+package serde
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+// ErrCodec is returned when a codec cannot be described by headers, or
+// headers do not describe a valid codec.
+var ErrCodec = errors.New("invalid codec headers")
+
+// Header keys.
+const (
+	// HeaderPrecision holds the number of decimal places of every
+	// dimension.
+	HeaderPrecision = "polyline-precision"
+	// HeaderDim holds the dimensionality.
+	HeaderDim = "polyline-dim"
+)
+
+// A Header is a message header.
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Headers returns the headers describing codec. Its scale must be a power
+// of ten, the same for every dimension, and its CRS must be WGS84.
+func Headers(codec polyline.Codec) ([]Header, error) {
+	scale := codec.Scale
+	if len(codec.Scales) > 0 {
+		scale = codec.Scales[0]
+		for _, s := range codec.Scales {
+			if s != scale {
+				return nil, fmt.Errorf("%w: scales differ between dimensions", ErrCodec)
+			}
+		}
+	}
+	precision := math.Log10(scale)
+	if precision != math.Trunc(precision) || precision < 0 || precision > 15 {
+		return nil, fmt.Errorf("%w: scale %g is not a power of ten", ErrCodec, scale)
+	}
+	if codec.CRS != "" || codec.Transformer != nil {
+		return nil, fmt.Errorf("%w: CRS %q", ErrCodec, codec.CRS)
+	}
+	return []Header{
+		{Key: HeaderPrecision, Value: []byte(strconv.Itoa(int(precision)))},
+		{Key: HeaderDim, Value: []byte(strconv.Itoa(codec.Dim))},
+	}, nil
+}
+
+// CodecFromHeaders returns base with its scale and dimensionality replaced
+// by those in headers, if present. Other headers are ignored.
+func CodecFromHeaders(headers []Header, base polyline.Codec) (polyline.Codec, error) {
+	for _, h := range headers {
+		switch h.Key {
+		case HeaderPrecision:
+			precision, err := strconv.Atoi(string(h.Value))
+			if err != nil || precision < 0 || precision > 15 {
+				return polyline.Codec{}, fmt.Errorf("%w: precision %q", ErrCodec, h.Value)
+			}
+			base.Scale = math.Pow10(precision)
+			base.Scales = nil
+		case HeaderDim:
+			dim, err := strconv.Atoi(string(h.Value))
+			if err != nil || dim < 1 {
+				return polyline.Codec{}, fmt.Errorf("%w: dim %q", ErrCodec, h.Value)
+			}
+			base.Dim = dim
+		}
+	}
+	return base, nil
+}
+
+// Marshal returns the encoding of coords with codec as a message value, and
+// the headers describing codec.
+func Marshal(coords [][]float64, codec polyline.Codec) ([]byte, []Header, error) {
+	headers, err := Headers(codec)
+	if err != nil {
+		return nil, nil, err
+	}
+	return codec.EncodeCoords(nil, coords), headers, nil
+}
+
+// Unmarshal decodes the message value with the codec described by headers,
+// starting from the default codec.
+func Unmarshal(value []byte, headers []Header) ([][]float64, error) {
+	codec, err := CodecFromHeaders(headers, polyline.DefaultCodec())
+	if err != nil {
+		return nil, err
+	}
+	coords, _, err := codec.DecodeCoords(value)
+	return coords, err
+}
+
+// MarshalBatch returns the encodings with codec of each of batch, for
+// messages that share the returned headers. The values share one backing
+// array, sized exactly in advance, so a batch costs a handful of
+// allocations however many messages it holds.
+func MarshalBatch(batch [][][]float64, codec polyline.Codec) ([][]byte, []Header, error) {
+	headers, err := Headers(codec)
+	if err != nil {
+		return nil, nil, err
+	}
+	size := 0
+	for _, coords := range batch {
+		size += polyline.EstimateEncodedSize(coords, codec)
+	}
+	buf := make([]byte, 0, size)
+	values := make([][]byte, len(batch))
+	for i, coords := range batch {
+		start := len(buf)
+		buf = codec.EncodeCoords(buf, coords)
+		values[i] = buf[start:len(buf):len(buf)]
+	}
+	return values, headers, nil
+}
+
+// UnmarshalBatch decodes each of values with the codec described by
+// headers, starting from the default codec. The coordinates of all values
+// share one backing array.
+func UnmarshalBatch(values [][]byte, headers []Header) ([][][]float64, error) {
+	codec, err := CodecFromHeaders(headers, polyline.DefaultCodec())
+	if err != nil {
+		return nil, err
+	}
+	// Every value ends with a byte below ContinuationByte, so counting them
+	// sizes the buffer exactly.
+	n := 0
+	for _, value := range values {
+		for _, c := range value {
+			if c < polyline.ContinuationByte {
+				n++
+			}
+		}
+	}
+	b := polyline.NewCoordBuffer(codec.Dim, n/codec.Dim)
+	ends := make([]int, len(values))
+	for i, value := range values {
+		if _, err := codec.DecodeInto(b, value); err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+		ends[i] = b.Len()
+	}
+	all := b.Coords()
+	batch := make([][][]float64, len(values))
+	start := 0
+	for i, end := range ends {
+		if end > start {
+			batch[i] = all[start:end:end]
+		}
+		start = end
+	}
+	return batch, nil
+}
@@ -0,0 +1,97 @@
+package serde_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/serde"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	value, headers, err := serde.Marshal(coords, polyline.DefaultCodec())
+	assert.NoError(t, err)
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", string(value))
+	assert.Equal(t, []serde.Header{
+		{Key: serde.HeaderPrecision, Value: []byte("5")},
+		{Key: serde.HeaderDim, Value: []byte("2")},
+	}, headers)
+	got, err := serde.Unmarshal(value, headers)
+	assert.NoError(t, err)
+	assert.Equal(t, coords, got)
+
+	codec := polyline.Codec6.WithDim(3)
+	coords3 := [][]float64{{1.000001, 2, 3}, {4, 5, 6.5}}
+	value, headers, err = serde.Marshal(coords3, codec)
+	assert.NoError(t, err)
+	got, err = serde.Unmarshal(value, headers)
+	assert.NoError(t, err)
+	assert.Equal(t, coords3, got)
+
+	// Without headers the default codec is used.
+	got, err = serde.Unmarshal([]byte("_p~iF~ps|U"), []serde.Header{{Key: "trace-id", Value: []byte("x")}})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{38.5, -120.2}}, got)
+
+	for _, codec := range []polyline.Codec{
+		polyline.DefaultCodec().WithScale(3),
+		polyline.DefaultCodec().WithScales(1e5, 1e6),
+		{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator},
+	} {
+		_, _, err = serde.Marshal(coords, codec)
+		assert.ErrorIs(t, err, serde.ErrCodec)
+		_, _, err = serde.MarshalBatch(nil, codec)
+		assert.ErrorIs(t, err, serde.ErrCodec)
+	}
+	for _, h := range []serde.Header{
+		{Key: serde.HeaderPrecision, Value: []byte("x")},
+		{Key: serde.HeaderPrecision, Value: []byte("16")},
+		{Key: serde.HeaderDim, Value: []byte("0")},
+	} {
+		_, err = serde.Unmarshal(nil, []serde.Header{h})
+		assert.ErrorIs(t, err, serde.ErrCodec)
+		_, err = serde.UnmarshalBatch(nil, []serde.Header{h})
+		assert.ErrorIs(t, err, serde.ErrCodec)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+	batch := [][][]float64{
+		{{38.5, -120.2}, {40.7, -120.95}},
+		nil,
+		{{1, 2}},
+	}
+	codec := polyline.Codec6
+	values, headers, err := serde.MarshalBatch(batch, codec)
+	assert.NoError(t, err)
+	assert.Len(t, values, 3)
+	for i, coords := range batch {
+		assert.Equal(t, string(codec.EncodeCoords(nil, coords)), string(values[i]))
+	}
+	// Values do not overwrite each other when appended to.
+	_ = append(values[0], 'x')
+	assert.Equal(t, string(codec.EncodeCoords(nil, batch[2])), string(values[2]))
+
+	got, err := serde.UnmarshalBatch(values, headers)
+	assert.NoError(t, err)
+	assert.Equal(t, batch, got)
+
+	_, err = serde.UnmarshalBatch([][]byte{values[0], []byte("_")}, headers)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
+
+func BenchmarkBatch(b *testing.B) {
+	batch := make([][][]float64, 1000)
+	for i := range batch {
+		batch[i] = [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	}
+	codec := polyline.DefaultCodec()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		values, headers, _ := serde.MarshalBatch(batch, codec)
+		_, _ = serde.UnmarshalBatch(values, headers)
+	}
+}
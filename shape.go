@@ -0,0 +1,179 @@
+package polyline
+
+import "math"
+
+// A ShapeKind is a kind of shape recognized by Codec.DecodeShape.
+type ShapeKind int
+
+// Shape kinds.
+const (
+	ShapePolyline ShapeKind = iota // Any other polyline
+	ShapeBounds                    // An axis-aligned bounding box
+	ShapeCircle                    // A regular polygon approximating a circle
+)
+
+func (k ShapeKind) String() string {
+	switch k {
+	case ShapePolyline:
+		return "polyline"
+	case ShapeBounds:
+		return "bounds"
+	case ShapeCircle:
+		return "circle"
+	default:
+		return "unknown"
+	}
+}
+
+// A Shape is a polyline decoded by Codec.DecodeShape. Min and Max are set
+// for ShapeBounds, and Center and Radius, in meters, for ShapeCircle.
+type Shape struct {
+	Kind     ShapeKind
+	Coords   [][]float64
+	Min, Max []float64
+	Center   []float64
+	Radius   float64
+}
+
+// boundsRing returns the closed ring around the box from min to max,
+// counterclockwise from min.
+func boundsRing(min, max []float64) [][]float64 {
+	return [][]float64{
+		{min[0], min[1]},
+		{min[0], max[1]},
+		{max[0], max[1]},
+		{max[0], min[1]},
+		{min[0], min[1]},
+	}
+}
+
+// EncodeBounds appends the encoding of the box from min to max, as a closed
+// ring of five two-dimensional coordinates starting at min, to buf and
+// returns the new buf. Like the box returned by DecodeShape, min and max are
+// WGS84 latitudes and longitudes whatever the codec's CRS.
+func (c Codec) EncodeBounds(buf []byte, min, max []float64) []byte {
+	return c.untransformed().EncodeCoords(buf, boundsRing(min, max))
+}
+
+// EncodeCircle appends the encoding of a closed regular polygon of n
+// vertices, at least three, approximating the circle of radius meters around
+// center to buf and returns the new buf.
+func (c Codec) EncodeCircle(buf []byte, center []float64, radius float64, n int) []byte {
+	if n < 3 {
+		n = 3
+	}
+	coords := make([][]float64, n+1)
+	for i := 0; i < n; i++ {
+		coords[i] = destination(center, 360*float64(i)/float64(n), radius)
+	}
+	coords[n] = coords[0]
+	return c.untransformed().EncodeCoords(buf, coords)
+}
+
+// DecodeShape decodes buf and recognizes whether it is a bounding box as
+// encoded by EncodeBounds or a circle as encoded by EncodeCircle with at
+// least eight vertices. Recognition allows for quantization error.
+func (c Codec) DecodeShape(buf []byte) (Shape, error) {
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return Shape{}, err
+	}
+	s := Shape{Kind: ShapePolyline, Coords: coords}
+	if len(coords) < 5 || c.Dim != 2 || !equalCoord(coords[0], coords[len(coords)-1]) {
+		return s, nil
+	}
+
+	if len(coords) == 5 {
+		min := []float64{math.Min(coords[0][0], coords[2][0]), math.Min(coords[0][1], coords[2][1])}
+		max := []float64{math.Max(coords[0][0], coords[2][0]), math.Max(coords[0][1], coords[2][1])}
+		if isRectangle(coords) {
+			s.Kind, s.Min, s.Max = ShapeBounds, min, max
+		}
+		return s, nil
+	}
+
+	vertices := coords[:len(coords)-1]
+	if len(vertices) < 8 {
+		return s, nil
+	}
+	// The vertices of a circle on the sphere lie in a plane whose normal
+	// through the origin passes through the circle's center, so the mean of
+	// their unit vectors points at it.
+	var x, y, z float64
+	for _, v := range vertices {
+		sinLat, cosLat := math.Sincos(radians(v[0]))
+		sinLng, cosLng := math.Sincos(radians(v[1]))
+		x += cosLat * cosLng
+		y += cosLat * sinLng
+		z += sinLat
+	}
+	center := []float64{degrees(math.Atan2(z, math.Hypot(x, y))), degrees(math.Atan2(y, x))}
+	if !winding(center, vertices) {
+		return s, nil
+	}
+	var radius, minRadius, maxRadius float64
+	minRadius = math.Inf(1)
+	for _, v := range vertices {
+		r := haversine(center, v)
+		radius += r / float64(len(vertices))
+		minRadius = math.Min(minRadius, r)
+		maxRadius = math.Max(maxRadius, r)
+	}
+	tolerance := math.Max(0.01*radius, 2*metersPerDegree/c.scale(0))
+	if maxRadius-minRadius <= tolerance {
+		s.Kind, s.Center, s.Radius = ShapeCircle, center, radius
+	}
+	return s, nil
+}
+
+// winding returns whether vertices go around center exactly once, always
+// turning in the same direction.
+func winding(center []float64, vertices [][]float64) bool {
+	var total, sign float64
+	prev := bearing(center, vertices[len(vertices)-1])
+	for _, v := range vertices {
+		b := bearing(center, v)
+		step := math.Remainder(b-prev, 360)
+		if step == 0 || sign != 0 && math.Signbit(step) != math.Signbit(sign) {
+			return false
+		}
+		sign = step
+		total += step
+		prev = b
+	}
+	return math.Abs(math.Abs(total)-360) < 1
+}
+
+// equalCoord returns whether a and b are equal.
+func equalCoord(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// isRectangle returns whether the closed ring of five coordinates ring is a
+// non-degenerate rectangle whose edges alternate between constant latitude
+// and constant longitude.
+func isRectangle(ring [][]float64) bool {
+	var prev int
+	for i := 0; i < 4; i++ {
+		a, b := ring[i], ring[i+1]
+		var axis int
+		switch {
+		case a[0] == b[0] && a[1] != b[1]:
+			axis = 1
+		case a[1] == b[1] && a[0] != b[0]:
+			axis = 2
+		default:
+			return false
+		}
+		if axis == prev {
+			return false
+		}
+		prev = axis
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeBounds(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	buf := codec.EncodeBounds(nil, []float64{51.28, -0.51}, []float64{51.69, 0.33})
+	coords, _, err := codec.DecodeCoords(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{51.28, -0.51}, {51.28, 0.33}, {51.69, 0.33}, {51.69, -0.51}, {51.28, -0.51}}, coords)
+
+	s, err := codec.DecodeShape(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.ShapeBounds, s.Kind)
+	assert.Equal(t, []float64{51.28, -0.51}, s.Min)
+	assert.Equal(t, []float64{51.69, 0.33}, s.Max)
+}
+
+func TestEncodeCircle(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	for _, tc := range []struct {
+		name   string
+		radius float64
+		n      int
+	}{
+		{name: "small", radius: 20, n: 8},
+		{name: "city", radius: 5000, n: 32},
+		{name: "large", radius: 100e3, n: 64},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			center := []float64{48.8566, 2.3522}
+			buf := codec.EncodeCircle(nil, center, tc.radius, tc.n)
+			s, err := codec.DecodeShape(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, polyline.ShapeCircle, s.Kind)
+			assert.Len(t, s.Coords, tc.n+1)
+			assert.InDelta(t, tc.radius, s.Radius, 0.01*tc.radius+1)
+			assert.InDelta(t, center[0], s.Center[0], 1e-4+tc.radius*1e-8)
+			assert.InDelta(t, center[1], s.Center[1], 1e-4+tc.radius*1e-8)
+		})
+	}
+}
+
+func TestDecodeShapePolyline(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	for _, tc := range []struct {
+		name   string
+		coords [][]float64
+	}{
+		{name: "open", coords: [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}},
+		{name: "triangle", coords: [][]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0.5}, {0, 0}}},
+		{name: "degenerate", coords: [][]float64{{0, 0}, {0, 1}, {0, 1}, {0, 0}, {0, 0}}},
+		{name: "square_circle", coords: [][]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := codec.DecodeShape(codec.EncodeCoords(nil, tc.coords))
+			assert.NoError(t, err)
+			assert.Equal(t, polyline.ShapePolyline, s.Kind)
+			assert.Equal(t, tc.coords, s.Coords)
+		})
+	}
+
+	_, err := codec.DecodeShape([]byte("_"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package polyline
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrSignature is returned when a signed polyline is missing its signature
+// or does not match it.
+var ErrSignature = errors.New("invalid signature")
+
+// SignatureDelimiter separates a polyline from its signature. It is not a
+// valid polyline byte.
+const SignatureDelimiter = '.'
+
+// Sign appends SignatureDelimiter and the HMAC-SHA256 of polyline under key,
+// in unpadded URL-safe base64, to polyline and returns the result. This lets
+// polylines passed through untrusted clients, for example in deep links, be
+// authenticated with VerifySignature.
+func Sign(polyline, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(polyline)
+	polyline = append(polyline, SignatureDelimiter)
+	return append(polyline, base64.RawURLEncoding.EncodeToString(mac.Sum(nil))...)
+}
+
+// VerifySignature checks a polyline signed by Sign under key and returns the
+// polyline without its signature. It returns ErrSignature if the signature is
+// missing or malformed or does not match.
+func VerifySignature(signed, key []byte) ([]byte, error) {
+	i := bytes.LastIndexByte(signed, SignatureDelimiter)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: no signature", ErrSignature)
+	}
+	polyline := signed[:i]
+	sum, err := base64.RawURLEncoding.DecodeString(string(signed[i+1:]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrSignature)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(polyline)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		return nil, ErrSignature
+	}
+	return polyline, nil
+}
+
+// DecodeCoordsSigned verifies a polyline signed by Sign under key and returns
+// its coordinates.
+func (c Codec) DecodeCoordsSigned(signed, key []byte) ([][]float64, error) {
+	buf, err := VerifySignature(signed, key)
+	if err != nil {
+		return nil, err
+	}
+	coords, _, err := c.DecodeCoords(buf)
+	return coords, err
+}
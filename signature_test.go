@@ -0,0 +1,56 @@
+package polyline_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	t.Parallel()
+	key := []byte("secret")
+	signed := string(polyline.Sign([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"), key))
+	assert.True(t, strings.HasPrefix(signed, "_p~iF~ps|U_ulLnnqC_mqNvxq`@."))
+	assert.Len(t, signed, len("_p~iF~ps|U_ulLnnqC_mqNvxq`@.")+43)
+	i := strings.LastIndexByte(signed, '.')
+	for _, tc := range []struct {
+		name     string
+		signed   string
+		key      string
+		expected string
+		err      error
+	}{
+		{name: "valid", signed: signed, key: "secret", expected: "_p~iF~ps|U_ulLnnqC_mqNvxq`@"},
+		{name: "empty", signed: string(polyline.Sign(nil, key)), key: "secret", expected: ""},
+		{name: "wrong_key", signed: signed, key: "guess", err: polyline.ErrSignature},
+		{name: "tampered", signed: "_p~iF~ps|U_ulLnnqC_mqNvxq`A" + signed[i:], key: "secret", err: polyline.ErrSignature},
+		{name: "truncated", signed: signed[:len(signed)-1], key: "secret", err: polyline.ErrSignature},
+		{name: "missing", signed: "_p~iF~ps|U", key: "secret", err: polyline.ErrSignature},
+		{name: "malformed", signed: "_p~iF~ps|U.!!", key: "secret", err: polyline.ErrSignature},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			buf, err := polyline.VerifySignature([]byte(tc.signed), []byte(tc.key))
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, string(buf))
+		})
+	}
+}
+
+func TestCodecDecodeCoordsSigned(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	key := []byte("secret")
+	coords := [][]float64{{38.5, -120.2}, {40.7, -120.95}}
+	got, err := codec.DecodeCoordsSigned(polyline.Sign(codec.EncodeCoords(nil, coords), key), key)
+	assert.NoError(t, err)
+	assert.Equal(t, coords, got)
+
+	_, err = codec.DecodeCoordsSigned(polyline.Sign(codec.EncodeCoords(nil, coords), key), []byte("guess"))
+	assert.ErrorIs(t, err, polyline.ErrSignature)
+	_, err = codec.DecodeCoordsSigned(polyline.Sign([]byte("_"), key), key)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
@@ -1,5 +1,12 @@
 package polyline
 
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
 type ChartPoint struct {
 	X float64
 	Y float64
@@ -110,24 +117,213 @@ func simplifyDouglasPeucker(points []Point, sqTolerance float64) []Point {
 }
 
 func Simplify(points *[]Point, tolerance float64, highestQuality bool) []Point {
-	arr := *points
+	opts := SimplifyOptions{Tolerance: tolerance}
+	if highestQuality {
+		opts.RadialTolerance = -1
+	}
+	return SimplifyWith(*points, opts)
+}
+
+// SimplifyOptions configures SimplifyWith, Simplify3, and
+// Codec.EncodePointsWith.
+type SimplifyOptions struct {
+	// Algorithm selects the simplification algorithm. Simplify3 always uses
+	// DouglasPeucker.
+	Algorithm SimplifyAlgorithm
+	// Tolerance is the distance tolerance of the algorithm. Zero means one.
+	Tolerance float64
+	// RadialTolerance is the distance from the previously kept point within
+	// which points are dropped before Algorithm runs. The pre-filter is
+	// much faster on dense input at some cost in quality. Zero means
+	// Tolerance and a negative value disables the pre-filter.
+	RadialTolerance float64
+	// ZScale converts Z values to the units of X and Y for Simplify3, for
+	// example 1/111320 for elevations in meters with X and Y in degrees.
+	// Zero means one.
+	ZScale float64
+	// Meters makes Tolerance and RadialTolerance distances in meters, and
+	// ZScale convert Z values to meters, with each point's X and Y taken as
+	// its latitude and longitude in degrees, as EncodePoints encodes them.
+	// Points are projected with a LocalProjection about their centroid, so
+	// tolerances are accurate to within a few percent for routes spanning
+	// up to a few hundred kilometers at any latitude.
+	Meters bool
+	// Model, if set with Meters, measures the distances of SimplifyWith's
+	// radial pre-filter and DouglasPeucker instead of the LocalProjection,
+	// for example Vincenty for tolerances that must hold on the ellipsoid.
+	// VisvalingamWhyatt areas and Simplify3 always use the LocalProjection.
+	Model DistanceModel
+}
+
+// SimplifyWith simplifies points as configured by opts.
+func SimplifyWith(points []Point, opts SimplifyOptions) []Point {
+	if len(points) <= 2 {
+		return points
+	}
+
+	if opts.Tolerance == 0 {
+		opts.Tolerance = 1
+	}
+	if opts.RadialTolerance == 0 {
+		opts.RadialTolerance = opts.Tolerance
+	}
 
-	if len(arr) <= 2 {
-		return arr
+	if opts.Meters {
+		return simplifyMeters(points, opts)
 	}
 
-	var sqTolerance float64
-	if tolerance == 0 {
-		sqTolerance = 1
-	} else {
-		sqTolerance = tolerance * tolerance
+	if opts.RadialTolerance > 0 {
+		points = simplifyRadialDist(points, opts.RadialTolerance*opts.RadialTolerance)
 	}
 
-	if !highestQuality {
-		arr = simplifyRadialDist(arr, sqTolerance)
+	if opts.Algorithm == VisvalingamWhyatt {
+		return simplifyVisvalingam(points, opts.Tolerance*opts.Tolerance)
+	}
+	return simplifyDouglasPeucker(points, opts.Tolerance*opts.Tolerance)
+}
+
+// SimplifySpans simplifies each span of points independently, as Simplify
+// does, using a pool of at most workers goroutines. If workers is zero or
+// negative then runtime.GOMAXPROCS(0) workers are used. The returned spans are
+// in the same order as spans.
+func SimplifySpans(spans [][]Point, tolerance float64, highestQuality bool, workers int) [][]Point {
+	result := make([][]Point, len(spans))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(spans) {
+		workers = len(spans)
 	}
 
-	arr = simplifyDouglasPeucker(arr, sqTolerance)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				span := spans[i]
+				result[i] = Simplify(&span, tolerance, highestQuality)
+			}
+		}()
+	}
+	for i := range spans {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return result
+}
 
-	return arr
+// SimplifyKeeping simplifies points while always keeping the points at the
+// indexes in keep, for example protected waypoints or the ends of segments
+// split at gaps. The spans between kept points are independent and are
+// simplified concurrently with SimplifySpans. Indexes outside points are
+// ignored.
+func SimplifyKeeping(points []Point, keep []int, tolerance float64, highestQuality bool, workers int) []Point {
+	if len(points) <= 2 {
+		return points
+	}
+
+	breaks := []int{0, len(points) - 1}
+	for _, i := range keep {
+		if 0 < i && i < len(points)-1 {
+			breaks = append(breaks, i)
+		}
+	}
+	sort.Ints(breaks)
+
+	var spans [][]Point
+	for i := 1; i < len(breaks); i++ {
+		if breaks[i] == breaks[i-1] {
+			continue
+		}
+		spans = append(spans, points[breaks[i-1]:breaks[i]+1])
+	}
+
+	simplified := []Point{points[0]}
+	for _, span := range SimplifySpans(spans, tolerance, highestQuality, workers) {
+		simplified = append(simplified, span[1:]...)
+	}
+	return simplified
+}
+
+// coordPoint is a Point backed by a coordinate, so that simplified Points can
+// be converted back to coordinates without losing extra dimensions.
+type coordPoint []float64
+
+func (p coordPoint) GetX() float64 {
+	return p[0]
+}
+
+func (p coordPoint) GetY() float64 {
+	return p[1]
+}
+
+// simplifyCoords simplifies coords with Douglas-Peucker at tolerance in
+// degrees. A tolerance of zero or less keeps every coordinate.
+func simplifyCoords(coords [][]float64, tolerance float64) [][]float64 {
+	if tolerance <= 0 || len(coords) <= 2 {
+		return coords
+	}
+	points := make([]Point, len(coords))
+	for i, coord := range coords {
+		points[i] = coordPoint(coord)
+	}
+	points = Simplify(&points, tolerance, true)
+	simplified := make([][]float64, len(points))
+	for i, point := range points {
+		simplified[i] = point.(coordPoint)
+	}
+	return simplified
+}
+
+// triangleArea returns the area of the triangle with corners p, p1 and p2.
+func triangleArea(p Point, p1 Point, p2 Point) float64 {
+	return math.Abs((p1.GetX()-p.GetX())*(p2.GetY()-p.GetY())-(p2.GetX()-p.GetX())*(p1.GetY()-p.GetY())) / 2
+}
+
+func simplifyDPAreaStep(points []Point, first int, last int, epsilon float64, simplified []Point) []Point {
+	maxArea := epsilon
+	var index int
+
+	for i := first + 1; i < last; i++ {
+		area := triangleArea(points[i], points[first], points[last])
+
+		if area > maxArea {
+			index = i
+			maxArea = area
+		}
+	}
+
+	if maxArea > epsilon {
+		if index-first > 1 {
+			simplified = simplifyDPAreaStep(points, first, index, epsilon, simplified)
+		}
+		simplified = append(simplified, points[index])
+		if last-index > 1 {
+			simplified = simplifyDPAreaStep(points, index, last, epsilon, simplified)
+		}
+	}
+
+	return simplified
+}
+
+// SimplifyArea simplifies points with a variant of Douglas-Peucker that
+// splits at the point forming the largest triangle with the ends of each
+// span, and drops every point of a span whose triangles all have an area of
+// at most epsilon. Unlike a distance threshold, small zig-zags between
+// nearby points are removed while long gentle bends are kept.
+func SimplifyArea(points []Point, epsilon float64) []Point {
+	if len(points) <= 2 {
+		return points
+	}
+	last := len(points) - 1
+
+	simplified := []Point{points[0]}
+	simplified = simplifyDPAreaStep(points, 0, last, epsilon, simplified)
+	simplified = append(simplified, points[last])
+
+	return simplified
 }
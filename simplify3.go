@@ -0,0 +1,150 @@
+package polyline
+
+// A Point3 is a Point with a third dimension, normally elevation.
+type Point3 interface {
+	Point
+	GetZ() float64
+}
+
+// A ChartPoint3 is a ChartPoint with a Z value.
+type ChartPoint3 struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+func (p ChartPoint3) GetX() float64 {
+	return p.X
+}
+
+func (p ChartPoint3) GetY() float64 {
+	return p.Y
+}
+
+func (p ChartPoint3) GetZ() float64 {
+	return p.Z
+}
+
+// getSqDist3 and getSqSegDist3 are getSqDist and getSqSegDist in three
+// dimensions, with Z values multiplied by zScale.
+func getSqDist3(p1 Point3, p2 Point3, zScale float64) float64 {
+	dx := p1.GetX() - p2.GetX()
+	dy := p1.GetY() - p2.GetY()
+	dz := (p1.GetZ() - p2.GetZ()) * zScale
+	return dx*dx + dy*dy + dz*dz
+}
+
+func getSqSegDist3(p Point3, p1 Point3, p2 Point3, zScale float64) float64 {
+	x, y, z := p1.GetX(), p1.GetY(), p1.GetZ()*zScale
+	dx, dy, dz := p2.GetX()-x, p2.GetY()-y, p2.GetZ()*zScale-z
+
+	if dx != 0 || dy != 0 || dz != 0 {
+		t := ((p.GetX()-x)*dx + (p.GetY()-y)*dy + (p.GetZ()*zScale-z)*dz) / (dx*dx + dy*dy + dz*dz)
+		if t > 1 {
+			x, y, z = p2.GetX(), p2.GetY(), p2.GetZ()*zScale
+		} else if t > 0 {
+			x += dx * t
+			y += dy * t
+			z += dz * t
+		}
+	}
+
+	dx, dy, dz = p.GetX()-x, p.GetY()-y, p.GetZ()*zScale-z
+	return dx*dx + dy*dy + dz*dz
+}
+
+func simplifyRadialDist3(points []Point3, sqTolerance, zScale float64) []Point3 {
+	prev := 0
+	newPoints := []Point3{points[0]}
+	for i := 1; i < len(points); i++ {
+		if getSqDist3(points[i], points[prev], zScale) > sqTolerance {
+			newPoints = append(newPoints, points[i])
+			prev = i
+		}
+	}
+	if last := len(points) - 1; prev != last {
+		newPoints = append(newPoints, points[last])
+	}
+	return newPoints
+}
+
+func simplifyDPStep3(points []Point3, first int, last int, sqTolerance, zScale float64, simplified []Point3) []Point3 {
+	maxSqDist := sqTolerance
+	var index int
+
+	for i := first + 1; i < last; i++ {
+		sqDist := getSqSegDist3(points[i], points[first], points[last], zScale)
+		if sqDist > maxSqDist {
+			index = i
+			maxSqDist = sqDist
+		}
+	}
+
+	if maxSqDist > sqTolerance {
+		if index-first > 1 {
+			simplified = simplifyDPStep3(points, first, index, sqTolerance, zScale, simplified)
+		}
+		simplified = append(simplified, points[index])
+		if last-index > 1 {
+			simplified = simplifyDPStep3(points, index, last, sqTolerance, zScale, simplified)
+		}
+	}
+
+	return simplified
+}
+
+// Simplify3 simplifies points as SimplifyWith does but measures distances
+// in three dimensions, so that points where only the elevation changes, such
+// as the top of a climb, are kept. Z values are multiplied by opts.ZScale
+// before distances are measured.
+func Simplify3(points []Point3, opts SimplifyOptions) []Point3 {
+	if len(points) <= 2 {
+		return points
+	}
+
+	if opts.Tolerance == 0 {
+		opts.Tolerance = 1
+	}
+	if opts.RadialTolerance == 0 {
+		opts.RadialTolerance = opts.Tolerance
+	}
+	if opts.ZScale == 0 {
+		opts.ZScale = 1
+	}
+	if opts.Meters {
+		return simplifyMeters3(points, opts)
+	}
+
+	if opts.RadialTolerance > 0 {
+		points = simplifyRadialDist3(points, opts.RadialTolerance*opts.RadialTolerance, opts.ZScale)
+	}
+
+	last := len(points) - 1
+	simplified := []Point3{points[0]}
+	simplified = simplifyDPStep3(points, 0, last, opts.Tolerance*opts.Tolerance, opts.ZScale, simplified)
+	return append(simplified, points[last])
+}
+
+// EncodePoints3D simplifies points in three dimensions with Simplify3 and
+// returns their encoding, as EncodePoints does for two-dimensional points.
+// X, Y, and Z are encoded as the first three dimensions, so c.Dim should be
+// three; dimensions beyond c.Dim are dropped and any beyond the third are
+// zero.
+func (c Codec) EncodePoints3D(points []Point3, tolerance float64, useHighQuality bool) []byte {
+	opts := SimplifyOptions{Tolerance: tolerance}
+	if useHighQuality {
+		opts.RadialTolerance = -1
+	}
+	return c.EncodePoints3DWith(points, opts)
+}
+
+// EncodePoints3DWith simplifies points in three dimensions as configured by
+// opts and returns their encoding. See EncodePoints3D.
+func (c Codec) EncodePoints3DWith(points []Point3, opts SimplifyOptions) []byte {
+	simplified := Simplify3(points, opts)
+	generic := make([]Point, len(simplified))
+	for i, p := range simplified {
+		generic[i] = p
+	}
+	return c.encodePoints(generic)
+}
@@ -0,0 +1,308 @@
+package polyline_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func zigzag(n int) []polyline.Point {
+	points := make([]polyline.Point, n)
+	for i := range points {
+		points[i] = polyline.ChartPoint{X: float64(i), Y: float64(i % 2)}
+	}
+	return points
+}
+
+func TestSimplifySpans(t *testing.T) {
+	t.Parallel()
+	spans := [][]polyline.Point{
+		zigzag(100),
+		zigzag(2),
+		zigzag(1000),
+	}
+	for _, workers := range []int{0, 1, 2, 8} {
+		got := polyline.SimplifySpans(spans, 2, true, workers)
+		assert.Len(t, got, len(spans))
+		for i, span := range spans {
+			assert.Equal(t, polyline.Simplify(&span, 2, true), got[i])
+		}
+	}
+}
+
+func TestSimplifyKeeping(t *testing.T) {
+	t.Parallel()
+	points := zigzag(100)
+	for _, tc := range []struct {
+		name string
+		keep []int
+		want []polyline.Point
+	}{
+		{
+			name: "none",
+			want: []polyline.Point{points[0], points[99]},
+		},
+		{
+			name: "one",
+			keep: []int{51},
+			want: []polyline.Point{points[0], points[51], points[99]},
+		},
+		{
+			name: "unsorted_duplicates_and_out_of_range",
+			keep: []int{75, -1, 25, 0, 75, 99, 100},
+			want: []polyline.Point{points[0], points[25], points[75], points[99]},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, polyline.SimplifyKeeping(points, tc.keep, 2, true, 4))
+		})
+	}
+}
+
+func TestSimplifyArea(t *testing.T) {
+	t.Parallel()
+	noisy := []polyline.Point{
+		polyline.ChartPoint{X: 0, Y: 0},
+		polyline.ChartPoint{X: 0.1, Y: 0.1},
+		polyline.ChartPoint{X: 0.2, Y: 0},
+		polyline.ChartPoint{X: 0.3, Y: 0.1},
+		polyline.ChartPoint{X: 10, Y: 0},
+		polyline.ChartPoint{X: 20, Y: 5},
+		polyline.ChartPoint{X: 30, Y: 0},
+	}
+	for _, tc := range []struct {
+		name    string
+		points  []polyline.Point
+		epsilon float64
+		want    []polyline.Point
+	}{
+		{
+			name:    "short",
+			points:  noisy[:2],
+			epsilon: 1,
+			want:    noisy[:2],
+		},
+		{
+			name:    "zero",
+			points:  noisy,
+			epsilon: 0,
+			want:    noisy,
+		},
+		{
+			name:    "zigzag",
+			points:  noisy,
+			epsilon: 1,
+			want:    []polyline.Point{noisy[0], noisy[4], noisy[5], noisy[6]},
+		},
+		{
+			name:    "large",
+			points:  noisy,
+			epsilon: 100,
+			want:    []polyline.Point{noisy[0], noisy[6]},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, polyline.SimplifyArea(tc.points, tc.epsilon))
+		})
+	}
+}
+
+func TestSimplifyWith(t *testing.T) {
+	t.Parallel()
+	points := make([]polyline.Point, 21)
+	for i := range points {
+		points[i] = polyline.ChartPoint{X: float64(i) / 10}
+	}
+	points[5] = polyline.ChartPoint{X: 0.5, Y: 1}
+	for _, tc := range []struct {
+		name string
+		opts polyline.SimplifyOptions
+		want []polyline.Point
+	}{
+		{
+			name: "default_radial",
+			opts: polyline.SimplifyOptions{Tolerance: 0.5},
+			want: []polyline.Point{points[0], points[5], points[6], points[20]},
+		},
+		{
+			name: "coarse_radial",
+			opts: polyline.SimplifyOptions{Tolerance: 0.5, RadialTolerance: 2},
+			want: []polyline.Point{points[0], points[20]},
+		},
+		{
+			name: "no_radial",
+			opts: polyline.SimplifyOptions{Tolerance: 0.5, RadialTolerance: -1},
+			want: []polyline.Point{points[0], points[5], points[6], points[20]},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, polyline.SimplifyWith(points, tc.opts))
+		})
+	}
+
+	assert.Equal(t, polyline.Simplify(&points, 0.5, true), polyline.SimplifyWith(points, polyline.SimplifyOptions{Tolerance: 0.5, RadialTolerance: -1}))
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	assert.Equal(t, codec.EncodePoints(points, 0.5, false), codec.EncodePointsWith(points, polyline.SimplifyOptions{Tolerance: 0.5}))
+}
+
+func TestSimplify3(t *testing.T) {
+	t.Parallel()
+	p := func(x, y, z float64) polyline.Point3 {
+		return polyline.ChartPoint3{X: x, Y: y, Z: z}
+	}
+	for _, tc := range []struct {
+		name     string
+		points   []polyline.Point3
+		opts     polyline.SimplifyOptions
+		expected []polyline.Point3
+	}{
+		{
+			name:     "short",
+			points:   []polyline.Point3{p(0, 0, 0), p(1, 1, 1)},
+			expected: []polyline.Point3{p(0, 0, 0), p(1, 1, 1)},
+		},
+		{
+			name:     "flat",
+			points:   []polyline.Point3{p(0, 0, 0), p(5, 0.1, 0), p(10, 0, 0)},
+			expected: []polyline.Point3{p(0, 0, 0), p(10, 0, 0)},
+		},
+		{
+			name:     "peak",
+			points:   []polyline.Point3{p(0, 0, 0), p(5, 0, 3), p(10, 0, 0)},
+			expected: []polyline.Point3{p(0, 0, 0), p(5, 0, 3), p(10, 0, 0)},
+		},
+		{
+			name:     "scaled_peak",
+			points:   []polyline.Point3{p(0, 0, 0), p(5, 0, 3), p(10, 0, 0)},
+			opts:     polyline.SimplifyOptions{ZScale: 0.1},
+			expected: []polyline.Point3{p(0, 0, 0), p(10, 0, 0)},
+		},
+		{
+			name:     "radial",
+			points:   []polyline.Point3{p(0, 0, 0), p(0, 0, 0.5), p(5, 0, 3), p(10, 0, 0), p(10, 0, 0.5)},
+			expected: []polyline.Point3{p(0, 0, 0), p(5, 0, 3), p(10, 0, 0.5)},
+		},
+		{
+			name:     "no_radial",
+			points:   []polyline.Point3{p(0, 0, 0), p(0, 0, 0.5), p(5, 0, 3), p(10, 0, 0), p(10, 0, 0.5)},
+			opts:     polyline.SimplifyOptions{RadialTolerance: -1},
+			expected: []polyline.Point3{p(0, 0, 0), p(5, 0, 3), p(10, 0, 0.5)},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, polyline.Simplify3(tc.points, tc.opts))
+		})
+	}
+}
+
+func TestSimplifyVisvalingamWhyatt(t *testing.T) {
+	t.Parallel()
+	noisy := []polyline.Point{
+		polyline.ChartPoint{X: 0, Y: 0},
+		polyline.ChartPoint{X: 0.1, Y: 0.1},
+		polyline.ChartPoint{X: 0.2, Y: 0},
+		polyline.ChartPoint{X: 0.3, Y: 0.1},
+		polyline.ChartPoint{X: 10, Y: 0},
+		polyline.ChartPoint{X: 20, Y: 5},
+		polyline.ChartPoint{X: 30, Y: 0},
+	}
+	for _, tc := range []struct {
+		name      string
+		points    []polyline.Point
+		tolerance float64
+		want      []polyline.Point
+	}{
+		{
+			name:      "short",
+			points:    noisy[:2],
+			tolerance: 1,
+			want:      noisy[:2],
+		},
+		{
+			name:      "zigzag",
+			points:    noisy,
+			tolerance: 1,
+			want:      []polyline.Point{noisy[0], noisy[4], noisy[5], noisy[6]},
+		},
+		{
+			name:      "large",
+			points:    noisy,
+			tolerance: 10,
+			want:      []polyline.Point{noisy[0], noisy[6]},
+		},
+		{
+			name:      "tiny",
+			points:    noisy,
+			tolerance: 0.001,
+			want:      noisy,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			opts := polyline.SimplifyOptions{
+				Algorithm:       polyline.VisvalingamWhyatt,
+				Tolerance:       tc.tolerance,
+				RadialTolerance: -1,
+			}
+			assert.Equal(t, tc.want, polyline.SimplifyWith(tc.points, opts))
+		})
+	}
+
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	opts := polyline.SimplifyOptions{Algorithm: polyline.VisvalingamWhyatt, Tolerance: 1, RadialTolerance: -1}
+	assert.Equal(t, codec.EncodePoints([]polyline.Point{noisy[0], noisy[4], noisy[5], noisy[6]}, 1, true), codec.EncodePointsWith(noisy, opts))
+}
+
+func TestSimplifyMeters(t *testing.T) {
+	t.Parallel()
+	// A street with a 5m kink and a 50m detour, near the equator and near
+	// the pole, where a degree of longitude is much shorter.
+	for _, lat := range []float64{0, 70} {
+		lat := lat
+		t.Run(fmt.Sprint(lat), func(t *testing.T) {
+			t.Parallel()
+			k := 1 / math.Cos(lat*math.Pi/180)
+			p := func(dLat, dLng float64) polyline.Point {
+				return polyline.ChartPoint{X: lat + dLat, Y: dLng * k}
+			}
+			points := []polyline.Point{p(0, 0), p(0.00005, 0.001), p(0, 0.002), p(0.00045, 0.003), p(0, 0.004), p(0, 0.005)}
+			opts := polyline.SimplifyOptions{Tolerance: 10, Meters: true}
+			assert.Equal(t, []polyline.Point{points[0], points[2], points[3], points[4], points[5]}, polyline.SimplifyWith(points, opts))
+			opts.Tolerance = 1
+			assert.Equal(t, points, polyline.SimplifyWith(points, opts))
+			opts.Tolerance = 100
+			assert.Equal(t, []polyline.Point{points[0], points[5]}, polyline.SimplifyWith(points, opts))
+
+			// Doubled by the model, the 5m kink exceeds a tolerance of 8m.
+			opts.Tolerance, opts.Model = 10, polyline.Haversine
+			assert.Equal(t, []polyline.Point{points[0], points[2], points[3], points[4], points[5]}, polyline.SimplifyWith(points, opts))
+			opts.Tolerance, opts.Model = 8, doubledModel{}
+			assert.Equal(t, points, polyline.SimplifyWith(points, opts))
+			opts.Algorithm = polyline.VisvalingamWhyatt
+			assert.Equal(t, points, polyline.SimplifyWith(points, opts))
+		})
+	}
+
+	p3 := func(x, y, z float64) polyline.Point3 {
+		return polyline.ChartPoint3{X: x, Y: y, Z: z}
+	}
+	points3 := []polyline.Point3{p3(0, 0, 0), p3(0, 0.001, 20), p3(0, 0.002, 0)}
+	opts := polyline.SimplifyOptions{Tolerance: 10, Meters: true}
+	assert.Equal(t, points3, polyline.Simplify3(points3, opts))
+	opts.Tolerance = 30
+	assert.Equal(t, []polyline.Point3{points3[0], points3[2]}, polyline.Simplify3(points3, opts))
+	opts.Tolerance, opts.ZScale = 10, 0.1
+	assert.Equal(t, []polyline.Point3{points3[0], points3[2]}, polyline.Simplify3(points3, opts))
+}
@@ -0,0 +1,39 @@
+package polyline
+
+import "math"
+
+// sinuosity returns the ratio of length to the distance between a and b.
+func sinuosity(length float64, a, b []float64) float64 {
+	if length == 0 {
+		return 1
+	}
+	return length / haversine(a, b)
+}
+
+// Sinuosity returns the length of coords divided by the distance between
+// their endpoints. A straight line has a sinuosity of one. It returns one for
+// polylines of zero length and +Inf for closed loops.
+func Sinuosity(coords [][]float64) float64 {
+	if len(coords) < 2 {
+		return 1
+	}
+	return sinuosity(pathLength(coords), coords[0], coords[len(coords)-1])
+}
+
+// WindowedSinuosity returns, for each vertex of coords, the sinuosity of the
+// window of length windowMeters that starts at that vertex. Near the end of
+// coords the window is truncated at the last vertex.
+func WindowedSinuosity(coords [][]float64, windowMeters float64) []float64 {
+	result := make([]float64, len(coords))
+	if len(coords) == 0 {
+		return result
+	}
+	cum := cumulativeDistances(coords)
+	total := cum[len(cum)-1]
+	for i, coord := range coords {
+		end := math.Min(cum[i]+windowMeters, total)
+		endCoord, _ := pointAtDistance(coords, cum, end)
+		result[i] = sinuosity(end-cum[i], coord, endCoord)
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package polyline_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinuosity(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 1.0, polyline.Sinuosity(nil))
+	assert.Equal(t, 1.0, polyline.Sinuosity([][]float64{{1, 2}, {1, 2}}))
+	assert.InDelta(t, 1.0, polyline.Sinuosity(meridian(10)), 1e-12)
+	assert.InDelta(t, math.Sqrt2, polyline.Sinuosity([][]float64{{0, 0}, {0.01, 0}, {0.01, 0.01}}), 1e-4)
+	assert.True(t, math.IsInf(polyline.Sinuosity([][]float64{{0, 0}, {0.01, 0}, {0, 0}}), 1))
+}
+
+func TestWindowedSinuosity(t *testing.T) {
+	t.Parallel()
+	// Straight north for 2.2km, then east for 2.2km.
+	coords := [][]float64{{0, 0}, {0.01, 0}, {0.02, 0}, {0.02, 0.01}, {0.02, 0.02}}
+	got := polyline.WindowedSinuosity(coords, 2224)
+	assert.Len(t, got, 5)
+	assert.InDelta(t, 1.0, got[0], 1e-3)
+	assert.InDelta(t, math.Sqrt2, got[1], 1e-3)
+	assert.InDelta(t, 1.0, got[2], 1e-3)
+	assert.InDelta(t, 1.0, got[3], 1e-3)
+	assert.Equal(t, 1.0, got[4])
+	assert.Empty(t, polyline.WindowedSinuosity(nil, 100))
+}
@@ -0,0 +1,80 @@
+package polyline
+
+import "math"
+
+// intLen returns the length of the encoding of i.
+func intLen(i int) int {
+	var u uint
+	if i < 0 {
+		u = uint(^(i << 1))
+	} else {
+		u = uint(i << 1)
+	}
+	n := 1
+	for u >= 32 {
+		u >>= 5
+		n++
+	}
+	return n
+}
+
+// EstimateEncodedSize returns the exact length of the encoding of coords by
+// codec, computed from the deltas alone without writing any bytes, for
+// admission control and storage planning.
+func EstimateEncodedSize(coords [][]float64, codec Codec) int {
+	if codec.Transformer != nil {
+		coords, codec = codec.toWGS84(coords), codec.untransformed()
+	}
+	var n int
+	if codec.CoalesceQuantumDuplicates {
+		codec.coalescedDeltas(coords, func(delta int) {
+			n += intLen(delta)
+		})
+		return n
+	}
+	last := make([]int, codec.Dim)
+	for _, coord := range coords {
+		for i, x := range coord {
+			ex := codec.quantize(i, x)
+			n += intLen(ex - last[i])
+			last[i] = ex
+		}
+	}
+	return n
+}
+
+// EncodePointsToSize simplifies points with the smallest tolerance, found by
+// bisection, whose encoding fits in maxBytes, for example to keep a Google
+// Static Maps URL within its length limit, and returns the encoding. Points
+// are simplified with the radial pre-filter disabled. If the encoding of all
+// points fits then no point is dropped. If not even the first and last
+// points fit then their encoding is returned regardless.
+func (c Codec) EncodePointsToSize(points []Point, maxBytes int) []byte {
+	buf := c.encodePoints(points)
+	if len(buf) <= maxBytes || len(points) <= 2 {
+		return buf
+	}
+
+	// No point is further than hi from the first point, so at a tolerance
+	// of hi only the ends are kept.
+	var hi float64
+	for _, p := range points {
+		hi = math.Max(hi, getSqDist(p, points[0]))
+	}
+	hi = math.Sqrt(hi)
+	best := c.encodePoints([]Point{points[0], points[len(points)-1]})
+	if hi == 0 || len(best) > maxBytes {
+		return best
+	}
+	lo := 0.0
+	for i := 0; i < 48; i++ {
+		mid := (lo + hi) / 2
+		buf := c.EncodePointsWith(points, SimplifyOptions{Tolerance: mid, RadialTolerance: -1})
+		if len(buf) <= maxBytes {
+			best, hi = buf, mid
+		} else {
+			lo = mid
+		}
+	}
+	return best
+}
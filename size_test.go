@@ -0,0 +1,59 @@
+package polyline_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateEncodedSize(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(0))
+	codecs := []polyline.Codec{
+		{Dim: 2, Scale: 1e5},
+		{Dim: 2, Scale: 1e6, Rounding: polyline.RoundJS},
+		{Dim: 2, Scale: 1e5, CoalesceQuantumDuplicates: true},
+	}
+	for i := 0; i < 20; i++ {
+		coords := polyline.Generate(r, polyline.GenOptions{Points: r.Intn(100), StepMeters: 0.5})
+		for _, codec := range codecs {
+			assert.Equal(t, len(codec.EncodeCoords(nil, coords)), polyline.EstimateEncodedSize(coords, codec))
+		}
+	}
+	codec := polyline.Codec{Dim: 1, Scale: 1}
+	for _, x := range []float64{0, 15, 16, -16, -17, 511, 512, math.MaxInt32, math.MinInt32} {
+		coords := [][]float64{{x}}
+		assert.Equal(t, len(codec.EncodeCoords(nil, coords)), polyline.EstimateEncodedSize(coords, codec))
+	}
+}
+
+func TestEncodePointsToSize(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(1))
+	coords := polyline.Generate(r, polyline.GenOptions{Points: 500, StepMeters: 50})
+	points := make([]polyline.Point, len(coords))
+	for i, coord := range coords {
+		points[i] = polyline.ChartPoint{X: coord[0], Y: coord[1]}
+	}
+	codec := polyline.DefaultCodec()
+	full := codec.EncodeCoords(nil, coords)
+	ends := codec.EncodeCoords(nil, [][]float64{coords[0], coords[len(coords)-1]})
+
+	assert.Equal(t, full, codec.EncodePointsToSize(points, len(full)))
+	prev := len(full)
+	for _, maxBytes := range []int{len(full) - 1, len(full) / 2, 512, 64, len(ends)} {
+		buf := codec.EncodePointsToSize(points, maxBytes)
+		assert.LessOrEqual(t, len(buf), maxBytes)
+		assert.LessOrEqual(t, len(buf), prev)
+		prev = len(buf)
+		got, _, err := codec.DecodeCoords(buf)
+		assert.NoError(t, err)
+		assert.InDeltaSlice(t, coords[0], got[0], 1e-5)
+		assert.InDeltaSlice(t, coords[len(coords)-1], got[len(got)-1], 1e-5)
+	}
+	assert.Equal(t, ends, codec.EncodePointsToSize(points, 1))
+	assert.Equal(t, codec.EncodePoints(points[:2], 1, true), codec.EncodePointsToSize(points[:2], 1))
+}
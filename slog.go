@@ -0,0 +1,52 @@
+//go:build go1.21
+
+package polyline
+
+import "log/slog"
+
+// A Polyline is an encoded polyline that implements slog.LogValuer. It logs
+// as a group summarizing the polyline, whose size is bounded whatever the
+// length of Buf, rather than the polyline itself: its Fingerprint, its
+// length in bytes, its number of points, its great-circle length in meters,
+// and its bounding box as min latitude, min longitude, max latitude, and max
+// longitude. If Buf does not decode then the points, length, and bounding
+// box are replaced by the error.
+type Polyline struct {
+	Buf     []byte
+	Codec   Codec // Codec of Buf, at least two-dimensional, default Dim 2 and Scale 1e5
+	Verbose bool  // Also log Buf itself
+}
+
+// LogValue implements slog.LogValuer.
+func (p Polyline) LogValue() slog.Value {
+	codec := p.Codec
+	if codec.Dim == 0 {
+		codec = defaultCodec
+	}
+	attrs := []slog.Attr{
+		slog.String("fingerprint", Fingerprint(p.Buf)),
+		slog.Int("bytes", len(p.Buf)),
+	}
+	var meters float64
+	points, bbox, err := codec.bounds(p.Buf, func(i int, prev, coord []float64) {
+		if prev != nil {
+			meters += haversine(prev, coord)
+		}
+	})
+	switch {
+	case err != nil:
+		attrs = append(attrs, slog.String("error", err.Error()))
+	case points == 0:
+		attrs = append(attrs, slog.Int("points", 0))
+	default:
+		attrs = append(attrs,
+			slog.Int("points", points),
+			slog.Float64("length", meters),
+			slog.Any("bbox", bbox),
+		)
+	}
+	if p.Verbose {
+		attrs = append(attrs, slog.String("polyline", string(p.Buf)))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,67 @@
+//go:build go1.21
+
+package polyline_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolylineLogValue(t *testing.T) {
+	t.Parallel()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	fp := polyline.Fingerprint(buf)
+	for _, tc := range []struct {
+		name     string
+		p        polyline.Polyline
+		expected string
+	}{
+		{
+			name:     "summary",
+			p:        polyline.Polyline{Buf: buf},
+			expected: "msg=route route.fingerprint=" + fp + " route.bytes=27 route.points=3 route.length=",
+		},
+		{
+			name:     "codec",
+			p:        polyline.Polyline{Buf: buf, Codec: polyline.DefaultCodec().WithDim(3)},
+			expected: " route.bytes=27 route.points=2 route.length=",
+		},
+		{
+			name:     "error",
+			p:        polyline.Polyline{Buf: []byte("_p~iF~ps|U_")},
+			expected: " route.bytes=11 route.error=",
+		},
+		{
+			name:     "verbose",
+			p:        polyline.Polyline{Buf: buf, Verbose: true},
+			expected: " route.polyline=_p~iF~ps|U_ulLnnqC_mqNvxq`@\n",
+		},
+		{
+			name:     "empty",
+			p:        polyline.Polyline{},
+			expected: "route.bytes=0 route.points=0\n",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			logger.Info("route", "route", tc.p)
+			assert.Contains(t, out.String(), tc.expected)
+			assert.Equal(t, tc.p.Verbose, strings.Contains(out.String(), "route.polyline="))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package polyline
+
+// SnapToRoute returns trace snapped onto route, for clean "as driven"
+// visualizations of noisy traces. Fixes are matched to route with
+// MatchProgress. Fixes within corridorMeters of route are replaced by their
+// matched positions, fixes further away are moved towards their matched
+// positions until they are on the edge of the corridor, and the vertices of
+// route passed between consecutive matched positions are inserted so that
+// the output follows route around corners. It returns nil if route has fewer
+// than two points.
+func SnapToRoute(route, trace [][]float64, corridorMeters float64) [][]float64 {
+	return SnapToRouteWith(route, trace, corridorMeters, Haversine)
+}
+
+// SnapToRouteWith snaps trace onto route as SnapToRoute does, with distances,
+// including corridorMeters, measured with model. A nil model means
+// Haversine.
+func SnapToRouteWith(route, trace [][]float64, corridorMeters float64, model DistanceModel) [][]float64 {
+	positions := MatchProgress(route, trace, MatchOptions{Model: model})
+	if positions == nil {
+		return nil
+	}
+	result := make([][]float64, 0, len(trace))
+	for i, p := range positions {
+		if i > 0 {
+			for j := positions[i-1].Segment + 1; j <= p.Segment; j++ {
+				result = append(result, cloneCoord(route[j]))
+			}
+		}
+		if p.Distance <= corridorMeters {
+			result = append(result, p.Coord)
+			continue
+		}
+		result = append(result, interpolate(p.Coord, trace[i], corridorMeters/p.Distance))
+	}
+	return result
+}
+
+// SnapToRoute decodes route and trace, snaps trace onto route with
+// SnapToRoute, and returns the encoding of the result.
+func (c Codec) SnapToRoute(route, trace []byte, corridorMeters float64) ([]byte, error) {
+	c = c.untransformed()
+	routeCoords, _, err := c.DecodeCoords(route)
+	if err != nil {
+		return nil, err
+	}
+	traceCoords, _, err := c.DecodeCoords(trace)
+	if err != nil {
+		return nil, err
+	}
+	return c.EncodeCoords(nil, SnapToRoute(routeCoords, traceCoords, corridorMeters)), nil
+}
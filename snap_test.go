@@ -0,0 +1,38 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapToRoute(t *testing.T) {
+	t.Parallel()
+	route := [][]float64{{0, 0}, {0.01, 0}, {0.01, 0.01}}
+	trace := [][]float64{
+		{0.002, 0.0001},
+		{0.008, -0.0001},
+		{0.0101, 0.004},
+		{0.012, 0.008}, // 222m off route.
+	}
+	got := polyline.SnapToRoute(route, trace, 50)
+	assertCoordsWithin(t, [][]float64{
+		{0.002, 0},
+		{0.008, 0},
+		{0.01, 0},
+		{0.01, 0.004},
+		{0.01045, 0.008},
+	}, got, 1e-5)
+
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	buf, err := codec.SnapToRoute(codec.EncodeCoords(nil, route), codec.EncodeCoords(nil, trace), 50)
+	assert.NoError(t, err)
+	assert.Equal(t, codec.EncodeCoords(nil, got), buf)
+
+	assertCoordsWithin(t, got, polyline.SnapToRouteWith(route, trace, 100, doubledModel{}), 1e-9)
+
+	_, err = codec.SnapToRoute([]byte("_"), nil, 50)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	assert.Nil(t, polyline.SnapToRoute(route[:1], trace, 50))
+}
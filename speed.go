@@ -0,0 +1,61 @@
+package polyline
+
+import (
+	"math"
+	"time"
+)
+
+// A SpeedViolation is a segment whose implied speed exceeds a limit.
+type SpeedViolation struct {
+	Index    int           // Index of the first vertex of the segment
+	Distance float64       // Length of the segment in meters
+	Duration time.Duration // Duration of the segment
+	Speed    float64       // Implied speed in meters per second, +Inf if Duration is not positive
+}
+
+// A SpeedReport summarizes the speed violations in a track.
+type SpeedReport struct {
+	Violations []SpeedViolation
+	Segments   int     // Number of segments checked
+	MaxSpeed   float64 // Maximum implied speed of any segment in meters per second
+}
+
+// OK returns whether the track had no violations.
+func (r SpeedReport) OK() bool {
+	return len(r.Violations) == 0
+}
+
+// CheckSpeed checks the speed implied by each segment of the timestamped
+// track coords, where times are the times of each coordinate, against
+// maxSpeed in meters per second, to detect teleporting GPS fixes. Segments
+// with a non-zero length and a non-positive duration have an infinite speed.
+// It returns ErrDimensionalMismatch if coords and times have different
+// lengths.
+func CheckSpeed(coords [][]float64, times []time.Time, maxSpeed float64) (SpeedReport, error) {
+	if len(coords) != len(times) {
+		return SpeedReport{}, ErrDimensionalMismatch
+	}
+	var report SpeedReport
+	for i := 1; i < len(coords); i++ {
+		report.Segments++
+		distance := haversine(coords[i-1], coords[i])
+		duration := times[i].Sub(times[i-1])
+		var speed float64
+		switch {
+		case duration > 0:
+			speed = distance / duration.Seconds()
+		case distance > 0:
+			speed = math.Inf(1)
+		}
+		report.MaxSpeed = math.Max(report.MaxSpeed, speed)
+		if speed > maxSpeed {
+			report.Violations = append(report.Violations, SpeedViolation{
+				Index:    i - 1,
+				Distance: distance,
+				Duration: duration,
+				Speed:    speed,
+			})
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,44 @@
+package polyline_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSpeed(t *testing.T) {
+	t.Parallel()
+	t0 := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+	coords := [][]float64{{0, 0}, {0.001, 0}, {0.1, 0}, {0.002, 0}, {0.002, 0}, {0.003, 0}}
+	times := []time.Time{
+		t0,
+		t0.Add(10 * time.Second),
+		t0.Add(20 * time.Second),
+		t0.Add(30 * time.Second),
+		t0.Add(30 * time.Second),
+		t0.Add(30 * time.Second),
+	}
+	report, err := polyline.CheckSpeed(coords, times, 50)
+	assert.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Equal(t, 5, report.Segments)
+	assert.True(t, math.IsInf(report.MaxSpeed, 1))
+	if assert.Len(t, report.Violations, 3) {
+		assert.Equal(t, 1, report.Violations[0].Index)
+		assert.InDelta(t, 1100.8, report.Violations[0].Speed, 0.1)
+		assert.Equal(t, 2, report.Violations[1].Index)
+		assert.Equal(t, 4, report.Violations[2].Index)
+		assert.True(t, math.IsInf(report.Violations[2].Speed, 1))
+	}
+
+	report, err = polyline.CheckSpeed(coords[:2], times[:2], 50)
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.InDelta(t, 11.1, report.MaxSpeed, 0.1)
+
+	_, err = polyline.CheckSpeed(coords, times[1:], 50)
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+}
@@ -0,0 +1,127 @@
+package polyline
+
+import "math"
+
+// Split splits the polyline buf at meters along it by great-circle distance
+// into two polylines, before and after, which both contain the point at the
+// split, interpolated within its segment and quantized. The first
+// coordinate of after is encoded absolutely, re-anchoring the deltas that
+// follow it, and the rest of buf is copied unchanged, so the remaining leg of
+// a route can be re-encoded without decoding and re-encoding all of it.
+// Distances are clamped to the length of the polyline. It returns ErrEmpty if
+// buf contains no coordinates.
+func (c Codec) Split(buf []byte, meters float64) (before, after []byte, err error) {
+	return c.SplitWith(buf, meters, Haversine)
+}
+
+// SplitWith splits the polyline buf at meters along it as Split does, with
+// distances measured with model. A nil model means Haversine.
+func (c Codec) SplitWith(buf []byte, meters float64, model DistanceModel) (before, after []byte, err error) {
+	if model == nil {
+		model = Haversine
+	}
+	if len(buf) == 0 {
+		return nil, nil, ErrEmpty
+	}
+	last, prevInts := make([]int, c.Dim), make([]int, c.Dim)
+	prev, coord := make([]float64, c.Dim), make([]float64, c.Dim)
+	var along float64
+	for i, offset := 0, 0; offset < len(buf); i++ {
+		start := offset
+		copy(prevInts, last)
+		for j := range coord {
+			k, rest, err := decodeInt(buf[offset:])
+			if err != nil {
+				return nil, nil, err
+			}
+			offset = len(buf) - len(rest)
+			last[j] += k
+			coord[j] = float64(last[j]) / c.scale(j)
+		}
+		if i == 0 {
+			prev, coord = coord, prev
+			continue
+		}
+		d := model.Distance(prev, coord)
+		if along+d < meters {
+			along += d
+			prev, coord = coord, prev
+			continue
+		}
+
+		var t float64
+		if d > 0 {
+			t = math.Max(0, meters-along) / d
+		}
+		split := make([]int, c.Dim)
+		for j, x := range interpolate(prev, coord, t) {
+			split[j] = c.quantize(j, x)
+		}
+		before = append([]byte(nil), buf[:start]...)
+		if !equalInts(split, prevInts) {
+			before = appendDeltas(before, split, prevInts)
+		}
+		after = appendDeltas(nil, split, nil)
+		if !equalInts(split, last) {
+			after = appendDeltas(after, last, split)
+		}
+		return before, append(after, buf[offset:]...), nil
+	}
+
+	// meters is at or beyond the end of buf.
+	return buf[:len(buf):len(buf)], appendDeltas(nil, last, nil), nil
+}
+
+// SplitAtPoint splits the polyline buf as Split does, at the point on it
+// nearest to p, for example to split a route where a vehicle left it. It
+// returns ErrEmpty if buf contains no coordinates.
+func (c Codec) SplitAtPoint(buf []byte, p []float64) (before, after []byte, err error) {
+	return c.SplitAtPointWith(buf, p, Haversine)
+}
+
+// SplitAtPointWith splits the polyline buf at the point on it nearest to p as
+// SplitAtPoint does, with distances measured with model. A nil model means
+// Haversine.
+func (c Codec) SplitAtPointWith(buf []byte, p []float64, model DistanceModel) (before, after []byte, err error) {
+	if model == nil {
+		model = Haversine
+	}
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.Transformer != nil {
+		p = c.toWGS84([][]float64{p})[0]
+	}
+	var meters float64
+	if len(coords) >= 2 {
+		cum := cumulativeDistancesWith(coords, model)
+		proj := projectOntoPath(coords, p, 0, len(coords)-1, nil)
+		meters = cum[proj.segment] + proj.t*(cum[proj.segment+1]-cum[proj.segment])
+	}
+	return c.SplitWith(buf, meters, model)
+}
+
+// appendDeltas appends the encoding of the differences between the quantized
+// coordinates coord and prev to buf, or of coord itself if prev is nil, and
+// returns the new buf.
+func appendDeltas(buf []byte, coord, prev []int) []byte {
+	for j, x := range coord {
+		if prev != nil {
+			x -= prev[j]
+		}
+		buf = encodeInt(buf, x)
+	}
+	return buf
+}
+
+// Split splits the polyline buf at meters along it using the default codec.
+func Split(buf []byte, meters float64) (before, after []byte, err error) {
+	return defaultCodec.Split(buf, meters)
+}
+
+// SplitAtPoint splits the polyline buf at the point on it nearest to p using
+// the default codec.
+func SplitAtPoint(buf []byte, p []float64) (before, after []byte, err error) {
+	return defaultCodec.SplitAtPoint(buf, p)
+}
@@ -0,0 +1,133 @@
+package polyline_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {0.01, 0}, {0.01, 0.01}, {0.02, 0.01}}
+	buf := polyline.EncodeCoords(coords)
+	segment, err := polyline.EncodedLength(polyline.EncodeCoords(coords[:2]))
+	assert.NoError(t, err)
+	for _, tc := range []struct {
+		name   string
+		meters float64
+		before [][]float64
+		after  [][]float64
+	}{
+		{
+			name:   "start",
+			meters: -1,
+			before: coords[:1],
+			after:  coords,
+		},
+		{
+			name:   "interior",
+			meters: 1.5 * segment,
+			before: [][]float64{{0, 0}, {0.01, 0}, {0.01, 0.005}},
+			after:  [][]float64{{0.01, 0.005}, {0.01, 0.01}, {0.02, 0.01}},
+		},
+		{
+			name:   "vertex",
+			meters: segment,
+			before: coords[:2],
+			after:  coords[1:],
+		},
+		{
+			name:   "end",
+			meters: 10 * segment,
+			before: coords,
+			after:  coords[3:],
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			before, after, err := polyline.Split(buf, tc.meters)
+			assert.NoError(t, err)
+			assert.Equal(t, string(polyline.EncodeCoords(tc.before)), string(before))
+			assert.Equal(t, string(polyline.EncodeCoords(tc.after)), string(after))
+		})
+	}
+
+	// The rest of the polyline is copied unchanged.
+	_, after, err := polyline.Split(buf, 0.5*segment)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(after, buf[len(polyline.EncodeCoords(coords[:2])):]))
+
+	before, after, err := polyline.DefaultCodec().SplitWith(buf, 3*segment, doubledModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, string(polyline.EncodeCoords([][]float64{{0, 0}, {0.01, 0}, {0.01, 0.005}})), string(before))
+	assert.Equal(t, string(polyline.EncodeCoords([][]float64{{0.01, 0.005}, {0.01, 0.01}, {0.02, 0.01}})), string(after))
+
+	before, after, err = polyline.Split(polyline.EncodeCoords(coords[:1]), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.EncodeCoords(coords[:1]), before)
+	assert.Equal(t, polyline.EncodeCoords(coords[:1]), after)
+
+	_, _, err = polyline.Split(nil, 10)
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+	_, _, err = polyline.Split([]byte("_p~iF~ps|U_ulLnnqC_"), 1e9)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
+
+func TestSplitAtPoint(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {0.01, 0}, {0.01, 0.01}, {0.02, 0.01}}
+	buf := polyline.EncodeCoords(coords)
+	for _, tc := range []struct {
+		name   string
+		p      []float64
+		before [][]float64
+		after  [][]float64
+	}{
+		{
+			name:   "start",
+			p:      []float64{-0.01, -0.01},
+			before: coords[:1],
+			after:  coords,
+		},
+		{
+			name:   "interior",
+			p:      []float64{0.0101, 0.005},
+			before: [][]float64{{0, 0}, {0.01, 0}, {0.01, 0.005}},
+			after:  [][]float64{{0.01, 0.005}, {0.01, 0.01}, {0.02, 0.01}},
+		},
+		{
+			name:   "vertex",
+			p:      []float64{0.011, -0.001},
+			before: coords[:2],
+			after:  coords[1:],
+		},
+		{
+			name:   "end",
+			p:      []float64{0.03, 0.01},
+			before: coords,
+			after:  coords[3:],
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			before, after, err := polyline.SplitAtPoint(buf, tc.p)
+			assert.NoError(t, err)
+			assert.Equal(t, string(polyline.EncodeCoords(tc.before)), string(before))
+			assert.Equal(t, string(polyline.EncodeCoords(tc.after)), string(after))
+		})
+	}
+
+	before, after, err := polyline.DefaultCodec().SplitAtPointWith(buf, []float64{0.0101, 0.005}, doubledModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, string(polyline.EncodeCoords([][]float64{{0, 0}, {0.01, 0}, {0.01, 0.005}})), string(before))
+	assert.Equal(t, string(polyline.EncodeCoords([][]float64{{0.01, 0.005}, {0.01, 0.01}, {0.02, 0.01}})), string(after))
+
+	_, _, err = polyline.SplitAtPoint(nil, []float64{0, 0})
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+	_, _, err = polyline.SplitAtPoint([]byte("_p~iF~ps|U_ulLnnqC_"), []float64{0, 0})
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
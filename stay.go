@@ -0,0 +1,85 @@
+package polyline
+
+import "time"
+
+// A Stay is a period during which a track stayed within a small radius.
+type Stay struct {
+	Start     int       // Index of the first coordinate of the stay
+	End       int       // Index after the last coordinate of the stay
+	Center    []float64 // Mean of the coordinates of the stay
+	Arrival   time.Time // Time of the first coordinate of the stay
+	Departure time.Time // Time of the last coordinate of the stay
+}
+
+// DetectStays returns the stays in coords, where times are the times of each
+// coordinate. A stay is a run of coordinates that are all within radius
+// meters of the first coordinate of the run and that span at least
+// minDuration. It returns ErrDimensionalMismatch if coords and times have
+// different lengths.
+func DetectStays(coords [][]float64, times []time.Time, radius float64, minDuration time.Duration) ([]Stay, error) {
+	if len(coords) != len(times) {
+		return nil, ErrDimensionalMismatch
+	}
+	var stays []Stay
+	for i := 0; i < len(coords); {
+		j := i + 1
+		for j < len(coords) && haversine(coords[i], coords[j]) <= radius {
+			j++
+		}
+		if times[j-1].Sub(times[i]) < minDuration {
+			i++
+			continue
+		}
+		center := make([]float64, len(coords[i]))
+		for _, coord := range coords[i:j] {
+			for k := range center {
+				center[k] += coord[k] / float64(j-i)
+			}
+		}
+		stays = append(stays, Stay{
+			Start:     i,
+			End:       j,
+			Center:    center,
+			Arrival:   times[i],
+			Departure: times[j-1],
+		})
+		i = j
+	}
+	return stays, nil
+}
+
+// CollapseStays returns copies of coords and times in which each stay, as
+// defined by DetectStays, is replaced by a single coordinate at its center
+// with its arrival time.
+func CollapseStays(coords [][]float64, times []time.Time, radius float64, minDuration time.Duration) ([][]float64, []time.Time, error) {
+	return replaceStays(coords, times, radius, minDuration, true)
+}
+
+// RemoveStays returns copies of coords and times with the coordinates of each
+// stay, as defined by DetectStays, removed.
+func RemoveStays(coords [][]float64, times []time.Time, radius float64, minDuration time.Duration) ([][]float64, []time.Time, error) {
+	return replaceStays(coords, times, radius, minDuration, false)
+}
+
+// replaceStays implements CollapseStays and RemoveStays.
+func replaceStays(coords [][]float64, times []time.Time, radius float64, minDuration time.Duration, collapse bool) ([][]float64, []time.Time, error) {
+	stays, err := DetectStays(coords, times, radius, minDuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	resultCoords := make([][]float64, 0, len(coords))
+	resultTimes := make([]time.Time, 0, len(times))
+	start := 0
+	for _, stay := range stays {
+		resultCoords = append(resultCoords, coords[start:stay.Start]...)
+		resultTimes = append(resultTimes, times[start:stay.Start]...)
+		if collapse {
+			resultCoords = append(resultCoords, stay.Center)
+			resultTimes = append(resultTimes, stay.Arrival)
+		}
+		start = stay.End
+	}
+	resultCoords = append(resultCoords, coords[start:]...)
+	resultTimes = append(resultTimes, times[start:]...)
+	return resultCoords, resultTimes, nil
+}
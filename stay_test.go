@@ -0,0 +1,60 @@
+package polyline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStays(t *testing.T) {
+	t.Parallel()
+	t0 := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+	coords := [][]float64{
+		{0, 0},
+		{0.01, 0},
+		{0.01, 0.0001},
+		{0.0101, 0},
+		{0.01, 0.0001},
+		{0.02, 0},
+		{0.03, 0},
+	}
+	times := []time.Time{
+		t0,
+		t0.Add(1 * time.Minute),
+		t0.Add(10 * time.Minute),
+		t0.Add(20 * time.Minute),
+		t0.Add(30 * time.Minute),
+		t0.Add(31 * time.Minute),
+		t0.Add(32 * time.Minute),
+	}
+
+	stays, err := polyline.DetectStays(coords, times, 50, 15*time.Minute)
+	assert.NoError(t, err)
+	if assert.Len(t, stays, 1) {
+		assert.Equal(t, 1, stays[0].Start)
+		assert.Equal(t, 5, stays[0].End)
+		assert.InDelta(t, 0.010025, stays[0].Center[0], 1e-9)
+		assert.InDelta(t, 0.00005, stays[0].Center[1], 1e-9)
+		assert.Equal(t, times[1], stays[0].Arrival)
+		assert.Equal(t, times[4], stays[0].Departure)
+	}
+
+	stays, err = polyline.DetectStays(coords, times, 50, time.Hour)
+	assert.NoError(t, err)
+	assert.Empty(t, stays)
+
+	gotCoords, gotTimes, err := polyline.CollapseStays(coords, times, 50, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, gotCoords, 4)
+	assert.Equal(t, []time.Time{times[0], times[1], times[5], times[6]}, gotTimes)
+
+	gotCoords, gotTimes, err = polyline.RemoveStays(coords, times, 50, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{0, 0}, {0.02, 0}, {0.03, 0}}, gotCoords)
+	assert.Equal(t, []time.Time{times[0], times[5], times[6]}, gotTimes)
+
+	_, err = polyline.DetectStays(coords, times[1:], 50, time.Minute)
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+}
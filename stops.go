@@ -0,0 +1,115 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrUnsnappable is returned by SnapStops when stops cannot be snapped to a
+// shape in order.
+var ErrUnsnappable = errors.New("stops cannot be snapped in order")
+
+// SnapStops snaps stops, in the order they are served, onto shape, returning
+// the position of each stop on shape. Positions never decrease along shape,
+// so a shape that passes a stop several times, such as a loop, is handled.
+// Among all such assignments with every stop within maxDistance meters of
+// its position, the one that minimizes the total distance from stops to
+// their positions is chosen. A maxDistance of zero means 100 meters. It
+// returns ErrUnsnappable, naming the first stop that cannot be placed, if
+// there is no such assignment.
+func SnapStops(shape, stops [][]float64, maxDistance float64) ([]RoutePosition, error) {
+	if len(shape) < 2 {
+		return nil, fmt.Errorf("%w: shape has %d points", ErrUnsnappable, len(shape))
+	}
+	if maxDistance == 0 {
+		maxDistance = 100
+	}
+	cum := cumulativeDistances(shape)
+
+	// candidates[s] holds the projections of stop s onto each segment within
+	// maxDistance, sorted by distance along shape.
+	candidates := make([][]RoutePosition, len(stops))
+	for s, stop := range stops {
+		for i := 0; i+1 < len(shape); i++ {
+			t, d := projectOntoSegment(stop, shape[i], shape[i+1])
+			if d > maxDistance {
+				continue
+			}
+			candidates[s] = append(candidates[s], RoutePosition{
+				Coord:    interpolate(shape[i], shape[i+1], t),
+				Segment:  i,
+				Along:    cum[i] + t*(cum[i+1]-cum[i]),
+				Distance: d,
+			})
+		}
+		sort.SliceStable(candidates[s], func(i, j int) bool {
+			return candidates[s][i].Along < candidates[s][j].Along
+		})
+	}
+
+	// cost[s][c] is the least total distance of an assignment of stops 0 to s
+	// with stop s at candidates[s][c], and from[s][c] the candidate of stop
+	// s-1 in that assignment.
+	cost := make([][]float64, len(stops))
+	from := make([][]int, len(stops))
+	for s := range stops {
+		cost[s] = make([]float64, len(candidates[s]))
+		from[s] = make([]int, len(candidates[s]))
+		var best float64
+		bestIndex, k := -1, 0
+		if s == 0 {
+			bestIndex = 0
+		}
+		for c, p := range candidates[s] {
+			if s > 0 {
+				// Advance k over the previous stop's candidates that are not
+				// after p, tracking the cheapest.
+				prev := candidates[s-1]
+				for ; k < len(prev) && prev[k].Along <= p.Along; k++ {
+					if !math.IsInf(cost[s-1][k], 1) && (bestIndex < 0 || cost[s-1][k] < best) {
+						best, bestIndex = cost[s-1][k], k
+					}
+				}
+			}
+			if bestIndex < 0 {
+				cost[s][c] = math.Inf(1)
+				continue
+			}
+			cost[s][c] = best + p.Distance
+			from[s][c] = bestIndex
+		}
+		feasible := false
+		for _, x := range cost[s] {
+			feasible = feasible || !math.IsInf(x, 1)
+		}
+		if !feasible {
+			return nil, fmt.Errorf("%w: stop %d", ErrUnsnappable, s)
+		}
+	}
+
+	positions := make([]RoutePosition, len(stops))
+	c := -1
+	for s := len(stops) - 1; s >= 0; s-- {
+		if c < 0 {
+			for i, x := range cost[s] {
+				if c < 0 || x < cost[s][c] {
+					c = i
+				}
+			}
+		}
+		positions[s] = candidates[s][c]
+		c = from[s][c]
+	}
+	return positions, nil
+}
+
+// SnapStops decodes a shape polyline and snaps stops onto it. See SnapStops.
+func (c Codec) SnapStops(buf []byte, stops [][]float64, maxDistance float64) ([]RoutePosition, error) {
+	shape, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	return SnapStops(shape, stops, maxDistance)
+}
@@ -0,0 +1,82 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapStops(t *testing.T) {
+	t.Parallel()
+	// The same out-and-back route as TestMatchProgress: north for 1112m, then
+	// back south on a parallel track 22m to the east.
+	shape := [][]float64{{0, 0}, {0.01, 0}, {0.01, 0.0002}, {0, 0.0002}}
+	for _, tc := range []struct {
+		name     string
+		stops    [][]float64
+		segments []int
+		along    []float64
+		err      error
+	}{
+		{
+			name:     "outbound_then_return",
+			stops:    [][]float64{{0.001, 0.00015}, {0.005, 0.00005}, {0.005, 0.00015}, {0.001, 0.00005}},
+			segments: []int{0, 0, 2, 2},
+			along:    []float64{111.2, 556.0, 1112.0 + 22.2 + 556.0, 1112.0 + 22.2 + 1000.8},
+		},
+		{
+			// Every stop is closest to the return leg, but the first must be
+			// on the outbound leg for the second to follow it.
+			name:     "order_wins",
+			stops:    [][]float64{{0.005, 0.00015}, {0.008, 0.00015}, {0.001, 0.00015}},
+			segments: []int{0, 2, 2},
+			along:    []float64{556.0, 1112.0 + 22.2 + 222.4, 1112.0 + 22.2 + 1000.8},
+		},
+		{
+			name:  "too_far",
+			stops: [][]float64{{0.005, 0}, {0.005, 0.01}},
+			err:   polyline.ErrUnsnappable,
+		},
+		{
+			// Only the outbound leg is within reach, and the stops go backwards.
+			name:  "out_of_order",
+			stops: [][]float64{{0.001, -0.0003}, {0.008, -0.0003}, {0.005, -0.0003}},
+			err:   polyline.ErrUnsnappable,
+		},
+		{
+			name: "no_stops",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			positions, err := polyline.SnapStops(shape, tc.stops, 50)
+			assert.ErrorIs(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+			assert.Len(t, positions, len(tc.stops))
+			for i, p := range positions {
+				assert.Equal(t, tc.segments[i], p.Segment)
+				assert.InDelta(t, tc.along[i], p.Along, 0.1)
+				assert.Less(t, p.Distance, 50.0)
+			}
+		})
+	}
+}
+
+func TestCodecSnapStops(t *testing.T) {
+	t.Parallel()
+	c := polyline.DefaultCodec()
+	buf := c.EncodeCoords(nil, [][]float64{{0, 0}, {0.01, 0}})
+	positions, err := c.SnapStops(buf, [][]float64{{0.005, 0.0001}}, 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, 556.0, positions[0].Along, 0.1)
+	assert.InDelta(t, 11.1, positions[0].Distance, 0.1)
+
+	_, err = polyline.SnapStops([][]float64{{0, 0}}, nil, 0)
+	assert.ErrorIs(t, err, polyline.ErrUnsnappable)
+	_, err = c.SnapStops([]byte{'_'}, nil, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package polyline
+
+// A Summary is a compact description of a polyline, small enough to store
+// and serve alongside the encoding so that lists of trips can be shown
+// without decoding every geometry.
+type Summary struct {
+	Start  []float64 `json:"start"`
+	End    []float64 `json:"end"`
+	Min    []float64 `json:"min"`    // Minimum of each dimension, the south-west corner of the bounding box
+	Max    []float64 `json:"max"`    // Maximum of each dimension, the north-east corner of the bounding box
+	Length float64   `json:"length"` // Length in meters
+	Points int       `json:"points"`
+}
+
+// Summarize decodes buf and returns its Summary. It returns ErrEmpty if buf
+// contains no coordinates.
+func (c Codec) Summarize(buf []byte) (Summary, error) {
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return Summary{}, err
+	}
+	if len(coords) == 0 {
+		return Summary{}, ErrEmpty
+	}
+	s := Summary{
+		Start:  cloneCoord(coords[0]),
+		End:    cloneCoord(coords[len(coords)-1]),
+		Min:    cloneCoord(coords[0]),
+		Max:    cloneCoord(coords[0]),
+		Length: pathLength(coords),
+		Points: len(coords),
+	}
+	for _, coord := range coords[1:] {
+		for j, x := range coord {
+			if x < s.Min[j] {
+				s.Min[j] = x
+			}
+			if x > s.Max[j] {
+				s.Max[j] = x
+			}
+		}
+	}
+	return s, nil
+}
+
+// Summarize decodes buf with the default codec and returns its Summary.
+func Summarize(buf []byte) (Summary, error) {
+	return defaultCodec.Summarize(buf)
+}
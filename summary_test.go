@@ -0,0 +1,34 @@
+package polyline_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	s, err := polyline.Summarize(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, polyline.Summary{
+		Start:  []float64{38.5, -120.2},
+		End:    []float64{43.252, -126.453},
+		Min:    []float64{38.5, -126.453},
+		Max:    []float64{43.252, -120.2},
+		Length: s.Length,
+		Points: 3,
+	}, s)
+	assert.InDelta(t, 789e3, s.Length, 1e3)
+
+	data, err := json.Marshal(s)
+	assert.NoError(t, err)
+	var got polyline.Summary
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, s, got)
+
+	_, err = polyline.Summarize([]byte{})
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+}
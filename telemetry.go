@@ -0,0 +1,75 @@
+package polyline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Keys of the attributes returned by Codec.Attributes.
+const (
+	AttrFingerprint = "polyline.fingerprint"
+	AttrBytes       = "polyline.bytes"
+	AttrPoints      = "polyline.points"
+	AttrBBox        = "polyline.bbox"
+	AttrPreview     = "polyline.preview"
+	AttrError       = "polyline.error"
+)
+
+// DefaultPreviewBytes is the length of the preview in Codec.Attributes when
+// none is given.
+const DefaultPreviewBytes = 32
+
+// An Attribute is a key and a value of type string, int, or []float64, the
+// types shared by OpenTelemetry attributes and structured logging fields.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Fingerprint returns a short hash of the polyline buf, the first eight
+// bytes of its SHA-256 digest in hex, for referring to a geometry in logs
+// and traces without including it.
+func Fingerprint(buf []byte) string {
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:8])
+}
+
+// preview returns at most n bytes of buf, followed by "..." if it is
+// truncated. Polylines contain no dots, so the marker is unambiguous.
+func preview(buf []byte, n int) string {
+	if len(buf) <= n {
+		return string(buf)
+	}
+	return string(buf[:n]) + "..."
+}
+
+// Attributes returns attributes describing the polyline buf whose total size
+// is bounded whatever the length of buf, for attaching to trace spans and log
+// records: its Fingerprint, its length in bytes, its number of points, its
+// bounding box as min latitude, min longitude, max latitude, and max
+// longitude, and its first previewBytes bytes. If previewBytes is zero then
+// DefaultPreviewBytes is used, and if it is negative the preview is omitted.
+// If buf does not decode then the points and bounding box are replaced by the
+// error. The codec must be at least two-dimensional.
+func (c Codec) Attributes(buf []byte, previewBytes int) []Attribute {
+	attrs := []Attribute{
+		{Key: AttrFingerprint, Value: Fingerprint(buf)},
+		{Key: AttrBytes, Value: len(buf)},
+	}
+	points, bbox, err := c.bounds(buf, nil)
+	switch {
+	case err != nil:
+		attrs = append(attrs, Attribute{Key: AttrError, Value: err.Error()})
+	case points == 0:
+		attrs = append(attrs, Attribute{Key: AttrPoints, Value: 0})
+	default:
+		attrs = append(attrs, Attribute{Key: AttrPoints, Value: points}, Attribute{Key: AttrBBox, Value: bbox})
+	}
+	if previewBytes == 0 {
+		previewBytes = DefaultPreviewBytes
+	}
+	if previewBytes > 0 {
+		attrs = append(attrs, Attribute{Key: AttrPreview, Value: preview(buf, previewBytes)})
+	}
+	return attrs
+}
@@ -0,0 +1,54 @@
+package polyline_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+	fp := polyline.Fingerprint([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+	assert.Len(t, fp, 16)
+	assert.Equal(t, fp, polyline.Fingerprint([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")))
+	assert.NotEqual(t, fp, polyline.Fingerprint([]byte("_p~iF~ps|U")))
+	assert.Equal(t, "e3b0c44298fc1c14", polyline.Fingerprint(nil))
+}
+
+func TestAttributes(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	buf := []byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	fp := polyline.Fingerprint(buf)
+	assert.Equal(t, []polyline.Attribute{
+		{Key: polyline.AttrFingerprint, Value: fp},
+		{Key: polyline.AttrBytes, Value: 27},
+		{Key: polyline.AttrPoints, Value: 3},
+		{Key: polyline.AttrBBox, Value: []float64{38.5, -126.453, 43.252, -120.2}},
+		{Key: polyline.AttrPreview, Value: "_p~iF~ps|U..."},
+	}, codec.Attributes(buf, 10))
+
+	attrs := codec.Attributes(buf, 0)
+	assert.Equal(t, polyline.Attribute{Key: polyline.AttrPreview, Value: string(buf)}, attrs[len(attrs)-1])
+	attrs = codec.Attributes(buf, -1)
+	assert.Equal(t, polyline.AttrBBox, attrs[len(attrs)-1].Key)
+
+	assert.Equal(t, []polyline.Attribute{
+		{Key: polyline.AttrFingerprint, Value: polyline.Fingerprint(nil)},
+		{Key: polyline.AttrBytes, Value: 0},
+		{Key: polyline.AttrPoints, Value: 0},
+	}, codec.Attributes(nil, -1))
+
+	attrs = codec.Attributes([]byte("_p~iF~ps|U_"), -1)
+	assert.Equal(t, polyline.AttrError, attrs[2].Key)
+	assert.Len(t, attrs, 3)
+
+	// The size of the attributes does not grow with the polyline.
+	long := polyline.EncodeCoords(benchmarkCoords(10000))
+	attrs = codec.Attributes(long, 0)
+	assert.Len(t, attrs, 5)
+	assert.Equal(t, polyline.DefaultPreviewBytes+len("..."), len(attrs[4].Value.(string)))
+	assert.True(t, strings.HasPrefix(string(long), strings.TrimSuffix(attrs[4].Value.(string), "...")))
+}
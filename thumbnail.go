@@ -0,0 +1,100 @@
+package polyline
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// A dpSpan is a span of points between two kept points, with the point
+// furthest from the line between them.
+type dpSpan struct {
+	first, last int
+	index       int
+	sqDist      float64
+}
+
+// dpQueue is a max-heap of spans ordered by their furthest point's distance.
+type dpQueue []dpSpan
+
+func (q dpQueue) Len() int            { return len(q) }
+func (q dpQueue) Less(i, j int) bool  { return q[i].sqDist > q[j].sqDist }
+func (q dpQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *dpQueue) Push(x interface{}) { *q = append(*q, x.(dpSpan)) }
+func (q *dpQueue) Pop() interface{} {
+	old := *q
+	s := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return s
+}
+
+// newDPSpan returns the span from first to last.
+func newDPSpan(points []Point, first, last int) dpSpan {
+	s := dpSpan{first: first, last: last, sqDist: -1}
+	for i := first + 1; i < last; i++ {
+		if sqDist := getSqSegDist(points[i], points[first], points[last]); sqDist > s.sqDist {
+			s.index = i
+			s.sqDist = sqDist
+		}
+	}
+	return s
+}
+
+// simplifyToCount returns the sorted indexes of at most n points of points,
+// including both ends, chosen in the order Douglas-Peucker would split them.
+func simplifyToCount(points []Point, n int) []int {
+	last := len(points) - 1
+	if last < 1 {
+		return []int{0}
+	}
+	indexes := []int{0, last}
+	q := dpQueue{newDPSpan(points, 0, last)}
+	for len(indexes) < n && len(q) > 0 {
+		s := heap.Pop(&q).(dpSpan)
+		if s.last-s.first < 2 {
+			continue
+		}
+		indexes = append(indexes, s.index)
+		heap.Push(&q, newDPSpan(points, s.first, s.index))
+		heap.Push(&q, newDPSpan(points, s.index, s.last))
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// Thumbnail decodes buf and returns an encoding of at most maxPoints of its
+// coordinates, always including both ends, for list views and previews. The
+// coordinates are those Douglas-Peucker would keep first. If maxPoints is
+// less than two then two is used. It returns ErrEmpty if buf contains no
+// coordinates.
+func (c Codec) Thumbnail(buf []byte, maxPoints int) ([]byte, error) {
+	c = c.untransformed()
+	coords, _, err := c.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(coords) == 0 {
+		return nil, ErrEmpty
+	}
+	if maxPoints < 2 {
+		maxPoints = 2
+	}
+	if len(coords) <= maxPoints {
+		return c.EncodeCoords(nil, coords), nil
+	}
+
+	points := make([]Point, len(coords))
+	for i, coord := range coords {
+		points[i] = ChartPoint{X: coord[1], Y: coord[0]}
+	}
+	indexes := simplifyToCount(points, maxPoints)
+	kept := make([][]float64, len(indexes))
+	for i, index := range indexes {
+		kept[i] = coords[index]
+	}
+	return c.EncodeCoords(make([]byte, 0, len(kept)*c.Dim*defaultBytesPerDim), kept), nil
+}
+
+// Thumbnail returns a thumbnail of buf using the default codec.
+func Thumbnail(buf []byte, maxPoints int) ([]byte, error) {
+	return defaultCodec.Thumbnail(buf, maxPoints)
+}
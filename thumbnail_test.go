@@ -0,0 +1,62 @@
+package polyline_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnail(t *testing.T) {
+	t.Parallel()
+	var sine [][]float64
+	for i := 0; i <= 200; i++ {
+		x := float64(i) / 100
+		sine = append(sine, []float64{math.Sin(x * math.Pi), x})
+	}
+	for _, tc := range []struct {
+		name      string
+		coords    [][]float64
+		maxPoints int
+		expected  [][]float64
+	}{
+		{
+			name:      "short",
+			coords:    [][]float64{{1, 1}, {2, 2}, {3, 1}},
+			maxPoints: 30,
+			expected:  [][]float64{{1, 1}, {2, 2}, {3, 1}},
+		},
+		{
+			name:      "ends",
+			coords:    sine,
+			maxPoints: 0,
+			expected:  [][]float64{{0, 0}, sine[200]},
+		},
+		{
+			name:      "peak",
+			coords:    sine,
+			maxPoints: 3,
+			expected:  [][]float64{{0, 0}, {1, 0.5}, sine[200]},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			thumb, err := polyline.Thumbnail(polyline.EncodeCoords(tc.coords), tc.maxPoints)
+			assert.NoError(t, err)
+			got, _, err := polyline.DecodeCoords(thumb)
+			assert.NoError(t, err)
+			assertCoordsWithin(t, tc.expected, got, 1e-5)
+		})
+	}
+
+	thumb, err := polyline.Thumbnail(polyline.EncodeCoords(sine), 30)
+	assert.NoError(t, err)
+	got, _, err := polyline.DecodeCoords(thumb)
+	assert.NoError(t, err)
+	assert.Len(t, got, 30)
+
+	_, err = polyline.Thumbnail(nil, 30)
+	assert.ErrorIs(t, err, polyline.ErrEmpty)
+}
@@ -0,0 +1,102 @@
+package polyline
+
+import (
+	"math"
+	"sort"
+)
+
+// A Tile is a Web Mercator map tile in the XYZ scheme, with X increasing
+// eastwards and Y southwards from the north-west corner of the map.
+type Tile struct {
+	Z, X, Y int
+}
+
+// maxMercatorLat is the latitude at which Web Mercator maps end.
+const maxMercatorLat = 85.0511287798066
+
+// tileY returns the unclamped Y coordinate, in tiles, of lat at zoom level
+// with n tiles across.
+func tileY(lat float64, n float64) float64 {
+	lat = radians(math.Max(-maxMercatorLat, math.Min(maxMercatorLat, lat)))
+	return (1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) / 2 * n
+}
+
+// CorridorTiles returns the tiles at each of zooms that intersect the
+// corridor of buffer meters either side of coords, for example to prefetch
+// the map along a route for offline navigation. The tiles are sorted by zoom,
+// then X, then Y, whatever the order of zooms, and repeated zooms add no
+// tiles. The corridor is approximated conservatively, so tiles near its edge
+// may be included unnecessarily but none are missed.
+func CorridorTiles(coords [][]float64, buffer float64, zooms []int) []Tile {
+	return CorridorTilesWith(coords, buffer, zooms, nil)
+}
+
+// CorridorTilesWith is like CorridorTiles but measures the route with
+// model.
+func CorridorTilesWith(coords [][]float64, buffer float64, zooms []int, model DistanceModel) []Tile {
+	if model == nil {
+		model = Haversine
+	}
+	set := make(map[Tile]struct{})
+	for _, z := range zooms {
+		n := math.Exp2(float64(z))
+		add := func(coord []float64, half float64) {
+			dLat := half / metersPerDegree
+			dLng := math.Min(180, dLat/math.Max(1e-9, math.Cos(radians(coord[0]))))
+			y0 := int(math.Max(0, tileY(coord[0]+dLat, n)))
+			y1 := int(math.Min(n-1, tileY(coord[0]-dLat, n)))
+			x0 := int(math.Floor((coord[1] - dLng + 180) / 360 * n))
+			x1 := int(math.Floor((coord[1] + dLng + 180) / 360 * n))
+			if x1-x0 >= int(n) {
+				x0, x1 = 0, int(n)-1
+			}
+			for x := x0; x <= x1; x++ {
+				for y := y0; y <= y1; y++ {
+					set[Tile{Z: z, X: (x%int(n) + int(n)) % int(n), Y: y}] = struct{}{}
+				}
+			}
+		}
+
+		step := math.Max(buffer, 2*math.Pi*earthRadius/n/64)
+		if len(coords) == 1 {
+			add(coords[0], buffer)
+		}
+		for i := 1; i < len(coords); i++ {
+			a, b := coords[i-1], coords[i]
+			samples := int(math.Ceil(model.Distance(a, b) / step))
+			if samples < 1 {
+				samples = 1
+			}
+			for k := 0; k <= samples; k++ {
+				add(interpolate(a, b, float64(k)/float64(samples)), buffer+step/2)
+			}
+		}
+	}
+
+	tiles := make([]Tile, 0, len(set))
+	for tile := range set {
+		tiles = append(tiles, tile)
+	}
+	sort.Slice(tiles, func(i, j int) bool {
+		a, b := tiles[i], tiles[j]
+		switch {
+		case a.Z != b.Z:
+			return a.Z < b.Z
+		case a.X != b.X:
+			return a.X < b.X
+		default:
+			return a.Y < b.Y
+		}
+	})
+	return tiles
+}
+
+// CorridorTiles decodes buf and returns the tiles at each of zooms that
+// intersect the corridor of buffer meters either side of it.
+func (c Codec) CorridorTiles(buf []byte, buffer float64, zooms []int) ([]Tile, error) {
+	coords, _, err := c.untransformed().DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	return CorridorTiles(coords, buffer, zooms), nil
+}
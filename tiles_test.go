@@ -0,0 +1,99 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorridorTiles(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		coords   [][]float64
+		buffer   float64
+		zooms    []int
+		expected []polyline.Tile
+	}{
+		{
+			name:     "world",
+			coords:   [][]float64{{51.5, -0.1}, {48.85, 2.35}},
+			buffer:   100,
+			zooms:    []int{0, 1},
+			expected: []polyline.Tile{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}},
+		},
+		{
+			name:     "zoom_order",
+			coords:   [][]float64{{51.5, -0.1}, {48.85, 2.35}},
+			buffer:   100,
+			zooms:    []int{1, 0, 1},
+			expected: []polyline.Tile{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}},
+		},
+		{
+			name:     "point",
+			coords:   [][]float64{{51.5, -0.1}},
+			buffer:   10,
+			zooms:    []int{10},
+			expected: []polyline.Tile{{10, 511, 340}},
+		},
+		{
+			name:     "point_buffer",
+			coords:   [][]float64{{51.5, -0.1}},
+			buffer:   30e3,
+			zooms:    []int{10},
+			expected: tileRange(10, 510, 512, 339, 341),
+		},
+		{
+			name:     "antimeridian",
+			coords:   [][]float64{{2, 179.99}, {2, -179.99}},
+			buffer:   10,
+			zooms:    []int{2},
+			expected: []polyline.Tile{{2, 0, 1}, {2, 3, 1}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tiles, err := polyline.DefaultCodec().CorridorTiles(polyline.EncodeCoords(tc.coords), tc.buffer, tc.zooms)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tiles)
+		})
+	}
+}
+
+func TestCorridorTilesCoverage(t *testing.T) {
+	t.Parallel()
+	// Every point within the buffer of a long diagonal route must be in a
+	// returned tile.
+	route := [][]float64{{45, 5}, {47, 9}}
+	assert.Equal(t, polyline.CorridorTiles(route, 2000, []int{12}), polyline.CorridorTilesWith(route, 2000, []int{12}, nil))
+	for _, model := range []polyline.DistanceModel{polyline.Haversine, polyline.Equirectangular, polyline.Vincenty} {
+		tiles := polyline.CorridorTilesWith(route, 2000, []int{12}, model)
+		set := make(map[polyline.Tile]bool)
+		for _, tile := range tiles {
+			set[tile] = true
+		}
+		for _, p := range polyline.Waypoints(route, 500) {
+			for _, offset := range [][]float64{{0.0179, 0}, {-0.0179, 0}, {0, 0.0254}, {0, -0.0254}} {
+				q := []float64{p[0] + offset[0], p[1] + offset[1]}
+				assert.True(t, set[tileAt(12, q)], "%v", q)
+			}
+		}
+	}
+}
+
+func tileRange(z, x0, x1, y0, y1 int) []polyline.Tile {
+	var tiles []polyline.Tile
+	for x := x0; x <= x1; x++ {
+		for y := y0; y <= y1; y++ {
+			tiles = append(tiles, polyline.Tile{Z: z, X: x, Y: y})
+		}
+	}
+	return tiles
+}
+
+func tileAt(z int, coord []float64) polyline.Tile {
+	tiles := polyline.CorridorTiles([][]float64{coord}, 0, []int{z})
+	return tiles[0]
+}
@@ -0,0 +1,90 @@
+package polyline
+
+import "math"
+
+// Translate returns a copy of coords moved by dLat degrees of latitude and
+// dLng degrees of longitude. Any further dimensions are copied unchanged.
+func Translate(coords [][]float64, dLat, dLng float64) [][]float64 {
+	result := make([][]float64, len(coords))
+	for i, coord := range coords {
+		result[i] = cloneCoord(coord)
+		result[i][0] += dLat
+		result[i][1] += dLng
+	}
+	return result
+}
+
+// RotateAround returns a copy of coords rotated clockwise by deg degrees
+// around center, as seen on a map with north up. The rotation is performed in
+// a local equirectangular projection about center, so shapes are preserved
+// for geometries that span a small fraction of the Earth. It returns nil if
+// center is at or beyond a pole, where the projection is undefined.
+func RotateAround(coords [][]float64, center []float64, deg float64) [][]float64 {
+	if math.Abs(center[0]) >= 90 {
+		return nil
+	}
+	sin, cos := math.Sincos(radians(deg))
+	k := math.Cos(radians(center[0]))
+	result := make([][]float64, len(coords))
+	for i, coord := range coords {
+		y := coord[0] - center[0]
+		x := (coord[1] - center[1]) * k
+		result[i] = cloneCoord(coord)
+		result[i][0] = center[0] + y*cos - x*sin
+		result[i][1] = center[1] + (x*cos+y*sin)/k
+	}
+	return result
+}
+
+// Scale returns a copy of coords scaled by factor about center. Any further
+// dimensions are copied unchanged.
+func Scale(coords [][]float64, center []float64, factor float64) [][]float64 {
+	result := make([][]float64, len(coords))
+	for i, coord := range coords {
+		result[i] = cloneCoord(coord)
+		result[i][0] = center[0] + factor*(coord[0]-center[0])
+		result[i][1] = center[1] + factor*(coord[1]-center[1])
+	}
+	return result
+}
+
+// SwapAxes returns a copy of coords with the first two dimensions exchanged,
+// to repair coordinates given as longitude, latitude. Any further dimensions
+// are copied unchanged.
+func SwapAxes(coords [][]float64) [][]float64 {
+	result := make([][]float64, len(coords))
+	for i, coord := range coords {
+		result[i] = cloneCoord(coord)
+		result[i][0], result[i][1] = coord[1], coord[0]
+	}
+	return result
+}
+
+// DetectSwappedAxes returns whether coords are certainly longitude, latitude
+// rather than latitude, longitude: some latitude is outside ±90 while every
+// latitude is within ±180 and every longitude is within ±90. Swapped
+// coordinates whose longitudes are all within ±90 cannot be detected.
+func DetectSwappedAxes(coords [][]float64) bool {
+	var outside bool
+	for _, coord := range coords {
+		lat, lng := math.Abs(coord[0]), math.Abs(coord[1])
+		if lat > 180 || lng > 90 {
+			return false
+		}
+		if lat > 90 {
+			outside = true
+		}
+	}
+	return outside
+}
+
+// Transform decodes buf, applies f to the decoded coordinates, and returns
+// the encoding of the result. It is typically used with Translate,
+// RotateAround, and Scale.
+func (c Codec) Transform(buf []byte, f func([][]float64) [][]float64) ([]byte, error) {
+	coords, _, err := c.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	return c.EncodeCoords(nil, f(coords)), nil
+}
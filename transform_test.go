@@ -0,0 +1,90 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertCoordsWithin(t *testing.T, want, got [][]float64, prec float64) {
+	t.Helper()
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i := range want {
+		assert.True(t, float64ArrayWithin(want[i], got[i], prec), "coordinate %d: want %v, got %v", i, want[i], got[i])
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{1, 2, 3}, {4, 5, 6}}
+	assert.Equal(t, [][]float64{{2, 4, 3}, {5, 7, 6}}, polyline.Translate(coords, 1, 2))
+	assert.Equal(t, [][]float64{{1, 2, 3}, {4, 5, 6}}, coords)
+}
+
+func TestRotateAround(t *testing.T) {
+	t.Parallel()
+	center := []float64{0, 0}
+	coords := [][]float64{{0, 0}, {1, 0}, {0, 1}}
+	assertCoordsWithin(t, [][]float64{{0, 0}, {0, 1}, {-1, 0}}, polyline.RotateAround(coords, center, 90), 1e-12)
+	assertCoordsWithin(t, [][]float64{{0, 0}, {-1, 0}, {0, -1}}, polyline.RotateAround(coords, center, 180), 1e-12)
+
+	// At 60°N a degree of longitude is half as long as a degree of latitude.
+	center = []float64{60, 10}
+	coords = [][]float64{{61, 10}}
+	assertCoordsWithin(t, [][]float64{{60, 12}}, polyline.RotateAround(coords, center, 90), 1e-9)
+
+	assert.Nil(t, polyline.RotateAround(coords, []float64{90, 0}, 90))
+	assert.Nil(t, polyline.RotateAround(coords, []float64{-90, 0}, 90))
+}
+
+func TestScale(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{1, 1}, {3, 5}}
+	assert.Equal(t, [][]float64{{1, 1}, {5, 9}}, polyline.Scale(coords, []float64{1, 1}, 2))
+}
+
+func TestSwapAxes(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{-120.2, 38.5, 7}, {-120.95, 40.7, 8}}
+	assert.Equal(t, [][]float64{{38.5, -120.2, 7}, {40.7, -120.95, 8}}, polyline.SwapAxes(coords))
+	assert.Equal(t, [][]float64{{-120.2, 38.5, 7}, {-120.95, 40.7, 8}}, coords)
+}
+
+func TestDetectSwappedAxes(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		coords   [][]float64
+		expected bool
+	}{
+		{name: "empty"},
+		{name: "correct", coords: [][]float64{{38.5, -120.2}, {40.7, -120.95}}},
+		{name: "swapped", coords: [][]float64{{-120.2, 38.5}, {-120.95, 40.7}}, expected: true},
+		{name: "ambiguous", coords: [][]float64{{7.0, 45.1}, {7.1, 45.2}}},
+		{name: "invalid", coords: [][]float64{{-120.2, 38.5}, {200, 40.7}}},
+		{name: "mixed", coords: [][]float64{{-120.2, 38.5}, {40.7, -120.95}}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, polyline.DetectSwappedAxes(tc.coords))
+		})
+	}
+}
+
+func TestCodecTransform(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	got, err := codec.Transform([]byte("_p~iF~ps|U_ulLnnqC_mqNvxq`@"), func(coords [][]float64) [][]float64 {
+		return polyline.Translate(coords, 1, 1)
+	})
+	assert.NoError(t, err)
+	want := polyline.EncodeCoords([][]float64{{39.5, -119.2}, {41.7, -119.95}, {44.252, -125.453}})
+	assert.Equal(t, want, got)
+
+	_, err = codec.Transform([]byte("_p~iF~ps|U_"), func(coords [][]float64) [][]float64 { return coords })
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
@@ -0,0 +1,300 @@
+// Package twkb converts between encoded polylines and Tiny Well-Known Binary
+// (TWKB) LineStrings. Like polylines, TWKB stores coordinates as scaled
+// integer deltas in zigzag varints, so geometries can be served in either
+// format without loss.
+//
+// See https://github.com/TWKB/Specification.
+package twkb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/sidsquare/go-polyline"
+)
+
+var (
+	// ErrInvalid is returned when the input is not a TWKB LineString.
+	ErrInvalid = errors.New("not a TWKB LineString")
+	// ErrPrecision is returned when a codec's scale is not a power of ten
+	// that TWKB can represent.
+	ErrPrecision = errors.New("unsupported precision")
+)
+
+// TWKB geometry types and metadata header flags.
+const (
+	typeLineString = 2
+
+	flagBBox          = 1 << 0
+	flagSize          = 1 << 1
+	flagIDList        = 1 << 2
+	flagExtendedDims  = 1 << 3
+	flagEmptyGeometry = 1 << 4
+)
+
+// Options configures Encode. Zero fields take default values.
+type Options struct {
+	BBox bool // Include a bounding box
+	Size bool // Include the size of the geometry body
+}
+
+// precision returns the TWKB precision of codec, the base ten logarithm of
+// its scale. Scales, if set, must be the same for every dimension, and Z and
+// M, whose precisions are unsigned, need a non-negative precision.
+func precision(codec polyline.Codec) (int, error) {
+	scale := codec.Scale
+	if codec.Scales != nil {
+		scale = codec.Scales[0]
+		for _, s := range codec.Scales[1:] {
+			if s != scale {
+				return 0, fmt.Errorf("%w: scales differ between dimensions", ErrPrecision)
+			}
+		}
+	}
+	p := math.Log10(scale)
+	switch {
+	case p != math.Trunc(p) || p < -8 || p > 7:
+		return 0, fmt.Errorf("%w: scale %g", ErrPrecision, scale)
+	case p < 0 && codec.Dim > 2:
+		return 0, fmt.Errorf("%w: scale %g for Z or M", ErrPrecision, scale)
+	}
+	return int(p), nil
+}
+
+// Encode returns coords, which must have codec's dimensionality of 2, 3 or
+// 4, as a TWKB LineString with the precision of codec's scale. A third
+// dimension is written as Z and a fourth as M, at the same precision. TWKB
+// coordinates are x (longitude) first, so the first two dimensions are
+// swapped. It returns ErrPrecision if codec's scale is not a power of ten
+// that TWKB can represent, if its Scales differ, or if a codec with Z or M
+// has a scale below one.
+func Encode(coords [][]float64, codec polyline.Codec, opts Options) ([]byte, error) {
+	if codec.Dim < 2 || codec.Dim > 4 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrInvalid, codec.Dim)
+	}
+	p, err := precision(codec)
+	if err != nil {
+		return nil, err
+	}
+	for i, coord := range coords {
+		if len(coord) != codec.Dim {
+			return nil, fmt.Errorf("%w: coordinate %d has %d values", polyline.ErrDimensionalMismatch, i, len(coord))
+		}
+	}
+
+	var metadata byte
+	if opts.BBox && len(coords) > 0 {
+		metadata |= flagBBox
+	}
+	if opts.Size {
+		metadata |= flagSize
+	}
+	if codec.Dim > 2 {
+		metadata |= flagExtendedDims
+	}
+	if len(coords) == 0 {
+		metadata |= flagEmptyGeometry
+	}
+	twkb := []byte{byte(zigzag(p))<<4 | typeLineString, metadata}
+	if codec.Dim > 2 {
+		// Z and M share the precision of x and y.
+		dims := byte(1 | (p&7)<<2)
+		if codec.Dim > 3 {
+			dims |= 2 | byte(p&7)<<5
+		}
+		twkb = append(twkb, dims)
+	}
+
+	quantized := make([][]int64, len(coords))
+	for i, coord := range polyline.SwapAxes(coords) {
+		quantized[i] = make([]int64, len(coord))
+		for j, x := range coord {
+			quantized[i][j] = int64(math.Round(x * math.Pow10(p)))
+		}
+	}
+	var body []byte
+	if metadata&flagBBox != 0 {
+		for j := 0; j < codec.Dim; j++ {
+			min, max := quantized[0][j], quantized[0][j]
+			for _, q := range quantized {
+				if q[j] < min {
+					min = q[j]
+				}
+				if q[j] > max {
+					max = q[j]
+				}
+			}
+			body = appendVarint(body, min)
+			body = appendVarint(body, max-min)
+		}
+	}
+	if len(coords) > 0 {
+		body = appendUvarint(body, uint64(len(coords)))
+		last := make([]int64, codec.Dim)
+		for _, q := range quantized {
+			for j, x := range q {
+				body = appendVarint(body, x-last[j])
+				last[j] = x
+			}
+		}
+	}
+	if opts.Size {
+		twkb = appendUvarint(twkb, uint64(len(body)))
+	}
+	return append(twkb, body...), nil
+}
+
+// Decode decodes a TWKB LineString and returns its coordinates, latitude
+// first, and its dimensionality.
+func Decode(twkb []byte) ([][]float64, int, error) {
+	if len(twkb) < 2 {
+		return nil, 0, fmt.Errorf("%w: %d bytes", ErrInvalid, len(twkb))
+	}
+	if typ := twkb[0] & 15; typ != typeLineString {
+		return nil, 0, fmt.Errorf("%w: type %d", ErrInvalid, typ)
+	}
+	scales := []float64{math.Pow10(int(unzigzag(uint64(twkb[0] >> 4))))}
+	metadata := twkb[1]
+	r := reader{buf: twkb[2:]}
+	if metadata&flagIDList != 0 {
+		return nil, 0, fmt.Errorf("%w: ID list on a LineString", ErrInvalid)
+	}
+	scales = append(scales, scales[0])
+	if metadata&flagExtendedDims != 0 {
+		dims := r.byte()
+		if dims&1 != 0 {
+			scales = append(scales, math.Pow10(int(dims>>2&7)))
+		}
+		if dims&2 != 0 {
+			scales = append(scales, math.Pow10(int(dims>>5&7)))
+		}
+	}
+	if metadata&flagSize != 0 {
+		if size := r.uvarint(); r.err == nil && size != uint64(len(r.buf)) {
+			return nil, 0, fmt.Errorf("%w: size %d, %d bytes remaining", ErrInvalid, size, len(r.buf))
+		}
+	}
+	dim := len(scales)
+	if metadata&flagEmptyGeometry != 0 {
+		return nil, dim, r.err
+	}
+	if metadata&flagBBox != 0 {
+		for j := 0; j < 2*dim; j++ {
+			r.varint()
+		}
+	}
+	n := r.uvarint()
+	if r.err == nil && n > uint64(len(r.buf)) {
+		return nil, 0, fmt.Errorf("%w: %d points in %d bytes", ErrInvalid, n, len(r.buf))
+	}
+	coords := make([][]float64, 0, n)
+	last := make([]int64, dim)
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		coord := make([]float64, dim)
+		for j := range coord {
+			last[j] += r.varint()
+			coord[j] = float64(last[j]) / scales[j]
+		}
+		coord[0], coord[1] = coord[1], coord[0]
+		coords = append(coords, coord)
+	}
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+	return coords, dim, nil
+}
+
+// FromPolyline converts a polyline decoded with codec to a TWKB LineString.
+// See Encode.
+func FromPolyline(buf []byte, codec polyline.Codec, opts Options) ([]byte, error) {
+	coords, _, err := codec.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(coords, codec, opts)
+}
+
+// ToPolyline converts a TWKB LineString to a polyline encoded with codec. It
+// returns polyline.ErrDimensionalMismatch if the LineString does not have
+// codec's dimensionality.
+func ToPolyline(twkb []byte, codec polyline.Codec) ([]byte, error) {
+	coords, dim, err := Decode(twkb)
+	if err != nil {
+		return nil, err
+	}
+	if dim != codec.Dim {
+		return nil, fmt.Errorf("%w: %d dimensions", polyline.ErrDimensionalMismatch, dim)
+	}
+	return codec.EncodeCoords(nil, coords), nil
+}
+
+// zigzag and unzigzag map signed integers to and from unsigned integers so
+// that values of small magnitude have short encodings.
+func zigzag(i int) uint64 {
+	return uint64(i<<1) ^ uint64(i>>63)
+}
+
+func unzigzag(u uint64) int64 {
+	if u&1 != 0 {
+		return -int64(u>>1) - 1
+	}
+	return int64(u >> 1)
+}
+
+// appendVarint and appendUvarint append zigzag and unsigned varints to dst.
+func appendVarint(dst []byte, v int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(dst, b[:binary.PutVarint(b[:], v)]...)
+}
+
+func appendUvarint(dst []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(dst, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+// A reader reads from buf, recording the first error.
+type reader struct {
+	buf []byte
+	err error
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) == 0 {
+		r.err = fmt.Errorf("%w: truncated", ErrInvalid)
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *reader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = fmt.Errorf("%w: truncated", ErrInvalid)
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = fmt.Errorf("%w: truncated", ErrInvalid)
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
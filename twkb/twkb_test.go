@@ -0,0 +1,149 @@
+package twkb_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/sidsquare/go-polyline/twkb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	for _, tc := range []struct {
+		name     string
+		codec    polyline.Codec
+		coords   [][]float64
+		opts     twkb.Options
+		expected string
+		err      error
+	}{
+		{
+			// SELECT ST_AsTWKB('LINESTRING(1 1,5 5)'::geometry)
+			name:     "postgis",
+			codec:    codec.WithScale(1),
+			coords:   [][]float64{{1, 1}, {5, 5}},
+			expected: "02000202020808",
+		},
+		{
+			name:     "precision",
+			codec:    codec,
+			coords:   [][]float64{{38.5, -120.2}, {40.7, -120.95}},
+			expected: "a20002" + "bfa4bb0b" + "a0fcd503" + "ef9309" + "c0ed1a",
+		},
+		{
+			name:     "bbox_size",
+			codec:    codec.WithScale(1),
+			coords:   [][]float64{{1, 1}, {5, 5}},
+			opts:     twkb.Options{BBox: true, Size: true},
+			expected: "02030902080208" + "0202020808",
+		},
+		{
+			name:     "z",
+			codec:    codec.WithScale(10).WithDim(3),
+			coords:   [][]float64{{1, 2, 3}},
+			expected: "22080501" + "28143c",
+		},
+		{
+			name:     "empty",
+			codec:    codec,
+			opts:     twkb.Options{BBox: true, Size: true},
+			expected: "a21200",
+		},
+		{
+			name:   "scale",
+			codec:  codec.WithScale(3),
+			coords: [][]float64{{1, 1}},
+			err:    twkb.ErrPrecision,
+		},
+		{
+			name:   "negative_z",
+			codec:  polyline.Codec{Dim: 3, Scale: 1e-2},
+			coords: [][]float64{{100, 200, 300}},
+			err:    twkb.ErrPrecision,
+		},
+		{
+			name:     "scales",
+			codec:    codec.WithDim(3).WithScales(10, 10, 10),
+			coords:   [][]float64{{1, 2, 3}},
+			expected: "22080501" + "28143c",
+		},
+		{
+			name:   "mixed_scales",
+			codec:  codec.WithDim(3).WithScales(1e5, 1e5, 10),
+			coords: [][]float64{{1, 2, 3}},
+			err:    twkb.ErrPrecision,
+		},
+		{
+			name:   "dimension",
+			codec:  codec,
+			coords: [][]float64{{1, 1, 1}},
+			err:    polyline.ErrDimensionalMismatch,
+		},
+		{
+			name:  "codec_dimension",
+			codec: codec.WithDim(5),
+			err:   twkb.ErrInvalid,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			buf, err := twkb.Encode(tc.coords, tc.codec, tc.opts)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, hex.EncodeToString(buf))
+			if tc.err != nil {
+				return
+			}
+			coords, dim, err := twkb.Decode(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.codec.Dim, dim)
+			assert.Equal(t, tc.coords, coords)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		hex  string
+	}{
+		{name: "short", hex: "02"},
+		{name: "point", hex: "01000202"},
+		{name: "id_list", hex: "020402020202"},
+		{name: "size", hex: "0202060202020808"},
+		{name: "truncated", hex: "020002020208"},
+		{name: "points", hex: "0200ff01"},
+		{name: "extended_dims", hex: "0208"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			buf, err := hex.DecodeString(tc.hex)
+			assert.NoError(t, err)
+			_, _, err = twkb.Decode(buf)
+			assert.ErrorIs(t, err, twkb.ErrInvalid)
+		})
+	}
+}
+
+func TestPolyline(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	const line = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+	buf, err := twkb.FromPolyline([]byte(line), codec, twkb.Options{BBox: true})
+	assert.NoError(t, err)
+	got, err := twkb.ToPolyline(buf, codec)
+	assert.NoError(t, err)
+	assert.Equal(t, line, string(got))
+
+	_, err = twkb.ToPolyline(buf, codec.WithDim(3))
+	assert.ErrorIs(t, err, polyline.ErrDimensionalMismatch)
+	_, err = twkb.ToPolyline([]byte{1}, codec)
+	assert.ErrorIs(t, err, twkb.ErrInvalid)
+	_, err = twkb.FromPolyline([]byte("_"), codec, twkb.Options{})
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+}
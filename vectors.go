@@ -0,0 +1,112 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrIncompatible is returned by VerifyCompatibility when an implementation
+// does not reproduce a test vector.
+var ErrIncompatible = errors.New("incompatible")
+
+// A Vector is a canonical test vector for the default codec: Encoded is the
+// exact encoding of Coords, and decoding Encoded yields Coords.
+type Vector struct {
+	Name    string
+	Encoded string
+	Coords  [][]float64
+}
+
+// A CodecLike encodes and decodes coordinates. Codec implements CodecLike, and
+// implementations in other languages can be verified by wrapping them in a
+// CodecLike.
+type CodecLike interface {
+	EncodeCoords(buf []byte, coords [][]float64) []byte
+	DecodeCoords(buf []byte) ([][]float64, []byte, error)
+}
+
+// Vectors returns the canonical test vectors for the default codec. The
+// vectors are deterministic and a fresh copy is returned on each call.
+func Vectors() []Vector {
+	return []Vector{
+		{
+			Name: "empty",
+		},
+		{
+			Name:    "origin",
+			Encoded: "??",
+			Coords:  [][]float64{{0, 0}},
+		},
+		{
+			Name:    "single",
+			Encoded: "_p~iF~ps|U",
+			Coords:  [][]float64{{38.5, -120.2}},
+		},
+		{
+			Name:    "google",
+			Encoded: "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			Coords:  [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}},
+		},
+		{
+			Name:    "extremes",
+			Encoded: "_cidP_gsia@~fsia@~ngtcA",
+			Coords:  [][]float64{{90, 180}, {-90, -180}},
+		},
+		{
+			Name:    "quanta",
+			Encoded: "??A@@A",
+			Coords:  [][]float64{{0, 0}, {1e-05, -1e-05}, {0, 0}},
+		},
+		{
+			Name:    "repeated",
+			Encoded: "b_vmEaa|y[??CA",
+			Coords:  [][]float64{{-33.86882, 151.20929}, {-33.86882, 151.20929}, {-33.8688, 151.2093}},
+		},
+		{
+			Name:    "long_deltas",
+			Encoded: "keeyHvbyAr{~N_pdPgojU_wmbAw~vR{}wrC",
+			Coords:  [][]float64{{51.4775, -0.4614}, {48.8566, 2.3522}, {52.52, 13.405}, {55.7558, 37.6173}},
+		},
+	}
+}
+
+// VerifyCompatibility checks that c reproduces every vector returned by
+// Vectors byte for byte when encoding, and to within 1e-9 when decoding. It
+// returns an error wrapping ErrIncompatible describing the first mismatch.
+func VerifyCompatibility(c CodecLike) error {
+	for _, v := range Vectors() {
+		if got := string(c.EncodeCoords(nil, v.Coords)); got != v.Encoded {
+			return fmt.Errorf("%w: %s: encoded %q, want %q", ErrIncompatible, v.Name, got, v.Encoded)
+		}
+		got, rest, err := c.DecodeCoords([]byte(v.Encoded))
+		switch {
+		case err != nil:
+			return fmt.Errorf("%w: %s: %v", ErrIncompatible, v.Name, err)
+		case len(rest) != 0:
+			return fmt.Errorf("%w: %s: %d unconsumed bytes", ErrIncompatible, v.Name, len(rest))
+		case len(got) != len(v.Coords):
+			return fmt.Errorf("%w: %s: decoded %d coordinates, want %d", ErrIncompatible, v.Name, len(got), len(v.Coords))
+		}
+		for i, coord := range got {
+			if !coordsWithin(coord, v.Coords[i], 1e-9) {
+				return fmt.Errorf("%w: %s: coordinate %d decoded as %v, want %v", ErrIncompatible, v.Name, i, coord, v.Coords[i])
+			}
+		}
+	}
+	return nil
+}
+
+// coordsWithin returns whether a and b have the same dimension and all their
+// components are within tolerance of each other.
+func coordsWithin(a, b []float64, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
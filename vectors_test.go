@@ -0,0 +1,39 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+type offByOneCodec struct {
+	polyline.Codec
+}
+
+func (c offByOneCodec) EncodeCoords(buf []byte, coords [][]float64) []byte {
+	buf = c.Codec.EncodeCoords(buf, coords)
+	if len(buf) > 0 {
+		buf[len(buf)-1]++
+	}
+	return buf
+}
+
+func TestVectors(t *testing.T) {
+	t.Parallel()
+	for _, v := range polyline.Vectors() {
+		got, rest, err := polyline.DecodeCoords([]byte(v.Encoded))
+		assert.NoError(t, err)
+		assert.Empty(t, rest)
+		assert.Equal(t, v.Coords, got)
+		assert.Equal(t, v.Encoded, string(polyline.EncodeCoords(v.Coords)))
+	}
+}
+
+func TestVerifyCompatibility(t *testing.T) {
+	t.Parallel()
+	codec := polyline.Codec{Dim: 2, Scale: 1e5}
+	assert.NoError(t, polyline.VerifyCompatibility(codec))
+	assert.ErrorIs(t, polyline.VerifyCompatibility(offByOneCodec{codec}), polyline.ErrIncompatible)
+	assert.ErrorIs(t, polyline.VerifyCompatibility(polyline.Codec{Dim: 2, Scale: 1e6}), polyline.ErrIncompatible)
+}
@@ -0,0 +1,42 @@
+package polyline
+
+import (
+	"fmt"
+	"math"
+)
+
+// VerifyRoundTrip encodes and decodes coords with c and returns an error
+// wrapping ErrPrecisionLoss that reports the first value that did not
+// survive to within half a quantum, with the index of its coordinate and
+// dimension and how far it moved, for example as a guardrail when ingesting
+// data. This also catches values that are not finite or that overflow when
+// scaled. It returns an error wrapping ErrDimensionalMismatch if a
+// coordinate does not have c.Dim values. With a Transformer, values are
+// compared in c's CRS, where a quantum does not map onto a fixed distance,
+// so VerifyRoundTrip is only meaningful for codecs without one. For an audit
+// with exact arithmetic see AuditPrecision.
+func VerifyRoundTrip(coords [][]float64, c Codec) error {
+	for i, coord := range coords {
+		if len(coord) != c.Dim {
+			return fmt.Errorf("%w: coordinate %d has %d values", ErrDimensionalMismatch, i, len(coord))
+		}
+	}
+	decoded, _, err := c.DecodeCoords(c.EncodeCoords(nil, coords))
+	if err != nil {
+		return err
+	}
+	if len(decoded) != len(coords) {
+		return fmt.Errorf("%w: %d coordinates decoded, want %d", ErrPrecisionLoss, len(decoded), len(coords))
+	}
+	for i, coord := range coords {
+		for j, x := range coord {
+			// Allow a little more than half a quantum for floating point
+			// error in scaling.
+			delta := decoded[i][j] - x
+			if !(math.Abs(delta)*c.scale(j) <= 0.5000001) {
+				return fmt.Errorf("%w: coordinate %d value %d: %g decoded as %g, off by %g", ErrPrecisionLoss, i, j, x, decoded[i][j], delta)
+			}
+		}
+	}
+	return nil
+}
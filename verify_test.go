@@ -0,0 +1,71 @@
+package polyline_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		coords   [][]float64
+		codec    polyline.Codec
+		expected error
+		message  string
+	}{
+		{
+			name:   "exact",
+			coords: [][]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}},
+			codec:  polyline.DefaultCodec(),
+		},
+		{
+			name:   "quantized",
+			coords: [][]float64{{38.123456, -120.987654}, {-0.000005, 179.999995}},
+			codec:  polyline.DefaultCodec(),
+		},
+		{
+			name:     "overflow",
+			coords:   [][]float64{{0, 0}, {1e15, 0}},
+			codec:    polyline.Codec7,
+			expected: polyline.ErrPrecisionLoss,
+			message:  "precision loss: coordinate 1 value 0: ",
+		},
+		{
+			name:     "nan",
+			coords:   [][]float64{{0, 0}, {1, math.NaN()}},
+			codec:    polyline.DefaultCodec(),
+			expected: polyline.ErrPrecisionLoss,
+			message:  "precision loss: coordinate 1 value 1: NaN decoded as ",
+		},
+		{
+			name:     "coalesced",
+			coords:   [][]float64{{1, 2}, {1.000001, 2}},
+			codec:    polyline.Codec{Dim: 2, Scale: 1e5, CoalesceQuantumDuplicates: true},
+			expected: polyline.ErrPrecisionLoss,
+			message:  "precision loss: 1 coordinates decoded, want 2",
+		},
+		{
+			name:     "dimensions",
+			coords:   [][]float64{{1, 2}, {3}},
+			codec:    polyline.DefaultCodec(),
+			expected: polyline.ErrDimensionalMismatch,
+			message:  "dimensional mismatch: coordinate 1 has 1 values",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := polyline.VerifyRoundTrip(tc.coords, tc.codec)
+			if tc.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.expected)
+			assert.Contains(t, err.Error(), tc.message)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package polyline
+
+import (
+	"math"
+	"sort"
+)
+
+// NearestVertex returns the index of the vertex of coords nearest to p and
+// its distance in meters, or -1 and +Inf if coords is empty. Ties go to the
+// lowest index. It scans every vertex; to answer repeated queries against
+// the same coords, build a VertexIndex.
+func NearestVertex(coords [][]float64, p []float64) (int, float64) {
+	best, bestDistance := -1, math.Inf(1)
+	for i, coord := range coords {
+		if d := haversine(p, coord); d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best, bestDistance
+}
+
+// A VertexIndex answers nearest vertex queries against a fixed set of
+// coordinates faster than NearestVertex, for example when dragging a point
+// along a route in an interactive editor. It orders the vertices by
+// latitude and searches outwards from the query's latitude, stopping once
+// the latitude difference alone exceeds the best distance found.
+type VertexIndex struct {
+	coords [][]float64
+	order  []int // Indexes of coords ordered by latitude, then index
+}
+
+// NewVertexIndex returns a new VertexIndex of coords. The index refers to
+// coords, which must not be modified while it is in use.
+func NewVertexIndex(coords [][]float64) *VertexIndex {
+	order := make([]int, len(coords))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return coords[order[i]][0] < coords[order[j]][0]
+	})
+	return &VertexIndex{
+		coords: coords,
+		order:  order,
+	}
+}
+
+// NearestVertex returns the same result as the package-level NearestVertex
+// for the indexed coords.
+func (ix *VertexIndex) NearestVertex(p []float64) (int, float64) {
+	best, bestDistance := -1, math.Inf(1)
+	consider := func(k int) bool {
+		i := ix.order[k]
+		coord := ix.coords[i]
+		// The great circle distance is at least the distance along the
+		// meridian.
+		if math.Abs(coord[0]-p[0])*metersPerDegree > bestDistance {
+			return false
+		}
+		if d := haversine(p, coord); d < bestDistance || d == bestDistance && i < best {
+			best, bestDistance = i, d
+		}
+		return true
+	}
+	mid := sort.Search(len(ix.order), func(k int) bool {
+		return ix.coords[ix.order[k]][0] >= p[0]
+	})
+	for lo, hi := mid-1, mid; lo >= 0 || hi < len(ix.order); {
+		if hi < len(ix.order) && !consider(hi) {
+			hi = len(ix.order)
+		} else {
+			hi++
+		}
+		if lo >= 0 && !consider(lo) {
+			lo = -1
+		} else {
+			lo--
+		}
+	}
+	return best, bestDistance
+}
@@ -0,0 +1,72 @@
+package polyline_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestVertex(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {0, 1}, {1, 1}, {0, 1}}
+	for _, tc := range []struct {
+		name     string
+		coords   [][]float64
+		p        []float64
+		expected int
+	}{
+		{name: "vertex", coords: coords, p: []float64{1, 1}, expected: 2},
+		{name: "near", coords: coords, p: []float64{0.1, -0.2}, expected: 0},
+		{name: "tie", coords: coords, p: []float64{0, 1}, expected: 1},
+		{name: "empty", p: []float64{0, 0}, expected: -1},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			i, d := polyline.NearestVertex(tc.coords, tc.p)
+			assert.Equal(t, tc.expected, i)
+			j, e := polyline.NewVertexIndex(tc.coords).NearestVertex(tc.p)
+			assert.Equal(t, i, j)
+			assert.Equal(t, d, e)
+			if i < 0 {
+				assert.True(t, math.IsInf(d, 1))
+			}
+		})
+	}
+}
+
+func TestVertexIndexMatchesLinearScan(t *testing.T) {
+	t.Parallel()
+	coords := benchmarkCoords(1000)
+	ix := polyline.NewVertexIndex(coords)
+	r := rand.New(rand.NewSource(1))
+	for k := 0; k < 1000; k++ {
+		p := []float64{44.9 + 0.2*r.Float64(), 6.9 + 0.2*r.Float64()}
+		i, d := polyline.NearestVertex(coords, p)
+		j, e := ix.NearestVertex(p)
+		assert.Equal(t, i, j)
+		assert.Equal(t, d, e)
+	}
+}
+
+func BenchmarkNearestVertex(b *testing.B) {
+	coords := benchmarkCoords(4096)
+	p := []float64{45.01, 7.01}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = polyline.NearestVertex(coords, p)
+	}
+}
+
+func BenchmarkVertexIndexNearestVertex(b *testing.B) {
+	coords := benchmarkCoords(4096)
+	ix := polyline.NewVertexIndex(coords)
+	p := []float64{45.01, 7.01}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ix.NearestVertex(p)
+	}
+}
@@ -0,0 +1,94 @@
+package polyline
+
+import "container/heap"
+
+// A SimplifyAlgorithm selects how SimplifyWith chooses the points to keep.
+type SimplifyAlgorithm int
+
+const (
+	// DouglasPeucker keeps every point further than Tolerance from the line
+	// between the points kept on either side of it. It is the default.
+	DouglasPeucker SimplifyAlgorithm = iota
+	// VisvalingamWhyatt repeatedly removes the point forming the smallest
+	// triangle with its neighbors, while that area is at most Tolerance
+	// squared. It removes small wiggles more evenly than DouglasPeucker and
+	// gives visually smoother lines for cartographic display.
+	VisvalingamWhyatt
+)
+
+// A vwVertex is an interior point of a line being simplified with
+// Visvalingam-Whyatt, with its effective area when it was queued.
+type vwVertex struct {
+	index int
+	area  float64
+}
+
+// vwQueue is a min-heap of vertices ordered by their effective area.
+type vwQueue []vwVertex
+
+func (q vwQueue) Len() int { return len(q) }
+func (q vwQueue) Less(i, j int) bool {
+	if q[i].area != q[j].area {
+		return q[i].area < q[j].area
+	}
+	return q[i].index < q[j].index
+}
+func (q vwQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *vwQueue) Push(x interface{}) { *q = append(*q, x.(vwVertex)) }
+func (q *vwQueue) Pop() interface{} {
+	old := *q
+	v := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return v
+}
+
+// simplifyVisvalingam removes the points of points with the smallest
+// effective areas while those areas are at most maxArea. A point's effective
+// area never falls below that of a point removed before it, so that removing
+// one point cannot cause a less significant neighbor to be kept.
+func simplifyVisvalingam(points []Point, maxArea float64) []Point {
+	n := len(points)
+	prev := make([]int, n)
+	next := make([]int, n)
+	areas := make([]float64, n)
+	q := make(vwQueue, 0, n-2)
+	for i := range points {
+		prev[i], next[i] = i-1, i+1
+		if 0 < i && i < n-1 {
+			areas[i] = triangleArea(points[i], points[i-1], points[i+1])
+			q = append(q, vwVertex{index: i, area: areas[i]})
+		}
+	}
+	heap.Init(&q)
+
+	removed := make([]bool, n)
+	for len(q) > 0 {
+		v := heap.Pop(&q).(vwVertex)
+		if removed[v.index] || v.area != areas[v.index] {
+			continue // Stale entry
+		}
+		if v.area > maxArea {
+			break
+		}
+		removed[v.index] = true
+		p, nx := prev[v.index], next[v.index]
+		next[p], prev[nx] = nx, p
+		for _, i := range [2]int{p, nx} {
+			if i == 0 || i == n-1 {
+				continue
+			}
+			area := triangleArea(points[i], points[prev[i]], points[next[i]])
+			if area < v.area {
+				area = v.area
+			}
+			areas[i] = area
+			heap.Push(&q, vwVertex{index: i, area: area})
+		}
+	}
+
+	simplified := make([]Point, 0, n)
+	for i := 0; i < n; i = next[i] {
+		simplified = append(simplified, points[i])
+	}
+	return simplified
+}
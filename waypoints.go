@@ -0,0 +1,26 @@
+package polyline
+
+// Waypoints returns n points evenly spaced by length along coords, including
+// both endpoints. It returns nil if n is less than one or coords is empty,
+// and the first point of coords if n is one.
+func Waypoints(coords [][]float64, n int) [][]float64 {
+	return WaypointsWith(coords, n, Haversine)
+}
+
+// WaypointsWith returns n points evenly spaced along coords as Waypoints
+// does, with lengths measured with model. A nil model means Haversine.
+func WaypointsWith(coords [][]float64, n int, model DistanceModel) [][]float64 {
+	if n < 1 || len(coords) == 0 {
+		return nil
+	}
+	if n == 1 {
+		return [][]float64{cloneCoord(coords[0])}
+	}
+	cum := cumulativeDistancesWith(coords, model)
+	total := cum[len(cum)-1]
+	result := make([][]float64, n)
+	for i := range result {
+		result[i], _ = pointAtDistance(coords, cum, total*float64(i)/float64(n-1))
+	}
+	return result
+}
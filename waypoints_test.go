@@ -0,0 +1,23 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaypoints(t *testing.T) {
+	t.Parallel()
+	coords := [][]float64{{0, 0}, {0.003, 0}, {0.004, 0}, {0.01, 0}}
+	assertCoordsWithin(t, [][]float64{{0, 0}, {0.0025, 0}, {0.005, 0}, {0.0075, 0}, {0.01, 0}}, polyline.Waypoints(coords, 5), 1e-9)
+	assertCoordsWithin(t, [][]float64{{0, 0}, {0.01, 0}}, polyline.Waypoints(coords, 2), 1e-12)
+	assert.Equal(t, [][]float64{{0, 0}}, polyline.Waypoints(coords, 1))
+	assert.Nil(t, polyline.Waypoints(coords, 0))
+	assert.Nil(t, polyline.Waypoints(nil, 3))
+	assert.Equal(t, [][]float64{{1, 2}, {1, 2}, {1, 2}}, polyline.Waypoints([][]float64{{1, 2}}, 3))
+
+	corner := [][]float64{{0, 0}, {0, 1}, {1, 1}}
+	assertCoordsWithin(t, [][]float64{{0, 0}, {0, 1}, {1, 1}}, polyline.WaypointsWith(corner, 3, nil), 1e-9)
+	assertCoordsWithin(t, [][]float64{{0, 0}, {0.5, 1}, {1, 1}}, polyline.WaypointsWith(corner, 3, latitudeModel{}), 1e-9)
+}
@@ -0,0 +1,147 @@
+package polyline
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrWKB is returned by Codec.EncodeWKB when the input is not a WKB
+// LineString.
+var ErrWKB = errors.New("not a WKB LineString")
+
+// WKB geometry type codes and EWKB flags.
+const (
+	wkbLineString = 2
+	wkbISOZ       = 1000
+	wkbISOM       = 2000
+	ewkbZ         = 0x80000000
+	ewkbM         = 0x40000000
+	ewkbSRID      = 0x20000000
+)
+
+// DecodeWKB decodes buf and returns it as a little-endian WKB LineString.
+// WKB coordinates are x first, so for a codec without a Transformer the first
+// two dimensions are swapped to longitude, latitude; a Transformer's
+// coordinates are written in the order of its CRS. Codecs with three or four
+// dimensions produce LineString Z and LineString ZM. If srid is zero the
+// result is ISO WKB; otherwise it is PostGIS EWKB carrying srid, which must be
+// the EPSG code of the codec's CRS, 4326 for WGS84.
+func (c Codec) DecodeWKB(buf []byte, srid uint32) ([]byte, error) {
+	if c.Dim < 2 || c.Dim > 4 {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrWKB, c.Dim)
+	}
+	if srid != 0 && srid != c.srid() {
+		return nil, fmt.Errorf("%w: SRID %d for CRS %q", ErrWKB, srid, c.CRS)
+	}
+	coords, _, err := c.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	typ := uint32(wkbLineString)
+	if srid == 0 {
+		typ += [...]uint32{0, 0, wkbISOZ, wkbISOZ + wkbISOM}[c.Dim-1]
+	} else {
+		typ |= [...]uint32{0, 0, ewkbZ, ewkbZ | ewkbM}[c.Dim-1] | ewkbSRID
+	}
+	wkb := make([]byte, 0, 13+8*c.Dim*len(coords))
+	wkb = append(wkb, 1) // Little endian
+	wkb = appendUint32(wkb, typ)
+	if srid != 0 {
+		wkb = appendUint32(wkb, srid)
+	}
+	wkb = appendUint32(wkb, uint32(len(coords)))
+	for _, coord := range c.xyAxes(coords) {
+		for _, v := range coord {
+			wkb = appendUint64(wkb, math.Float64bits(v))
+		}
+	}
+	return wkb, nil
+}
+
+// EncodeWKB appends the encoding of a WKB or EWKB LineString, in either byte
+// order and with positions ordered as DecodeWKB writes them, to buf. An EWKB
+// SRID, if present, must be the EPSG code of the codec's CRS, 4326 for WGS84.
+// It returns ErrWKB if
+// wkb is not a LineString and ErrDimensionalMismatch if its dimensionality is
+// not the codec's.
+func (c Codec) EncodeWKB(buf, wkb []byte) ([]byte, error) {
+	if len(wkb) < 9 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrWKB, len(wkb))
+	}
+	var order binary.ByteOrder
+	switch wkb[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return nil, fmt.Errorf("%w: byte order %d", ErrWKB, wkb[0])
+	}
+	typ := order.Uint32(wkb[1:])
+	wkb = wkb[5:]
+	dim := 2
+	if typ&ewkbZ != 0 {
+		dim++
+	}
+	if typ&ewkbM != 0 {
+		dim++
+	}
+	if typ&ewkbSRID != 0 {
+		if srid := order.Uint32(wkb); srid == 0 || srid != c.srid() {
+			return nil, fmt.Errorf("%w: SRID %d", ErrWKB, srid)
+		}
+		wkb = wkb[4:]
+	}
+	typ &^= ewkbZ | ewkbM | ewkbSRID
+	switch typ / 1000 {
+	case 1, 2:
+		dim++
+	case 3:
+		dim += 2
+	}
+	if typ%1000 != wkbLineString || typ >= 4000 {
+		return nil, fmt.Errorf("%w: type %d", ErrWKB, typ)
+	}
+	if dim != c.Dim {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrDimensionalMismatch, dim)
+	}
+	if len(wkb) < 4 {
+		return nil, fmt.Errorf("%w: truncated", ErrWKB)
+	}
+	n := int(order.Uint32(wkb))
+	wkb = wkb[4:]
+	if len(wkb) != 8*dim*n {
+		return nil, fmt.Errorf("%w: %d bytes for %d points", ErrWKB, len(wkb), n)
+	}
+	coords := make([][]float64, n)
+	for i := range coords {
+		coords[i] = make([]float64, dim)
+		for j := range coords[i] {
+			coords[i][j] = math.Float64frombits(order.Uint64(wkb))
+			wkb = wkb[8:]
+		}
+	}
+	return c.EncodeCoords(buf, c.xyAxes(coords)), nil
+}
+
+// appendUint16, appendUint32, and appendUint64 append little-endian
+// integers to dst.
+func appendUint16(dst []byte, v uint16) []byte {
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], v)
+	return append(dst, b[:]...)
+}
+
+func appendUint32(dst []byte, v uint32) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return append(dst, b[:]...)
+}
+
+func appendUint64(dst []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(dst, b[:]...)
+}
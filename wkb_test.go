@@ -0,0 +1,119 @@
+package polyline_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// LINESTRING(0 0,1 1) as WKB, and as EWKB with SRID 4326.
+	wkbHex  = "01020000000200000000000000000000000000000000000000000000000000F03F000000000000F03F"
+	ewkbHex = "0102000020E61000000200000000000000000000000000000000000000000000000000F03F000000000000F03F"
+)
+
+func TestDecodeWKB(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	buf := codec.EncodeCoords(nil, [][]float64{{0, 0}, {1, 1}})
+
+	wkb, err := codec.DecodeWKB(buf, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, wkbHex, strings.ToUpper(hex.EncodeToString(wkb)))
+
+	wkb, err = codec.DecodeWKB(buf, 4326)
+	assert.NoError(t, err)
+	assert.Equal(t, ewkbHex, strings.ToUpper(hex.EncodeToString(wkb)))
+
+	codec3 := polyline.Codec{Dim: 3, Scale: 1e5}
+	wkb, err = codec3.DecodeWKB(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}}), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "01ea03000001000000", hex.EncodeToString(wkb[:9]))
+	wkb, err = codec3.DecodeWKB(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}}), 4326)
+	assert.NoError(t, err)
+	assert.Equal(t, "01020000a0e6100000", hex.EncodeToString(wkb[:9]))
+
+	wkb, err = codec.DecodeWKB(nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "010200000000000000", hex.EncodeToString(wkb))
+
+	// A Transformer's coordinates are already x, y.
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	buf = codec.EncodeCoords(nil, [][]float64{{0, 1}})
+	wkb, err = mercator.DecodeWKB(buf, 3857)
+	assert.NoError(t, err)
+	assert.Equal(t, "0102000020110f000001000000", hex.EncodeToString(wkb[:13]))
+	assert.InDelta(t, 111319.49, math.Float64frombits(binary.LittleEndian.Uint64(wkb[13:])), 0.01)
+	assert.Zero(t, math.Float64frombits(binary.LittleEndian.Uint64(wkb[21:])))
+	back, err := mercator.EncodeWKB(nil, wkb)
+	assert.NoError(t, err)
+	assert.Equal(t, string(buf), string(back))
+	_, err = mercator.DecodeWKB(buf, 4326)
+	assert.ErrorIs(t, err, polyline.ErrWKB)
+	ewkb, err := hex.DecodeString(ewkbHex)
+	assert.NoError(t, err)
+	_, err = mercator.EncodeWKB(nil, ewkb)
+	assert.ErrorIs(t, err, polyline.ErrWKB)
+
+	_, err = codec.DecodeWKB([]byte("_"), 0)
+	assert.ErrorIs(t, err, polyline.ErrUnterminatedSequence)
+	_, err = polyline.Codec{Dim: 5, Scale: 1e5}.DecodeWKB(nil, 0)
+	assert.ErrorIs(t, err, polyline.ErrWKB)
+}
+
+func TestEncodeWKB(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	codec3 := polyline.Codec{Dim: 3, Scale: 1e5}
+	codec4 := polyline.Codec{Dim: 4, Scale: 1e5}
+	line := string(codec.EncodeCoords(nil, [][]float64{{0, 0}, {1, 1}}))
+	for _, tc := range []struct {
+		name     string
+		codec    polyline.Codec
+		hex      string
+		expected string
+		err      error
+	}{
+		{name: "wkb", codec: codec, hex: wkbHex, expected: line},
+		{name: "ewkb", codec: codec, hex: ewkbHex, expected: line},
+		{
+			name:     "big_endian",
+			codec:    codec,
+			hex:      "000000000200000002" + "00000000000000000000000000000000" + "3FF00000000000003FF0000000000000",
+			expected: line,
+		},
+		{
+			name:     "iso_z",
+			codec:    codec3,
+			hex:      "01EA03000001000000" + "0000000000000040" + "000000000000F03F" + "0000000000003E40",
+			expected: string(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}})),
+		},
+		{
+			name:     "ewkb_zm",
+			codec:    codec4,
+			hex:      "01020000C000000000",
+			expected: "",
+		},
+		{name: "srid", codec: codec, hex: "0102000020346C000000000000", err: polyline.ErrWKB},
+		{name: "point", codec: codec, hex: "0101000000000000000000000000000000000000", err: polyline.ErrWKB},
+		{name: "byte_order", codec: codec, hex: "020200000000000000", err: polyline.ErrWKB},
+		{name: "short", codec: codec, hex: "0102", err: polyline.ErrWKB},
+		{name: "truncated", codec: codec, hex: wkbHex[:len(wkbHex)-2], err: polyline.ErrWKB},
+		{name: "dimensions", codec: codec, hex: "01EA03000000000000", err: polyline.ErrDimensionalMismatch},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			wkb, err := hex.DecodeString(tc.hex)
+			assert.NoError(t, err)
+			buf, err := tc.codec.EncodeWKB(nil, wkb)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, string(buf))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrWKT is returned by Codec.EncodeWKT when the input is not a WKT
+// LineString.
+var ErrWKT = errors.New("not a WKT LineString")
+
+// wktTags maps dimensionalities to the WKT dimension tags that follow the
+// LINESTRING keyword.
+var wktTags = map[int]string{2: "", 3: "Z", 4: "ZM"}
+
+// DecodeWKT decodes buf and returns it as a WKT LineString, for example
+// "LINESTRING(-120.2 38.5,-120.95 40.7)". WKT coordinates are x first, so
+// for a codec without a Transformer the first two dimensions are swapped to
+// longitude, latitude; a Transformer's coordinates are written in the order
+// of its CRS. Codecs with three or four
+// dimensions produce LINESTRING Z and LINESTRING ZM. Values are written with
+// digits decimal places; a negative digits uses as many as the codec's
+// scale for each dimension preserves, trimming trailing zeros. An empty
+// polyline is "LINESTRING EMPTY".
+func (c Codec) DecodeWKT(buf []byte, digits int) ([]byte, error) {
+	tag, ok := wktTags[c.Dim]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d dimensions", ErrWKT, c.Dim)
+	}
+	coords, _, err := c.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
+	wkt := []byte("LINESTRING")
+	if tag != "" {
+		wkt = append(wkt, ' ')
+		wkt = append(wkt, tag...)
+	}
+	if len(coords) == 0 {
+		return append(wkt, " EMPTY"...), nil
+	}
+	trim := digits < 0
+	places := make([]int, c.Dim)
+	for j := range places {
+		places[j] = digits
+		if trim {
+			places[j] = int(math.Ceil(math.Log10(c.scale(j))))
+		}
+	}
+	if c.Dim >= 2 && c.Transformer == nil {
+		places[0], places[1] = places[1], places[0]
+	}
+	wkt = append(wkt, '(')
+	for i, coord := range c.xyAxes(coords) {
+		if i > 0 {
+			wkt = append(wkt, ',')
+		}
+		for j, v := range coord {
+			if j > 0 {
+				wkt = append(wkt, ' ')
+			}
+			wkt = appendWKTFloat(wkt, v, places[j], trim)
+		}
+	}
+	return append(wkt, ')'), nil
+}
+
+// appendWKTFloat appends v with digits decimal places, trimming trailing
+// zeros if trim is set.
+func appendWKTFloat(dst []byte, v float64, digits int, trim bool) []byte {
+	n := len(dst)
+	dst = strconv.AppendFloat(dst, v, 'f', digits, 64)
+	if trim && digits > 0 {
+		for dst[len(dst)-1] == '0' {
+			dst = dst[:len(dst)-1]
+		}
+		if dst[len(dst)-1] == '.' {
+			dst = dst[:len(dst)-1]
+		}
+	}
+	if string(dst[n:]) == "-0" {
+		dst = append(dst[:n], '0')
+	}
+	return dst
+}
+
+// EncodeWKT appends the encoding of a WKT LineString, with positions
+// ordered as DecodeWKT writes them, to buf. Keywords are case insensitive,
+// and an EWKT "SRID=n;" prefix is accepted if n is the EPSG code of the
+// codec's CRS, 4326 for WGS84. The
+// dimension tag, if present, must match the codec's dimensionality. It
+// returns ErrWKT if wkt is not a LineString and ErrDimensionalMismatch if a
+// position does not have the codec's dimensionality.
+func (c Codec) EncodeWKT(buf, wkt []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(wkt))
+	if i := strings.IndexByte(s, ';'); i >= 0 && strings.HasPrefix(strings.ToUpper(s), "SRID=") {
+		if srid := s[len("SRID="):i]; c.srid() == 0 || srid != strconv.FormatUint(uint64(c.srid()), 10) {
+			return nil, fmt.Errorf("%w: SRID %s", ErrWKT, srid)
+		}
+		s = strings.TrimSpace(s[i+1:])
+	}
+	if !strings.HasPrefix(strings.ToUpper(s), "LINESTRING") {
+		return nil, fmt.Errorf("%w: %.20q", ErrWKT, s)
+	}
+	s = strings.TrimSpace(s[len("LINESTRING"):])
+	body := strings.TrimLeft(s, "ZzMm ")
+	if tag := strings.ToUpper(strings.ReplaceAll(s[:len(s)-len(body)], " ", "")); tag != "" {
+		if want, ok := wktTags[c.Dim]; !ok || tag != want {
+			return nil, fmt.Errorf("%w: LINESTRING %s for %d dimensions", ErrDimensionalMismatch, tag, c.Dim)
+		}
+	}
+	if strings.EqualFold(body, "EMPTY") {
+		return buf, nil
+	}
+	if !strings.HasPrefix(body, "(") || !strings.HasSuffix(body, ")") {
+		return nil, fmt.Errorf("%w: missing parentheses", ErrWKT)
+	}
+	var coords [][]float64
+	for i, position := range strings.Split(body[1:len(body)-1], ",") {
+		fields := strings.Fields(position)
+		if len(fields) != c.Dim {
+			return nil, fmt.Errorf("%w: position %d has %d values", ErrDimensionalMismatch, i, len(fields))
+		}
+		coord := make([]float64, c.Dim)
+		for j, field := range fields {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: position %d: %v", ErrWKT, i, err)
+			}
+			coord[j] = v
+		}
+		coords = append(coords, coord)
+	}
+	return c.EncodeCoords(buf, c.xyAxes(coords)), nil
+}
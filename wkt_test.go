@@ -0,0 +1,183 @@
+package polyline_test
+
+import (
+	"testing"
+
+	"github.com/sidsquare/go-polyline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeWKT(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	codec3 := polyline.Codec{Dim: 3, Scale: 1e5}
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	for _, tc := range []struct {
+		name     string
+		codec    polyline.Codec
+		buf      string
+		digits   int
+		expected string
+		err      error
+	}{
+		{
+			name:     "trimmed",
+			codec:    codec,
+			buf:      "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			digits:   -1,
+			expected: "LINESTRING(-120.2 38.5,-120.95 40.7,-126.453 43.252)",
+		},
+		{
+			name:     "digits",
+			codec:    codec,
+			buf:      "_p~iF~ps|U",
+			digits:   2,
+			expected: "LINESTRING(-120.20 38.50)",
+		},
+		{
+			name:     "zero",
+			codec:    codec,
+			buf:      "??",
+			digits:   -1,
+			expected: "LINESTRING(0 0)",
+		},
+		{
+			name:     "z",
+			codec:    codec3,
+			buf:      string(codec3.EncodeCoords(nil, [][]float64{{1, 2, 30}})),
+			digits:   -1,
+			expected: "LINESTRING Z(2 1 30)",
+		},
+		{
+			name:     "empty",
+			codec:    codec,
+			digits:   -1,
+			expected: "LINESTRING EMPTY",
+		},
+		{
+			name:     "transformer",
+			codec:    mercator,
+			buf:      string(codec.EncodeCoords(nil, [][]float64{{0, 1}})),
+			digits:   2,
+			expected: "LINESTRING(111319.49 0.00)",
+		},
+		{
+			name:  "unterminated",
+			codec: codec,
+			buf:   "_",
+			err:   polyline.ErrUnterminatedSequence,
+		},
+		{
+			name:  "dimensions",
+			codec: polyline.Codec{Dim: 5, Scale: 1e5},
+			err:   polyline.ErrWKT,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			wkt, err := tc.codec.DecodeWKT([]byte(tc.buf), tc.digits)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, string(wkt))
+		})
+	}
+}
+
+func TestEncodeWKT(t *testing.T) {
+	t.Parallel()
+	codec := polyline.DefaultCodec()
+	mercator := polyline.Codec{Dim: 2, Scale: 1e5, CRS: "EPSG:3857", Transformer: polyline.WebMercator}
+	for _, tc := range []struct {
+		name     string
+		codec    polyline.Codec
+		wkt      string
+		expected string
+		err      error
+	}{
+		{
+			name:     "linestring",
+			codec:    codec,
+			wkt:      "LINESTRING(-120.2 38.5,-120.95 40.7,-126.453 43.252)",
+			expected: "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+		},
+		{
+			name:     "lenient",
+			codec:    codec,
+			wkt:      " SRID=4326;linestring ( -120.2  38.5 , -120.95 40.7 )\n",
+			expected: "_p~iF~ps|U_ulLnnqC",
+		},
+		{
+			name:     "z",
+			codec:    polyline.Codec{Dim: 3, Scale: 1e5},
+			wkt:      "LINESTRING Z (2 1 30)",
+			expected: "_ibE_seK_kbvD",
+		},
+		{
+			name:  "empty",
+			codec: codec,
+			wkt:   "LINESTRING EMPTY",
+		},
+		{
+			name:  "point",
+			codec: codec,
+			wkt:   "POINT(-120.2 38.5)",
+			err:   polyline.ErrWKT,
+		},
+		{
+			name:  "srid",
+			codec: codec,
+			wkt:   "SRID=27700;LINESTRING(0 0)",
+			err:   polyline.ErrWKT,
+		},
+		{
+			name:     "transformer",
+			codec:    mercator,
+			wkt:      "SRID=3857;LINESTRING(111319.49079327357 0)",
+			expected: string(codec.EncodeCoords(nil, [][]float64{{0, 1}})),
+		},
+		{
+			name:  "transformer_srid",
+			codec: mercator,
+			wkt:   "SRID=4326;LINESTRING(0 0)",
+			err:   polyline.ErrWKT,
+		},
+		{
+			name:  "unknown_crs_srid",
+			codec: polyline.Codec{Dim: 2, Scale: 1e5, CRS: "local", Transformer: polyline.WebMercator},
+			wkt:   "SRID=0;LINESTRING(0 0)",
+			err:   polyline.ErrWKT,
+		},
+		{
+			name:  "parentheses",
+			codec: codec,
+			wkt:   "LINESTRING(0 0",
+			err:   polyline.ErrWKT,
+		},
+		{
+			name:  "number",
+			codec: codec,
+			wkt:   "LINESTRING(0 x)",
+			err:   polyline.ErrWKT,
+		},
+		{
+			name:  "tag",
+			codec: codec,
+			wkt:   "LINESTRING Z (0 0 0)",
+			err:   polyline.ErrDimensionalMismatch,
+		},
+		{
+			name:  "position",
+			codec: codec,
+			wkt:   "LINESTRING(0 0,1)",
+			err:   polyline.ErrDimensionalMismatch,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			buf, err := tc.codec.EncodeWKT(nil, []byte(tc.wkt))
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, string(buf))
+		})
+	}
+}